@@ -2,31 +2,59 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/cybre/home-inventory/internal/infrastructure"
+	appaccount "github.com/cybre/home-inventory/services/inventory/app/account"
+	appactivity "github.com/cybre/home-inventory/services/inventory/app/activity"
+	appcatalog "github.com/cybre/home-inventory/services/inventory/app/catalog"
+	appexport "github.com/cybre/home-inventory/services/inventory/app/export"
 	apphousehold "github.com/cybre/home-inventory/services/inventory/app/household"
+	applabel "github.com/cybre/home-inventory/services/inventory/app/label"
+	apptaxonomy "github.com/cybre/home-inventory/services/inventory/app/taxonomy"
 	"github.com/cybre/home-inventory/services/inventory/domain/household"
+	"github.com/cybre/home-inventory/services/inventory/domain/taxonomy"
 
-	"github.com/cybre/home-inventory/internal/cassandra"
 	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/health"
+	"github.com/cybre/home-inventory/internal/infrastructure"
 	"github.com/cybre/home-inventory/internal/logging"
+	"github.com/cybre/home-inventory/internal/tracing"
 	httptransport "github.com/cybre/home-inventory/services/inventory/transport/http"
 	kafkatransport "github.com/cybre/home-inventory/services/inventory/transport/kafka"
+	"github.com/go-playground/validator/v10"
 )
 
 var (
 	kafkaBrokers   = strings.Split(os.Getenv("KAFKA_BROKERS"), ",")
 	cassandraHosts = strings.Split(os.Getenv("CASSANDRA_HOSTS"), ",")
 	serverAddress  = os.Getenv("SERVER_ADDRESS")
+
+	// KAFKA_CONSUMER_WORKERS is the number of events each projector handles in parallel, never more than one per aggregate.
+	kafkaConsumerWorkers = getEnvIntOrDefault("KAFKA_CONSUMER_WORKERS", 4)
+
+	// STORAGE_DRIVER selects between Cassandra with Kafka (the default) and a single SQLite file.
+	storageDriver = os.Getenv("STORAGE_DRIVER")
+	sqlitePath    = getEnvOrDefault("SQLITE_PATH", "inventory.db")
+
+	productLookupURL   = os.Getenv("PRODUCT_LOOKUP_URL")
+	productCatalogSeed = os.Getenv("PRODUCT_CATALOG_SEED")
 )
 
+// processManagers coordinate the workflows spanning several aggregates. Workflows such as moving a room between
+// households add theirs here once their commands exist.
+var processManagers []es.ProcessManager
+
 const (
 	eventsTopic = "inventory.events"
 	serviceName = "inventory"
+
+	commandTimeout = 10 * time.Second
 )
 
 func main() {
@@ -38,17 +66,21 @@ func main() {
 
 	ctx = logging.WithLogger(ctx, logger)
 
-	cassandraSession, err := cassandra.NewSession(cassandraHosts, serviceName)
+	shutdownTracing, err := tracing.Setup(ctx, serviceName)
 	if err != nil {
 		panic(err)
 	}
-	defer cassandraSession.Close()
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("failed to shut down tracing", slog.Any("error", err))
+		}
+	}()
 
-	eventMessaging, err := infrastructure.NewKafkaEventMessaging(kafkaBrokers, eventsTopic, logger)
+	storage, err := newStorage(storageDriver, logger)
 	if err != nil {
 		panic(err)
 	}
-	defer eventMessaging.Close()
+	defer storage.close()
 
 	es.RegisterAggregateRoot(household.HouseholdAggregateType, household.NewHouseholdAggregate)
 	es.RegisterEvent(household.HouseholdCreatedEvent{})
@@ -58,20 +90,120 @@ func main() {
 	es.RegisterEvent(household.RoomUpdatedEvent{})
 	es.RegisterEvent(household.RoomDeletedEvent{})
 
-	eventStore, err := infrastructure.NewCassandraEventStore(cassandraSession)
-	if err != nil {
-		panic(err)
+	es.RegisterAggregateRoot(taxonomy.TaxonomyAggregateType, taxonomy.NewTaxonomyAggregate)
+	es.RegisterEvent(taxonomy.CategoryCreatedEvent{})
+	es.RegisterEvent(taxonomy.CategoryRenamedEvent{})
+	es.RegisterEvent(taxonomy.CategoryMergedEvent{})
+	es.RegisterEvent(taxonomy.CategoryDeletedEvent{})
+	es.RegisterEvent(taxonomy.CategoryFieldAddedEvent{})
+	es.RegisterEvent(taxonomy.CategoryFieldRemovedEvent{})
+	es.RegisterEvent(taxonomy.TagCreatedEvent{})
+	es.RegisterEvent(taxonomy.TagRenamedEvent{})
+	es.RegisterEvent(taxonomy.TagMergedEvent{})
+	es.RegisterEvent(taxonomy.TagDeletedEvent{})
+	es.RegisterEvent(taxonomy.TaxonomyClearedEvent{})
+
+	commandBus := es.NewCommandBus(
+		storage.eventStore,
+		storage.eventMessaging,
+		serviceName,
+		es.CommandTracing(),
+		es.CommandMetrics(),
+		es.CommandLogging(),
+		es.CommandRecovery(),
+		es.CommandValidation(validator.New()),
+		es.CommandTimeout(commandTimeout),
+	)
+
+	householdService := apphousehold.NewHouseholdService(commandBus, storage.userHouseholdRepository)
+	taxonomyService := apptaxonomy.NewTaxonomyService(commandBus, storage.userTaxonomyRepository)
+	labelService := applabel.NewLabelService(storage.userHouseholdRepository, storage.labelCodeRepository)
+	if productCatalogSeed != "" {
+		seedProductCatalog(ctx, storage.productCatalog, productCatalogSeed)
 	}
-	commandBus := es.NewCommandBus(eventStore, eventMessaging)
 
-	userHouseholdRepository := apphousehold.NewUserHouseholdRepository(cassandraSession)
-	householdService := apphousehold.NewHouseholdService(commandBus, userHouseholdRepository)
+	var remoteProductCatalog appcatalog.ProductCatalog
+	if productLookupURL != "" {
+		remoteProductCatalog = appcatalog.NewHTTPProductCatalog(productLookupURL)
+	}
+	catalogService := appcatalog.NewCatalogService(storage.productCatalog, remoteProductCatalog)
+
+	activityService := appactivity.NewActivityService(storage.userHouseholdRepository, storage.householdActivityRepository)
+
+	exportService := appexport.NewExportService(commandBus, storage.userHouseholdRepository)
+	accountService := appaccount.NewAccountService(commandBus, storage.userHouseholdRepository, storage.userTaxonomyRepository, storage.labelCodeRepository, storage.householdActivityRepository, storage.personalDataKeyStore, storage.eventStore)
 
-	if err := kafkatransport.NewKafkaTransport(ctx, eventMessaging, userHouseholdRepository); err != nil {
+	if err := kafkatransport.NewKafkaTransport(ctx, storage.eventConsumer, storage.userHouseholdRepository, storage.userTaxonomyRepository, storage.householdActivityRepository); err != nil {
 		panic(err)
 	}
 
-	if err := httptransport.NewHTTPTransport(ctx, serverAddress, householdService); err != nil {
+	if err := startProcessManagers(ctx, storage, commandBus, processManagers...); err != nil {
 		panic(err)
 	}
+
+	healthChecker := health.NewChecker()
+	for name, check := range storage.livenessChecks {
+		healthChecker.AddLivenessCheck(name, check)
+	}
+	for name, check := range storage.readinessChecks {
+		healthChecker.AddReadinessCheck(name, check)
+	}
+
+	if err := httptransport.NewHTTPTransport(ctx, serverAddress, healthChecker, householdService, exportService, accountService, taxonomyService, labelService, catalogService, activityService); err != nil {
+		panic(err)
+	}
+}
+
+// startProcessManagers runs each process manager on the event store, process timeout store and checkpoint store
+// of the storage driver, dispatching its follow-up commands through the command bus.
+func startProcessManagers(ctx context.Context, storage *storage, commandBus es.CommandDispatcher, managers ...es.ProcessManager) error {
+	for _, manager := range managers {
+		runner := es.NewProcessRunner(manager, storage.eventStore, storage.eventMessaging, commandBus, storage.processTimeoutStore)
+		if err := infrastructure.StartProcessManager(ctx, runner, storage.eventStore, storage.eventConsumer, storage.checkpointStore); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func seedProductCatalog(ctx context.Context, catalog productCatalog, path string) {
+	logger := logging.FromContext(ctx)
+
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Error("failed to open product catalog seed", slog.String("path", path), slog.Any("error", err))
+		return
+	}
+	defer file.Close()
+
+	loaded, skipped, err := catalog.Seed(ctx, file)
+	if err != nil {
+		logger.Error("failed to seed product catalog", slog.String("path", path), slog.Any("error", err))
+		return
+	}
+
+	logger.Info("seeded product catalog", slog.Int("loaded", loaded), slog.Int("skipped", skipped))
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+
+	return defaultValue
+}
+
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Errorf("invalid %s: %w", key, err))
+	}
+
+	return parsed
 }
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/cybre/home-inventory/internal/cassandra"
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/health"
+	"github.com/cybre/home-inventory/internal/infrastructure"
+	"github.com/cybre/home-inventory/internal/sqlite"
+	appaccount "github.com/cybre/home-inventory/services/inventory/app/account"
+	appactivity "github.com/cybre/home-inventory/services/inventory/app/activity"
+	appcatalog "github.com/cybre/home-inventory/services/inventory/app/catalog"
+	apphousehold "github.com/cybre/home-inventory/services/inventory/app/household"
+	applabel "github.com/cybre/home-inventory/services/inventory/app/label"
+	apptaxonomy "github.com/cybre/home-inventory/services/inventory/app/taxonomy"
+	kafkatransport "github.com/cybre/home-inventory/services/inventory/transport/kafka"
+)
+
+const (
+	storageDriverCassandra = "cassandra"
+	storageDriverSQLite    = "sqlite"
+)
+
+type eventMessaging interface {
+	es.EventPublisher
+	kafkatransport.EventConsumer
+	infrastructure.EventStream
+	Close()
+}
+
+type personalDataKeyStore interface {
+	infrastructure.PersonalDataKeyStore
+	appaccount.PersonalDataKeyStore
+}
+
+type userHouseholdRepository interface {
+	apphousehold.UserHouseholdRepo
+	apphousehold.HouseholdRepo
+	appaccount.UserHouseholdRepo
+}
+
+type userTaxonomyRepository interface {
+	apptaxonomy.UserTaxonomyRepo
+	apptaxonomy.TaxonomyRepo
+	appaccount.UserTaxonomyRepo
+}
+
+type labelCodeRepository interface {
+	applabel.LabelCodeRepo
+	appaccount.LabelCodeRepo
+}
+
+type householdActivityRepository interface {
+	appactivity.ActivityRepo
+	appactivity.HouseholdActivityRepo
+}
+
+type productCatalog interface {
+	appcatalog.LocalProductCatalog
+	Seed(ctx context.Context, r io.Reader) (loaded int, skipped int, err error)
+}
+
+// storage holds the event store, event messaging, process manager stores and read model repositories of one
+// storage driver.
+type storage struct {
+	eventStore                  es.EventStore
+	eventMessaging              eventMessaging
+	eventConsumer               infrastructure.EventConsumer
+	checkpointStore             infrastructure.CheckpointStore
+	processTimeoutStore         es.ProcessTimeoutStore
+	personalDataKeyStore        personalDataKeyStore
+	userHouseholdRepository     userHouseholdRepository
+	userTaxonomyRepository      userTaxonomyRepository
+	labelCodeRepository         labelCodeRepository
+	householdActivityRepository householdActivityRepository
+	productCatalog              productCatalog
+
+	// livenessChecks tell whether the storage is stuck in a way only a restart fixes, by check name
+	livenessChecks map[string]health.Check
+	// readinessChecks tell whether the storage can serve requests, by check name
+	readinessChecks map[string]health.Check
+
+	close func()
+}
+
+func newStorage(driver string, logger *slog.Logger) (*storage, error) {
+	switch driver {
+	case "", storageDriverCassandra:
+		return newCassandraStorage(logger)
+	case storageDriverSQLite:
+		return newSQLiteStorage(sqlitePath)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q, expected %s or %s", driver, storageDriverCassandra, storageDriverSQLite)
+	}
+}
+
+// newCassandraStorage stores events and read models in Cassandra and signals new events through Kafka.
+func newCassandraStorage(logger *slog.Logger) (*storage, error) {
+	cassandraSession, err := cassandra.NewSession(cassandraHosts, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	personalDataKeyStore, err := infrastructure.NewCassandraPersonalDataKeyStore(cassandraSession)
+	if err != nil {
+		cassandraSession.Close()
+		return nil, err
+	}
+
+	personalDataProtector := infrastructure.NewPersonalDataProtector(personalDataKeyStore)
+
+	eventMessaging, err := infrastructure.NewKafkaEventMessaging(kafkaBrokers, eventsTopic, kafkaConsumerWorkers, personalDataProtector, logger)
+	if err != nil {
+		cassandraSession.Close()
+		return nil, err
+	}
+
+	closeAll := func() {
+		eventMessaging.Close()
+		cassandraSession.Close()
+	}
+
+	eventStore, err := infrastructure.NewCassandraEventStore(cassandraSession, personalDataProtector)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+
+	checkpointStore, err := infrastructure.NewCassandraCheckpointStore(cassandraSession)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+
+	processTimeoutStore, err := infrastructure.NewCassandraProcessTimeoutStore(cassandraSession)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+
+	return &storage{
+		eventStore:     eventStore,
+		eventMessaging: eventMessaging,
+		// Projectors follow the event log, which also holds the events of a crash between storing and publishing
+		// them. Published events only tell every instance that there is more to read, and the instance holding a
+		// projector's checkpoint reads it.
+		eventConsumer:               infrastructure.NewCatchUpEventConsumer(eventStore, eventMessaging.Broadcast(), checkpointStore),
+		checkpointStore:             checkpointStore,
+		processTimeoutStore:         processTimeoutStore,
+		personalDataKeyStore:        personalDataKeyStore,
+		userHouseholdRepository:     apphousehold.NewUserHouseholdRepository(cassandraSession),
+		userTaxonomyRepository:      apptaxonomy.NewUserTaxonomyRepository(cassandraSession),
+		labelCodeRepository:         applabel.NewLabelCodeRepository(cassandraSession),
+		householdActivityRepository: appactivity.NewHouseholdActivityRepository(cassandraSession),
+		productCatalog:              appcatalog.NewCassandraProductCatalog(cassandraSession),
+		// Consumers only update read models, so the API keeps serving while they are down.
+		// One that stays down despite being restarted gets the whole service restarted.
+		livenessChecks: map[string]health.Check{
+			"event_consumers": eventMessaging.CheckConsumers,
+		},
+		readinessChecks: map[string]health.Check{
+			"cassandra": func(ctx context.Context) error {
+				return cassandra.Ping(ctx, cassandraSession)
+			},
+			"kafka": eventMessaging.Ping,
+		},
+		close: closeAll,
+	}, nil
+}
+
+// newSQLiteStorage keeps everything in a single SQLite file and delivers events in process,
+// so the service runs without Cassandra or Kafka.
+func newSQLiteStorage(path string) (*storage, error) {
+	db, err := sqlite.NewDB(path, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	closeAll := func() {
+		db.Close()
+	}
+
+	personalDataKeyStore, err := infrastructure.NewSQLitePersonalDataKeyStore(db)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+
+	eventStore, err := infrastructure.NewSQLiteEventStore(db, infrastructure.NewPersonalDataProtector(personalDataKeyStore))
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+
+	checkpointStore, err := infrastructure.NewSQLiteCheckpointStore(db)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+
+	processTimeoutStore, err := infrastructure.NewSQLiteProcessTimeoutStore(db)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+
+	// Events are only published after they are stored, so projectors follow the event log
+	// to also pick up the events of a crash in between on the next start
+	eventMessaging := infrastructure.NewInProcessEventMessaging()
+
+	return &storage{
+		eventStore:                  eventStore,
+		eventMessaging:              eventMessaging,
+		eventConsumer:               infrastructure.NewCatchUpEventConsumer(eventStore, eventMessaging, checkpointStore),
+		checkpointStore:             checkpointStore,
+		processTimeoutStore:         processTimeoutStore,
+		personalDataKeyStore:        personalDataKeyStore,
+		userHouseholdRepository:     apphousehold.NewSQLiteUserHouseholdRepository(db),
+		userTaxonomyRepository:      apptaxonomy.NewSQLiteUserTaxonomyRepository(db),
+		labelCodeRepository:         applabel.NewSQLiteLabelCodeRepository(db),
+		householdActivityRepository: appactivity.NewSQLiteHouseholdActivityRepository(db),
+		productCatalog:              appcatalog.NewSQLiteProductCatalog(db),
+		readinessChecks: map[string]health.Check{
+			"sqlite": db.PingContext,
+		},
+		close: closeAll,
+	}, nil
+}
@@ -7,13 +7,18 @@ import (
 	"os/signal"
 
 	"github.com/cybre/home-inventory/internal/logging"
+	"github.com/cybre/home-inventory/internal/tracing"
 	"github.com/cybre/home-inventory/services/web/app"
 	"github.com/joho/godotenv"
 )
 
 const serviceName = "web"
 
-var serverAddress = os.Getenv("SERVER_ADDRESS")
+var (
+	serverAddress  = os.Getenv("SERVER_ADDRESS")
+	metricsAddress = os.Getenv("METRICS_ADDRESS")
+	publicBaseURL  = os.Getenv("PUBLIC_BASE_URL")
+)
 
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
@@ -30,9 +35,21 @@ func main() {
 		}
 
 		serverAddress = os.Getenv("SERVER_ADDRESS")
+		metricsAddress = os.Getenv("METRICS_ADDRESS")
+		publicBaseURL = os.Getenv("PUBLIC_BASE_URL")
 	}
 
-	if err := app.New(ctx, serverAddress, logger); err != nil {
+	shutdownTracing, err := tracing.Setup(ctx, serviceName)
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("failed to shut down tracing", slog.Any("error", err))
+		}
+	}()
+
+	if err := app.New(ctx, serverAddress, metricsAddress, publicBaseURL, logger); err != nil {
 		panic(err)
 	}
 }
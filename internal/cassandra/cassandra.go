@@ -1,6 +1,7 @@
 package cassandra
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -18,6 +19,8 @@ func NewSession(hosts []string, keyspace string) (*gocql.Session, error) {
 
 	cluster := gocql.NewCluster(hosts...)
 	cluster.Keyspace = keyspace
+	cluster.QueryObserver = queryObserver{}
+	cluster.BatchObserver = queryObserver{}
 
 	session, err := cluster.CreateSession()
 	if err != nil {
@@ -68,3 +71,8 @@ func createKeyspace(hosts []string, keyspace string) error {
 
 	return nil
 }
+
+// Ping runs a trivial query to check that the session can reach the cluster.
+func Ping(ctx context.Context, session *gocql.Session) error {
+	return session.Query("SELECT now() FROM system.local").WithContext(ctx).Exec()
+}
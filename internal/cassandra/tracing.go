@@ -0,0 +1,58 @@
+package cassandra
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cybre/home-inventory/internal/tracing"
+	"github.com/gocql/gocql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryObserver records a span for every query and batch attempt made with a traced context.
+// Queries outside of a trace, such as migrations, are not recorded.
+type queryObserver struct{}
+
+func (queryObserver) ObserveQuery(ctx context.Context, q gocql.ObservedQuery) {
+	recordSpan(ctx, "cassandra "+statementOperation(q.Statement), q.Keyspace, q.Start, q.End, q.Err,
+		semconv.DBStatement(q.Statement),
+		semconv.DBOperation(statementOperation(q.Statement)),
+	)
+}
+
+func (queryObserver) ObserveBatch(ctx context.Context, b gocql.ObservedBatch) {
+	recordSpan(ctx, "cassandra BATCH", b.Keyspace, b.Start, b.End, b.Err,
+		semconv.DBStatement(strings.Join(b.Statements, ";\n")),
+		semconv.DBOperation("BATCH"),
+	)
+}
+
+func recordSpan(ctx context.Context, name, keyspace string, start, end time.Time, err error, attributes ...attribute.KeyValue) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+
+	_, span := tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(append(attributes, semconv.DBSystemCassandra, semconv.DBName(keyspace))...),
+	)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End(trace.WithTimestamp(end))
+}
+
+// statementOperation returns the first keyword of a CQL statement, such as SELECT or INSERT.
+func statementOperation(statement string) string {
+	operation, _, _ := strings.Cut(strings.TrimSpace(statement), " ")
+
+	return strings.ToUpper(operation)
+}
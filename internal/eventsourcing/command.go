@@ -7,7 +7,11 @@ import (
 	"time"
 
 	"github.com/cybre/home-inventory/internal/logging"
+	"github.com/cybre/home-inventory/internal/metrics"
 	"github.com/cybre/home-inventory/internal/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Command interface {
@@ -16,8 +20,14 @@ type Command interface {
 }
 
 type EventStore interface {
-	GetEvents(aggregateType AggregateType, aggregateID AggregateID) ([]Event, error)
+	GetEvents(ctx context.Context, aggregateType AggregateType, aggregateID AggregateID) ([]Event, error)
+	// StoreEvents appends the events to their aggregate's stream and to the global event log,
+	// setting the Position of each event in the slice.
 	StoreEvents(ctx context.Context, events []Event) error
+	// ReadAll returns up to batchSize events from the global event log, starting at fromPosition, in log order.
+	// An event is only returned once every earlier position holds an event or is known to stay empty, so
+	// readers that remember the last position they got never miss an event.
+	ReadAll(ctx context.Context, fromPosition uint64, batchSize int) ([]Event, error)
 }
 
 type EventPublisher interface {
@@ -27,18 +37,35 @@ type EventPublisher interface {
 type CommandBus struct {
 	eventStore     EventStore
 	eventPublisher EventPublisher
+	source         string
+	handler        CommandHandlerFunc
 }
 
-func NewCommandBus(eventStore EventStore, eventPublisher EventPublisher) *CommandBus {
-	return &CommandBus{
+// NewCommandBus creates a command bus recording events on behalf of the source service.
+// Commands pass through the middlewares in the given order before they are handled.
+func NewCommandBus(eventStore EventStore, eventPublisher EventPublisher, source string, middlewares ...CommandMiddleware) *CommandBus {
+	cb := &CommandBus{
 		eventStore:     eventStore,
 		eventPublisher: eventPublisher,
+		source:         source,
 	}
+
+	cb.handler = cb.dispatch
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		cb.handler = middlewares[i](cb.handler)
+	}
+
+	return cb
 }
 
 func (cb *CommandBus) Dispatch(ctx context.Context, c Command) error {
+	return cb.handler(ctx, c)
+}
+
+// dispatch handles the command with its aggregate, then stores and publishes the resulting events.
+func (cb *CommandBus) dispatch(ctx context.Context, c Command) error {
 	// TODO - distributed locking
-	events, err := cb.eventStore.GetEvents(c.AggregateType(), c.AggregateID())
+	events, err := cb.eventStore.GetEvents(ctx, c.AggregateType(), c.AggregateID())
 	if err != nil {
 		return fmt.Errorf("failed to fetch events for aggregate: %w", err)
 	}
@@ -64,7 +91,7 @@ func (cb *CommandBus) Dispatch(ctx context.Context, c Command) error {
 			slog.Any("aggregate_type", c.AggregateType()),
 			slog.Any("aggregate_id", c.AggregateID()),
 			slog.Any("version", aggregateContext.Version()),
-			slog.String("command", fmt.Sprintf("%T", c)),
+			slog.String("command", commandName(c)),
 		),
 	)
 
@@ -73,6 +100,7 @@ func (cb *CommandBus) Dispatch(ctx context.Context, c Command) error {
 		return err
 	}
 
+	eventMetadata := newEventMetadata(ctx, cb.source)
 	newEvents := utils.Map(result, func(i uint, event EventData) Event {
 		return Event{
 			AggregateType: c.AggregateType(),
@@ -81,16 +109,29 @@ func (cb *CommandBus) Dispatch(ctx context.Context, c Command) error {
 			Data:          event,
 			Timestamp:     time.Now().UnixMilli(),
 			Version:       aggregateContext.Version() + i + 1,
+			Metadata:      eventMetadata(),
 		}
 	})
 
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("events", len(newEvents)))
+
 	if err := cb.eventStore.StoreEvents(ctx, newEvents); err != nil {
 		return fmt.Errorf("failed to store events: %w", err)
 	}
 
+	countEvents(metrics.EventsStored, newEvents)
+
 	if err := cb.eventPublisher.PublishEvents(ctx, newEvents); err != nil {
 		return fmt.Errorf("failed to publish events: %w", err)
 	}
 
+	countEvents(metrics.EventsPublished, newEvents)
+
 	return nil
 }
+
+func countEvents(counter *prometheus.CounterVec, events []Event) {
+	for _, event := range events {
+		counter.WithLabelValues(string(event.EventType)).Inc()
+	}
+}
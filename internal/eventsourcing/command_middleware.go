@@ -0,0 +1,148 @@
+package eventsourcing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/bnkamalesh/errors"
+	"github.com/cybre/home-inventory/internal/logging"
+	"github.com/cybre/home-inventory/internal/metrics"
+	"github.com/cybre/home-inventory/internal/tracing"
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CommandHandlerFunc handles a dispatched command.
+type CommandHandlerFunc func(ctx context.Context, c Command) error
+
+// CommandMiddleware wraps the handling of every command dispatched on a CommandBus.
+type CommandMiddleware func(next CommandHandlerFunc) CommandHandlerFunc
+
+// TimedCommand is implemented by commands that need a different timeout than the CommandTimeout default.
+type TimedCommand interface {
+	Command
+	Timeout() time.Duration
+}
+
+// CommandLogging logs the outcome and duration of every command.
+func CommandLogging() CommandMiddleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, c Command) error {
+			start := time.Now()
+			err := next(ctx, c)
+
+			attrs := []slog.Attr{
+				slog.String("command", commandName(c)),
+				slog.Any("aggregate_type", c.AggregateType()),
+				slog.Any("aggregate_id", c.AggregateID()),
+				slog.Duration("duration", time.Since(start)),
+			}
+
+			logger := logging.FromContext(ctx)
+			if err != nil {
+				logger.LogAttrs(ctx, slog.LevelError, "COMMAND_ERROR", append(attrs, slog.String("err", err.Error()))...)
+			} else {
+				logger.LogAttrs(ctx, slog.LevelInfo, "COMMAND", attrs...)
+			}
+
+			return err
+		}
+	}
+}
+
+// CommandTimeout cancels the handling of a command after the timeout, or after its own timeout if it is a TimedCommand.
+func CommandTimeout(timeout time.Duration) CommandMiddleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, c Command) error {
+			commandTimeout := timeout
+			if timed, ok := c.(TimedCommand); ok {
+				commandTimeout = timed.Timeout()
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+			defer cancel()
+
+			return next(ctx, c)
+		}
+	}
+}
+
+// CommandRecovery turns a panic while handling a command into an ErrCommandPanicked error.
+func CommandRecovery() CommandMiddleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, c Command) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logging.FromContext(ctx).Error("command handling panicked", slog.String("command", commandName(c)), slog.Any("panic", r), slog.String("stack", string(debug.Stack())))
+					err = fmt.Errorf("%w: %v", ErrCommandPanicked, r)
+				}
+			}()
+
+			return next(ctx, c)
+		}
+	}
+}
+
+// CommandValidation rejects commands that do not pass the validation described by their struct tags.
+func CommandValidation(validate *validator.Validate) CommandMiddleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, c Command) error {
+			if err := validate.Struct(c); err != nil {
+				return errors.InputBodyErr(err, fmt.Sprintf("invalid %s: %s", commandName(c), err))
+			}
+
+			return next(ctx, c)
+		}
+	}
+}
+
+// CommandTracing handles every command in its own span.
+func CommandTracing() CommandMiddleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, c Command) (err error) {
+			ctx, span := tracing.Tracer().Start(ctx, "Dispatch "+commandName(c), trace.WithAttributes(
+				attribute.String("command", commandName(c)),
+				attribute.String("aggregate_type", string(c.AggregateType())),
+				attribute.String("aggregate_id", string(c.AggregateID())),
+			))
+			defer func() { tracing.End(span, err) }()
+
+			return next(ctx, c)
+		}
+	}
+}
+
+// CommandMetrics records how long commands take by command type and result.
+func CommandMetrics() CommandMiddleware {
+	return func(next CommandHandlerFunc) CommandHandlerFunc {
+		return func(ctx context.Context, c Command) error {
+			start := time.Now()
+			err := next(ctx, c)
+
+			metrics.CommandDispatchDuration.
+				WithLabelValues(commandName(c), dispatchResult(err)).
+				Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+func dispatchResult(err error) string {
+	switch {
+	case err == nil:
+		return metrics.DispatchOK
+	case errors.Is(err, ErrVersionConflict):
+		return metrics.DispatchConflict
+	default:
+		return metrics.DispatchError
+	}
+}
+
+func commandName(c Command) string {
+	return fmt.Sprintf("%T", c)
+}
@@ -0,0 +1,76 @@
+package eventsourcing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bnkamalesh/errors"
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCommand struct {
+	ID string `validate:"required"`
+}
+
+func (c testCommand) AggregateType() es.AggregateType {
+	return "Test"
+}
+
+func (c testCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(c.ID)
+}
+
+type slowCommand struct {
+	testCommand
+}
+
+func (c slowCommand) Timeout() time.Duration {
+	return time.Hour
+}
+
+func Test_CommandMiddlewares(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("validation rejects invalid commands before they are handled", func(t *testing.T) {
+		handled := false
+		handler := es.CommandValidation(validator.New())(func(ctx context.Context, c es.Command) error {
+			handled = true
+			return nil
+		})
+
+		err := handler(ctx, testCommand{})
+		assert.Error(t, err)
+		code, _, _ := errors.HTTPStatusCodeMessage(err)
+		assert.Equal(t, 400, code)
+		assert.False(t, handled)
+
+		assert.NoError(t, handler(ctx, testCommand{ID: "1"}))
+		assert.True(t, handled)
+	})
+
+	t.Run("recovery turns panics into errors", func(t *testing.T) {
+		handler := es.CommandRecovery()(func(ctx context.Context, c es.Command) error {
+			panic("boom")
+		})
+
+		assert.ErrorIs(t, handler(ctx, testCommand{ID: "1"}), es.ErrCommandPanicked)
+	})
+
+	t.Run("timeout bounds the command context", func(t *testing.T) {
+		var deadline time.Time
+		handler := es.CommandTimeout(time.Second)(func(ctx context.Context, c es.Command) error {
+			deadline, _ = ctx.Deadline()
+			return nil
+		})
+
+		assert.NoError(t, handler(ctx, testCommand{ID: "1"}))
+		assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+
+		// Commands can ask for their own timeout
+		assert.NoError(t, handler(ctx, slowCommand{testCommand{ID: "1"}}))
+		assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, 100*time.Millisecond)
+	})
+}
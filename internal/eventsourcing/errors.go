@@ -5,7 +5,9 @@ import "errors"
 var (
 	ErrAggregateTypeNotFound = errors.New("aggregate type not found in registry")
 	ErrEventTypeNotFound     = errors.New("event type not found in registry")
+	ErrVersionConflict       = errors.New("aggregate version already exists in event store")
 
-	ErrUnknownCommand = errors.New("aggregate does not know how to handle command")
-	ErrUnknownEvent   = errors.New("event handler does not know how to handle event")
+	ErrUnknownCommand  = errors.New("aggregate does not know how to handle command")
+	ErrCommandPanicked = errors.New("command handling panicked")
+	ErrUnknownEvent    = errors.New("event handler does not know how to handle event")
 )
@@ -15,6 +15,10 @@ type Event struct {
 	Data          EventData     `json:"eventData"`
 	Timestamp     int64         `json:"timestamp"`
 	Version       uint          `json:"version"`
+	// Position is the event's place in the global event log, set by EventStore.StoreEvents and EventStore.ReadAll.
+	Position uint64 `json:"position,omitempty"`
+	// Metadata travels next to the event rather than inside it, e.g. as Kafka record headers
+	Metadata EventMetadata `json:"-"`
 }
 
 func UnmarshalEvent(data []byte) (Event, error) {
@@ -37,6 +41,11 @@ func UnmarshalEvent(data []byte) (Event, error) {
 		return Event{}, fmt.Errorf("failed to unmarshal event data: %w", err)
 	}
 
+	position := uint64(0)
+	if p, ok := event["position"].(float64); ok {
+		position = uint64(p)
+	}
+
 	return Event{
 		AggregateType: AggregateType(event["aggregateType"].(string)),
 		AggregateID:   AggregateID(event["aggregateId"].(string)),
@@ -44,6 +53,7 @@ func UnmarshalEvent(data []byte) (Event, error) {
 		Data:          reflect.ValueOf(eventDataInstance).Elem().Interface().(EventData),
 		Timestamp:     int64(event["timestamp"].(float64)),
 		Version:       uint(event["version"].(float64)),
+		Position:      position,
 	}, nil
 }
 
@@ -78,11 +88,8 @@ func (r *EventRegistry) GetEvent(eventType EventType) (EventData, bool) {
 	return nil, false
 }
 
-var eventRegistry *EventRegistry
-
-func init() {
-	eventRegistry = NewEventRegistry()
-}
+// eventRegistry is initialised with the package variables, before any init function registers events in it
+var eventRegistry = NewEventRegistry()
 
 func RegisterEvent(event EventData) {
 	eventRegistry.RegisterEvent(event)
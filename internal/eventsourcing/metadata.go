@@ -0,0 +1,82 @@
+package eventsourcing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Context keys for the IDs that end up in event metadata. The request and correlation IDs are put on the
+// context by middleware.RequestAndCorrelationIDLogging.
+const (
+	requestIDKey     = "request_id"
+	correlationIDKey = "correlation_id"
+	causationIDKey   = "causation_id"
+	actorIDKey       = "actor_id"
+)
+
+// EventMetadata describes where an event came from.
+type EventMetadata struct {
+	EventID string `json:"eventId"`
+	// CorrelationID is shared by everything that happened because of the same original request
+	CorrelationID string `json:"correlationId,omitempty"`
+	// CausationID is the ID of the request or event that directly caused the event
+	CausationID string `json:"causationId,omitempty"`
+	// ActorID is the user the event was recorded for
+	ActorID string `json:"actorId,omitempty" personal:"subject"`
+	// Source is the service that recorded the event
+	Source string `json:"source,omitempty"`
+}
+
+// WithActor records the user that commands dispatched with the context act for.
+func WithActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorIDKey, actorID)
+}
+
+// WithCausingEvent makes the event the cause of anything dispatched with the returned context,
+// continuing its correlation and acting for the same user.
+func WithCausingEvent(ctx context.Context, event Event) context.Context {
+	ctx = context.WithValue(ctx, causationIDKey, event.Metadata.EventID)
+
+	if event.Metadata.CorrelationID != "" {
+		ctx = context.WithValue(ctx, correlationIDKey, event.Metadata.CorrelationID)
+	}
+
+	if event.Metadata.ActorID != "" {
+		ctx = WithActor(ctx, event.Metadata.ActorID)
+	}
+
+	return ctx
+}
+
+// newEventMetadata returns a function creating the metadata of each event recorded by one command.
+// The events share a correlation ID, which is started here if the context does not carry one.
+func newEventMetadata(ctx context.Context, source string) func() EventMetadata {
+	correlationID := contextString(ctx, correlationIDKey)
+	if correlationID == "" {
+		correlationID = uuid.NewString()
+	}
+
+	causationID := contextString(ctx, causationIDKey)
+	if causationID == "" {
+		causationID = contextString(ctx, requestIDKey)
+	}
+
+	actorID := contextString(ctx, actorIDKey)
+
+	return func() EventMetadata {
+		return EventMetadata{
+			EventID:       uuid.NewString(),
+			CorrelationID: correlationID,
+			CausationID:   causationID,
+			ActorID:       actorID,
+			Source:        source,
+		}
+	}
+}
+
+func contextString(ctx context.Context, key string) string {
+	value, _ := ctx.Value(key).(string)
+
+	return value
+}
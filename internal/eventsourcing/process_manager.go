@@ -0,0 +1,533 @@
+package eventsourcing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/bnkamalesh/errors"
+	"github.com/cybre/home-inventory/internal/logging"
+	"github.com/cybre/home-inventory/internal/metrics"
+	"github.com/cybre/home-inventory/internal/utils"
+	"github.com/google/uuid"
+)
+
+// processTimeoutInterval is how often due process timeouts are looked for
+const processTimeoutInterval = 5 * time.Second
+
+const (
+	EventTypeProcessEventHandled     EventType = "ProcessEventHandledEvent"
+	EventTypeProcessTimeoutScheduled EventType = "ProcessTimeoutScheduledEvent"
+	EventTypeProcessTimeoutFired     EventType = "ProcessTimeoutFiredEvent"
+	EventTypeProcessTimeoutCancelled EventType = "ProcessTimeoutCancelledEvent"
+	EventTypeProcessCompleted        EventType = "ProcessCompletedEvent"
+)
+
+// ProcessID identifies one instance of a process, such as one household merge.
+type ProcessID string
+
+// ProcessState is the state of one process instance, rebuilt from the events the process recorded.
+type ProcessState interface {
+	ApplyEvent(EventData)
+}
+
+// CommandDispatcher dispatches commands to their aggregates, like the CommandBus.
+type CommandDispatcher interface {
+	Dispatch(ctx context.Context, c Command) error
+}
+
+// ProcessManager coordinates a workflow spanning several aggregates. It reacts to events by recording its own
+// events, dispatching commands and scheduling timeouts, and undoes earlier steps when an aggregate rejects a command.
+//
+// Process instances are stored as event streams of the aggregate type named after the process manager, so the events
+// a process records have to be registered like any other event. Commands are dispatched before the process records
+// that it handled an event, so a failure in between dispatches them again and they have to be safe to repeat.
+type ProcessManager interface {
+	// Name identifies the process manager and is the aggregate type of its process instances.
+	Name() string
+	// Events returns the types of events the process manager reacts to.
+	Events() []EventType
+	// ProcessID returns the process instance an event belongs to, or false if it concerns none.
+	ProcessID(event Event) (ProcessID, bool)
+	// NewState returns the state of a process instance that has not recorded anything yet.
+	NewState() ProcessState
+	// HandleEvent reacts to an event of the process instance.
+	HandleEvent(ctx context.Context, process *Process, event Event) error
+	// HandleTimeout reacts to a timeout the process instance scheduled coming due.
+	HandleTimeout(ctx context.Context, process *Process, timeout string) error
+	// Compensate reacts to a command of the process instance being rejected, typically by dispatching commands
+	// that undo the steps already taken. The commands of the step after the rejected one are dropped.
+	Compensate(ctx context.Context, process *Process, command Command, err error) error
+}
+
+// Process is a process instance as seen by its ProcessManager while it handles an event or a timeout.
+type Process struct {
+	id        ProcessID
+	state     ProcessState
+	handled   map[string]bool
+	timeouts  map[string]ProcessTimeoutScheduledEvent
+	completed bool
+
+	recorded []EventData
+	commands []Command
+}
+
+func newProcess(id ProcessID, state ProcessState) *Process {
+	return &Process{
+		id:       id,
+		state:    state,
+		handled:  map[string]bool{},
+		timeouts: map[string]ProcessTimeoutScheduledEvent{},
+	}
+}
+
+func (p *Process) ID() ProcessID {
+	return p.id
+}
+
+func (p *Process) State() ProcessState {
+	return p.state
+}
+
+// Completed tells whether the process has finished. A completed process ignores further events and timeouts.
+func (p *Process) Completed() bool {
+	return p.completed
+}
+
+// Record applies the event to the state of the process and stores it once the process has handled the event or timeout.
+func (p *Process) Record(event EventData) {
+	p.apply(event)
+	p.recorded = append(p.recorded, event)
+}
+
+// Dispatch queues a command, which is dispatched once the process has handled the event or timeout.
+func (p *Process) Dispatch(c Command) {
+	p.commands = append(p.commands, c)
+}
+
+// ScheduleTimeout has the process manager handle a timeout with the given name after the given duration,
+// unless it is cancelled or the process completes first.
+func (p *Process) ScheduleTimeout(name string, after time.Duration) {
+	p.Record(ProcessTimeoutScheduledEvent{
+		TimeoutID: uuid.NewString(),
+		Name:      name,
+		Deadline:  time.Now().Add(after).UnixMilli(),
+	})
+}
+
+// CancelTimeout cancels the pending timeouts with the given name.
+func (p *Process) CancelTimeout(name string) {
+	for _, timeout := range p.pendingTimeouts() {
+		if timeout.Name == name {
+			p.Record(ProcessTimeoutCancelledEvent{TimeoutID: timeout.TimeoutID})
+		}
+	}
+}
+
+// Complete finishes the process, cancelling its pending timeouts.
+func (p *Process) Complete() {
+	if p.completed {
+		return
+	}
+
+	for _, timeout := range p.pendingTimeouts() {
+		p.Record(ProcessTimeoutCancelledEvent{TimeoutID: timeout.TimeoutID})
+	}
+
+	p.Record(ProcessCompletedEvent{})
+}
+
+func (p *Process) pendingTimeouts() []ProcessTimeoutScheduledEvent {
+	timeouts := make([]ProcessTimeoutScheduledEvent, 0, len(p.timeouts))
+	for _, timeout := range p.timeouts {
+		timeouts = append(timeouts, timeout)
+	}
+
+	sort.Slice(timeouts, func(i, j int) bool {
+		return timeouts[i].Deadline < timeouts[j].Deadline
+	})
+
+	return timeouts
+}
+
+func (p *Process) apply(event EventData) {
+	switch e := event.(type) {
+	case ProcessEventHandledEvent:
+		p.handled[e.Event] = true
+	case ProcessTimeoutScheduledEvent:
+		p.timeouts[e.TimeoutID] = e
+	case ProcessTimeoutFiredEvent:
+		delete(p.timeouts, e.TimeoutID)
+	case ProcessTimeoutCancelledEvent:
+		delete(p.timeouts, e.TimeoutID)
+	case ProcessCompletedEvent:
+		p.completed = true
+	default:
+		p.state.ApplyEvent(event)
+	}
+}
+
+func init() {
+	RegisterEvent(ProcessEventHandledEvent{})
+	RegisterEvent(ProcessTimeoutScheduledEvent{})
+	RegisterEvent(ProcessTimeoutFiredEvent{})
+	RegisterEvent(ProcessTimeoutCancelledEvent{})
+	RegisterEvent(ProcessCompletedEvent{})
+}
+
+// ProcessEventHandledEvent marks an event as handled by the process, so a redelivery of it is ignored.
+type ProcessEventHandledEvent struct {
+	Event string `json:"event"`
+}
+
+func (e ProcessEventHandledEvent) EventType() EventType {
+	return EventTypeProcessEventHandled
+}
+
+type ProcessTimeoutScheduledEvent struct {
+	TimeoutID string `json:"timeoutId"`
+	Name      string `json:"name"`
+	// Deadline is when the timeout comes due, in Unix milliseconds
+	Deadline int64 `json:"deadline"`
+}
+
+func (e ProcessTimeoutScheduledEvent) EventType() EventType {
+	return EventTypeProcessTimeoutScheduled
+}
+
+type ProcessTimeoutFiredEvent struct {
+	TimeoutID string `json:"timeoutId"`
+}
+
+func (e ProcessTimeoutFiredEvent) EventType() EventType {
+	return EventTypeProcessTimeoutFired
+}
+
+type ProcessTimeoutCancelledEvent struct {
+	TimeoutID string `json:"timeoutId"`
+}
+
+func (e ProcessTimeoutCancelledEvent) EventType() EventType {
+	return EventTypeProcessTimeoutCancelled
+}
+
+type ProcessCompletedEvent struct{}
+
+func (e ProcessCompletedEvent) EventType() EventType {
+	return EventTypeProcessCompleted
+}
+
+// ProcessTimeout is a pending timeout of a process instance.
+type ProcessTimeout struct {
+	ProcessID ProcessID
+	TimeoutID string
+	Name      string
+	// Deadline is when the timeout comes due, in Unix milliseconds
+	Deadline int64
+}
+
+// ProcessTimeoutStore keeps the pending timeouts of processes, so they survive restarts without the event log
+// having to be read from the start again.
+type ProcessTimeoutStore interface {
+	// SaveTimeout stores a pending timeout, replacing one with the same ID.
+	SaveTimeout(ctx context.Context, processType AggregateType, timeout ProcessTimeout) error
+	DeleteTimeout(ctx context.Context, processType AggregateType, timeoutID string) error
+	// DueTimeouts returns the timeouts of the process type that are due at the given time, earliest first.
+	DueTimeouts(ctx context.Context, processType AggregateType, now time.Time) ([]ProcessTimeout, error)
+}
+
+// ProcessRunner carries out the decisions of a ProcessManager. It is an event handler for the events the
+// manager reacts to, and fires the timeouts of its processes with RunTimeouts. The state of every process
+// is kept in the event store and its pending timeouts in a ProcessTimeoutStore, so processes continue where
+// they left off after a restart.
+type ProcessRunner struct {
+	manager        ProcessManager
+	eventStore     EventStore
+	eventPublisher EventPublisher
+	commands       CommandDispatcher
+	timeouts       *ProcessTimeouts
+}
+
+func NewProcessRunner(manager ProcessManager, eventStore EventStore, eventPublisher EventPublisher, commands CommandDispatcher, timeoutStore ProcessTimeoutStore) *ProcessRunner {
+	return &ProcessRunner{
+		manager:        manager,
+		eventStore:     eventStore,
+		eventPublisher: eventPublisher,
+		commands:       commands,
+		timeouts:       newProcessTimeouts(AggregateType(manager.Name()), timeoutStore),
+	}
+}
+
+func (r *ProcessRunner) Name() string {
+	return r.manager.Name()
+}
+
+func (r *ProcessRunner) Events() []EventType {
+	return r.manager.Events()
+}
+
+// HandleEvent passes the event to the process instance it belongs to. Events the process has handled before are ignored.
+func (r *ProcessRunner) HandleEvent(ctx context.Context, event Event) error {
+	processID, ok := r.manager.ProcessID(event)
+	if !ok {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s/%s/%d", event.AggregateType, event.AggregateID, event.Version)
+
+	return r.step(ctx, processID, func(process *Process) (EventData, error) {
+		if process.completed || process.handled[key] {
+			return nil, nil
+		}
+
+		if err := r.manager.HandleEvent(ctx, process, event); err != nil {
+			return nil, err
+		}
+
+		return ProcessEventHandledEvent{Event: key}, nil
+	})
+}
+
+// Timeouts returns the event handler recording the pending timeouts of the processes in the timeout store. It
+// has to be fed the global event log, for instance by a catch-up subscription resuming from its checkpoint, for
+// timeouts scheduled by another instance of the service or right before a crash to fire.
+func (r *ProcessRunner) Timeouts() *ProcessTimeouts {
+	return r.timeouts
+}
+
+// RunTimeouts fires the timeouts that have come due until ctx is done.
+func (r *ProcessRunner) RunTimeouts(ctx context.Context) {
+	ticker := time.NewTicker(processTimeoutInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := r.FireDueTimeouts(ctx, now); err != nil {
+				logging.FromContext(ctx).Error("failed to fire process timeouts", slog.String("process_manager", r.Name()), slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// FireDueTimeouts has the process manager handle the timeouts due at the given time. A timeout that fails
+// stays pending and is fired again later.
+func (r *ProcessRunner) FireDueTimeouts(ctx context.Context, now time.Time) error {
+	due, err := r.timeouts.due(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, timeout := range due {
+		err := r.step(ctx, timeout.ProcessID, func(process *Process) (EventData, error) {
+			scheduled, pending := process.timeouts[timeout.TimeoutID]
+			if process.completed || !pending {
+				return nil, nil
+			}
+
+			if err := r.manager.HandleTimeout(ctx, process, scheduled.Name); err != nil {
+				return nil, err
+			}
+
+			return ProcessTimeoutFiredEvent{TimeoutID: timeout.TimeoutID}, nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fire timeout %s of process %s: %w", timeout.Name, timeout.ProcessID, err))
+			continue
+		}
+
+		if err := r.timeouts.remove(ctx, timeout.TimeoutID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// step loads a process, lets act decide what to do and carries it out. act returns the event marking
+// the step as done, or nil if the process has nothing to do.
+func (r *ProcessRunner) step(ctx context.Context, processID ProcessID, act func(process *Process) (EventData, error)) error {
+	process, version, err := r.load(ctx, processID)
+	if err != nil {
+		return err
+	}
+
+	done, err := act(process)
+	if err != nil || done == nil {
+		return err
+	}
+
+	if err := r.dispatch(ctx, process); err != nil {
+		return err
+	}
+
+	process.Record(done)
+
+	return r.save(ctx, process, version)
+}
+
+func (r *ProcessRunner) load(ctx context.Context, processID ProcessID) (*Process, uint, error) {
+	events, err := r.eventStore.GetEvents(ctx, AggregateType(r.manager.Name()), AggregateID(processID))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch events for process: %w", err)
+	}
+
+	process := newProcess(processID, r.manager.NewState())
+	for _, event := range events {
+		process.apply(event.Data)
+	}
+
+	version := uint(0)
+	if len(events) > 0 {
+		version = events[len(events)-1].Version
+	}
+
+	return process, version, nil
+}
+
+// dispatch dispatches the queued commands in order. When an aggregate rejects one, the rest are dropped and
+// the process manager gets to compensate. Any other failure is returned, so the step is retried.
+func (r *ProcessRunner) dispatch(ctx context.Context, process *Process) error {
+	compensating := false
+	for len(process.commands) > 0 {
+		command := process.commands[0]
+		process.commands = process.commands[1:]
+
+		err := r.commands.Dispatch(ctx, command)
+		if err == nil {
+			continue
+		}
+
+		if compensating || !isRejection(err) {
+			return fmt.Errorf("failed to dispatch %s: %w", commandName(command), err)
+		}
+
+		logging.FromContext(ctx).Warn("process command rejected, compensating",
+			slog.String("process_manager", r.Name()),
+			slog.String("process_id", string(process.id)),
+			slog.String("command", commandName(command)),
+			slog.Any("error", err),
+		)
+
+		compensating = true
+		process.commands = nil
+		if err := r.manager.Compensate(ctx, process, command, err); err != nil {
+			return fmt.Errorf("failed to compensate for %s: %w", commandName(command), err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ProcessRunner) save(ctx context.Context, process *Process, version uint) error {
+	eventMetadata := newEventMetadata(ctx, r.manager.Name())
+	events := utils.Map(process.recorded, func(i uint, event EventData) Event {
+		return Event{
+			AggregateType: AggregateType(r.manager.Name()),
+			AggregateID:   AggregateID(process.id),
+			EventType:     event.EventType(),
+			Data:          event,
+			Timestamp:     time.Now().UnixMilli(),
+			Version:       version + i + 1,
+			Metadata:      eventMetadata(),
+		}
+	})
+
+	if err := r.eventStore.StoreEvents(ctx, events); err != nil {
+		return fmt.Errorf("failed to store process events: %w", err)
+	}
+
+	countEvents(metrics.EventsStored, events)
+
+	if err := r.eventPublisher.PublishEvents(ctx, events); err != nil {
+		return fmt.Errorf("failed to publish process events: %w", err)
+	}
+
+	countEvents(metrics.EventsPublished, events)
+
+	return nil
+}
+
+// isRejection tells whether an aggregate refused a command, as opposed to the command failing for a reason
+// that retrying could fix.
+func isRejection(err error) bool {
+	var appErr *errors.Error
+	if !errors.As(err, &appErr) {
+		return false
+	}
+
+	switch appErr.Type() {
+	case errors.TypeValidation, errors.TypeInputBody, errors.TypeDuplicate, errors.TypeNotFound, errors.TypeEmpty,
+		errors.TypeUnauthenticated, errors.TypeUnauthorized:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProcessTimeouts records the pending timeouts of the processes of one process manager in a ProcessTimeoutStore.
+type ProcessTimeouts struct {
+	processType AggregateType
+	store       ProcessTimeoutStore
+}
+
+func newProcessTimeouts(processType AggregateType, store ProcessTimeoutStore) *ProcessTimeouts {
+	return &ProcessTimeouts{
+		processType: processType,
+		store:       store,
+	}
+}
+
+func (t *ProcessTimeouts) Name() string {
+	return string(t.processType) + "Timeouts"
+}
+
+func (t *ProcessTimeouts) Events() []EventType {
+	return []EventType{EventTypeProcessTimeoutScheduled, EventTypeProcessTimeoutFired, EventTypeProcessTimeoutCancelled}
+}
+
+// HandleEvent can be repeated safely, so the events after a checkpoint can be handled again after a crash.
+func (t *ProcessTimeouts) HandleEvent(ctx context.Context, event Event) error {
+	if event.AggregateType != t.processType {
+		return nil
+	}
+
+	switch e := event.Data.(type) {
+	case ProcessTimeoutScheduledEvent:
+		return t.store.SaveTimeout(ctx, t.processType, ProcessTimeout{
+			ProcessID: ProcessID(event.AggregateID),
+			TimeoutID: e.TimeoutID,
+			Name:      e.Name,
+			Deadline:  e.Deadline,
+		})
+	case ProcessTimeoutFiredEvent:
+		return t.remove(ctx, e.TimeoutID)
+	case ProcessTimeoutCancelledEvent:
+		return t.remove(ctx, e.TimeoutID)
+	}
+
+	return nil
+}
+
+// due returns the timeouts due at the given time, earliest first.
+func (t *ProcessTimeouts) due(ctx context.Context, now time.Time) ([]ProcessTimeout, error) {
+	due, err := t.store.DueTimeouts(ctx, t.processType, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due timeouts of %s: %w", t.processType, err)
+	}
+
+	return due, nil
+}
+
+func (t *ProcessTimeouts) remove(ctx context.Context, timeoutID string) error {
+	if err := t.store.DeleteTimeout(ctx, t.processType, timeoutID); err != nil {
+		return fmt.Errorf("failed to delete timeout %s of %s: %w", timeoutID, t.processType, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// LivenessPath reports whether the service is running
+	LivenessPath = "/healthz"
+	// ReadinessPath reports whether the service and its dependencies can serve requests
+	ReadinessPath = "/readyz"
+
+	checkTimeout = 2 * time.Second
+)
+
+const (
+	StatusOK      = "ok"
+	StatusFailing = "failing"
+)
+
+// Check returns an error if a dependency is unavailable.
+type Check func(ctx context.Context) error
+
+// CheckResult describes the outcome of a single check.
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the outcome of a set of checks, keyed by check name.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker holds the liveness and readiness checks of a service.
+// Liveness checks should only fail when restarting the service would help, while readiness checks cover
+// everything the service needs to serve requests.
+type Checker struct {
+	liveness  []namedCheck
+	readiness []namedCheck
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+func (c *Checker) AddLivenessCheck(name string, check Check) {
+	c.liveness = append(c.liveness, namedCheck{name, check})
+}
+
+func (c *Checker) AddReadinessCheck(name string, check Check) {
+	c.readiness = append(c.readiness, namedCheck{name, check})
+}
+
+// Live runs the liveness checks.
+func (c *Checker) Live(ctx context.Context) Report {
+	return run(ctx, c.liveness)
+}
+
+// Ready runs the liveness and readiness checks.
+func (c *Checker) Ready(ctx context.Context) Report {
+	return run(ctx, append(append([]namedCheck{}, c.liveness...), c.readiness...))
+}
+
+// RegisterRoutes serves the reports of the checker, with status 503 if any check fails.
+func (c *Checker) RegisterRoutes(e *echo.Echo) {
+	e.GET(LivenessPath, reportHandler(c.Live))
+	e.GET(ReadinessPath, reportHandler(c.Ready))
+}
+
+func reportHandler(report func(context.Context) Report) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		result := report(c.Request().Context())
+		if result.Status != StatusOK {
+			return c.JSON(http.StatusServiceUnavailable, result)
+		}
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
+
+// run executes the checks concurrently, giving each of them checkTimeout to finish.
+func run(ctx context.Context, checks []namedCheck) Report {
+	results := make([]CheckResult, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check(checkCtx)
+			results[i] = CheckResult{Status: StatusOK, Duration: time.Since(start).String()}
+			if err != nil {
+				results[i].Status = StatusFailing
+				results[i].Error = err.Error()
+			}
+		}(i, check.check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checks))}
+	for i, check := range checks {
+		report.Checks[check.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFailing
+		}
+	}
+
+	return report
+}
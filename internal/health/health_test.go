@@ -0,0 +1,50 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cybre/home-inventory/internal/health"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Checker(t *testing.T) {
+	checker := health.NewChecker()
+	checker.AddReadinessCheck("database", func(ctx context.Context) error {
+		return nil
+	})
+	checker.AddReadinessCheck("broker", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	e := echo.New()
+	checker.RegisterRoutes(e)
+
+	get := func(path string) (int, health.Report) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		var report health.Report
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+
+		return rec.Code, report
+	}
+
+	// Failing dependencies do not make the service unhealthy
+	code, report := get(health.LivenessPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, report.Status)
+
+	// but they make it unready, with the failing check described
+	code, report = get(health.ReadinessPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusFailing, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["database"].Status)
+	assert.Equal(t, health.StatusFailing, report.Checks["broker"].Status)
+	assert.Equal(t, "connection refused", report.Checks["broker"].Error)
+}
@@ -0,0 +1,298 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/logging"
+	"github.com/cybre/home-inventory/internal/metrics"
+)
+
+const (
+	catchUpBatchSize = 500
+	// catchUpInterval is how often a subscription reads the log without being prompted by a live event,
+	// which picks up events whose log positions were resolved late, and how often an instance not holding the
+	// checkpoint lease tries to take it over
+	catchUpInterval = 5 * time.Second
+	// checkpointLease is how long a subscription holds its checkpoint without renewing the lease
+	checkpointLease = 30 * time.Second
+)
+
+// instanceID identifies this process among the instances of the service as the holder of checkpoint leases
+var instanceID = newInstanceID()
+
+func newInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s/%d", hostname, os.Getpid())
+}
+
+// CatchUpSubscription feeds an event handler the global event log in log order, resuming from its checkpoint. It
+// reads the history from the event store first and then follows the live event stream. Live events are only used
+// as a signal to read the log again, so the handler sees each event once and in log order, and never an event
+// before an earlier one. The position of the last handled event is saved as the checkpoint, so a restart
+// continues where the subscription left off.
+//
+// Every instance of the service subscribes to live events, but only the one holding the lease on the checkpoint
+// delivers events, so the handler sees each event once across instances. The others take the lease over once it
+// is given up or expires, and then continue from the checkpoint.
+type CatchUpSubscription struct {
+	eventStore  es.EventStore
+	eventStream EventStream
+	handler     EventHandler
+	checkpoints CheckpointStore
+
+	// mu is held while the log is read. Live events arriving meanwhile set behind instead of waiting, since they
+	// may be published by the handler itself, and whoever holds mu reads the log once more before letting go.
+	mu       sync.Mutex
+	behind   atomic.Bool
+	position atomic.Uint64
+	// failures counts the failed attempts at the event after position
+	failures int
+	// leasedUntil is when the lease on the checkpoint runs out, and nextClaim when it may be claimed again after
+	// another instance held it
+	leasedUntil time.Time
+	nextClaim   time.Time
+}
+
+// NewCatchUpSubscription creates a subscription that delivers the events logged after the handler's checkpoint.
+func NewCatchUpSubscription(eventStore es.EventStore, eventStream EventStream, handler EventHandler, checkpoints CheckpointStore) *CatchUpSubscription {
+	return &CatchUpSubscription{
+		eventStore:  eventStore,
+		eventStream: eventStream,
+		handler:     handler,
+		checkpoints: checkpoints,
+	}
+}
+
+// Start delivers the history and then keeps following live events until ctx is done, when it gives up the lease on
+// the checkpoint. Should the handler fail on the history, or the checkpoint be out of reach, the failure is logged
+// and the subscription tries again later.
+func (s *CatchUpSubscription) Start(ctx context.Context) error {
+	s.mu.Lock()
+
+	// Subscribing before reading the history leaves no window for live events to be missed
+	if err := s.eventStream.StreamEvents(ctx, s.handler.Name(), s.handleLiveEvent); err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("failed to subscribe to event stream: %w", err)
+	}
+
+	err := s.catchUp(ctx)
+	s.mu.Unlock()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to catch up with event log", slog.String("subscription", s.handler.Name()), slog.Any("error", err))
+	}
+
+	s.catchUpWhileBehind(ctx)
+
+	go s.poll(ctx)
+
+	return nil
+}
+
+// Position returns the global log position of the last delivered event.
+func (s *CatchUpSubscription) Position() uint64 {
+	return s.position.Load()
+}
+
+// handleLiveEvent always succeeds, since the subscription keeps track of its position itself.
+func (s *CatchUpSubscription) handleLiveEvent(ctx context.Context, event es.Event) error {
+	// Events published before the global log existed have no position
+	if event.Position == 0 || event.Position <= s.position.Load() {
+		return nil
+	}
+
+	s.behind.Store(true)
+	s.catchUpWhileBehind(ctx)
+
+	return nil
+}
+
+// catchUpWhileBehind reads the log until no live event is left unread, unless another goroutine is reading it,
+// which then reads it once more itself.
+func (s *CatchUpSubscription) catchUpWhileBehind(ctx context.Context) {
+	for s.behind.Load() && s.mu.TryLock() {
+		s.behind.Store(false)
+		err := s.catchUp(ctx)
+		s.mu.Unlock()
+
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to catch up with event log", slog.String("subscription", s.handler.Name()), slog.Any("error", err))
+			return
+		}
+	}
+}
+
+func (s *CatchUpSubscription) poll(ctx context.Context) {
+	ticker := time.NewTicker(catchUpInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.release(ctx)
+			return
+		case <-ticker.C:
+			s.behind.Store(true)
+			s.catchUpWhileBehind(ctx)
+		}
+	}
+}
+
+// claim makes sure the subscription holds the lease on its checkpoint. The lease is renewed once half of it has
+// passed, and taken over at most every catchUpInterval while another instance holds it. Taking it over loads the
+// checkpoint, which the previous holder has moved on. It is called with mu held.
+func (s *CatchUpSubscription) claim(ctx context.Context) (bool, error) {
+	now := time.Now()
+	if s.leasedUntil.Sub(now) > checkpointLease/2 {
+		return true, nil
+	}
+
+	held := now.Before(s.leasedUntil)
+	if !held && now.Before(s.nextClaim) {
+		return false, nil
+	}
+
+	s.nextClaim = now.Add(catchUpInterval)
+	claimed, err := s.checkpoints.ClaimCheckpoint(ctx, s.handler.Name(), instanceID, checkpointLease)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim checkpoint: %w", err)
+	}
+
+	if !claimed {
+		s.leasedUntil = time.Time{}
+		return false, nil
+	}
+
+	if !held {
+		position, err := s.checkpoints.GetCheckpoint(ctx, s.handler.Name())
+		if err != nil {
+			return false, err
+		}
+
+		s.position.Store(position)
+		s.failures = 0
+	}
+
+	s.leasedUntil = now.Add(checkpointLease)
+
+	return true, nil
+}
+
+// release gives up the lease on the checkpoint, so another instance takes the subscription over right away.
+func (s *CatchUpSubscription) release(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !time.Now().Before(s.leasedUntil) {
+		return
+	}
+
+	s.leasedUntil = time.Time{}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), catchUpInterval)
+	defer cancel()
+
+	if err := s.checkpoints.ReleaseCheckpoint(ctx, s.handler.Name(), instanceID); err != nil {
+		logging.FromContext(ctx).Warn("failed to release checkpoint", slog.String("subscription", s.handler.Name()), slog.Any("error", err))
+	}
+}
+
+// catchUp delivers everything logged after the current position and saves the new checkpoint, as long as the
+// subscription holds the checkpoint lease. It stops at an event the handler fails on, which is then tried again
+// the next time the log is read.
+func (s *CatchUpSubscription) catchUp(ctx context.Context) error {
+	for {
+		if claimed, err := s.claim(ctx); err != nil || !claimed {
+			return err
+		}
+
+		checkpoint := s.position.Load()
+		events, err := s.eventStore.ReadAll(ctx, checkpoint+1, catchUpBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to read event log: %w", err)
+		}
+
+		var handleErr error
+		for _, event := range events {
+			if handleErr = s.dispatch(ctx, event); handleErr != nil {
+				break
+			}
+
+			s.position.Store(event.Position)
+		}
+
+		if s.position.Load() > checkpoint {
+			if err := s.checkpoints.SaveCheckpoint(ctx, s.handler.Name(), s.position.Load()); err != nil {
+				return errors.Join(handleErr, err)
+			}
+		}
+
+		if handleErr != nil || len(events) < catchUpBatchSize {
+			return handleErr
+		}
+	}
+}
+
+// dispatch hands the event to the handler. After eventMaxAttempts failed attempts the event is given up on, so
+// one broken event cannot hold the subscription back for good.
+func (s *CatchUpSubscription) dispatch(ctx context.Context, event es.Event) error {
+	err := handleEvent(ctx, s.handler, event)
+	if err == nil {
+		s.failures = 0
+		return nil
+	}
+
+	s.failures++
+	if s.failures < eventMaxAttempts {
+		return fmt.Errorf("failed to handle event at position %d: %w", event.Position, err)
+	}
+
+	s.failures = 0
+	metrics.EventsDeadLettered.WithLabelValues(s.handler.Name()).Inc()
+	logging.FromContext(ctx).Error(
+		"giving up on event",
+		slog.String("subscription", s.handler.Name()),
+		slog.Uint64("position", event.Position),
+		slog.Int("attempts", eventMaxAttempts),
+		slog.Any("error", err),
+	)
+
+	return nil
+}
+
+// CatchUpEventConsumer delivers events to handlers through catch-up subscriptions, so a handler also gets the
+// events it missed while the service was down, for instance those stored right before a crash but never published.
+type CatchUpEventConsumer struct {
+	eventStore  es.EventStore
+	eventStream EventStream
+	checkpoints CheckpointStore
+}
+
+func NewCatchUpEventConsumer(eventStore es.EventStore, eventStream EventStream, checkpoints CheckpointStore) *CatchUpEventConsumer {
+	return &CatchUpEventConsumer{
+		eventStore:  eventStore,
+		eventStream: eventStream,
+		checkpoints: checkpoints,
+	}
+}
+
+func (c *CatchUpEventConsumer) ConsumeEvents(ctx context.Context, handler EventHandler) error {
+	return NewCatchUpSubscription(c.eventStore, c.eventStream, handler, c.checkpoints).Start(ctx)
+}
+
+// StreamEvents passes the live events of the underlying stream on, for subscribers keeping track of their
+// position themselves.
+func (c *CatchUpEventConsumer) StreamEvents(ctx context.Context, name string, callback func(ctx context.Context, event es.Event) error) error {
+	return c.eventStream.StreamEvents(ctx, name, callback)
+}
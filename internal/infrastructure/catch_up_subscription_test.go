@@ -0,0 +1,194 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/infrastructure"
+	"github.com/cybre/home-inventory/internal/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingHandler struct {
+	names []string
+}
+
+func (h *recordingHandler) HandleEvent(ctx context.Context, event es.Event) error {
+	h.names = append(h.names, event.Data.(testEvent).Name)
+	return nil
+}
+
+func (h *recordingHandler) Events() []es.EventType {
+	return []es.EventType{testEvent{}.EventType()}
+}
+
+func (h *recordingHandler) Name() string {
+	return "RecordingHandler"
+}
+
+// failingHandler fails on every event with the name it fails on.
+type failingHandler struct {
+	recordingHandler
+	failOn   string
+	attempts int
+}
+
+func (h *failingHandler) HandleEvent(ctx context.Context, event es.Event) error {
+	if event.Data.(testEvent).Name == h.failOn {
+		h.attempts++
+		return errors.New("broken")
+	}
+
+	return h.recordingHandler.HandleEvent(ctx, event)
+}
+
+func (h *failingHandler) Name() string {
+	return "FailingHandler"
+}
+
+func Test_CatchUpSubscription(t *testing.T) {
+	ctx := context.Background()
+	es.RegisterEvent(testEvent{})
+
+	db, err := sqlite.NewDB(filepath.Join(t.TempDir(), "events.db"), "test")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	keyStore, err := infrastructure.NewSQLitePersonalDataKeyStore(db)
+	assert.NoError(t, err)
+
+	eventStore, err := infrastructure.NewSQLiteEventStore(db, infrastructure.NewPersonalDataProtector(keyStore))
+	assert.NoError(t, err)
+
+	messaging := infrastructure.NewInProcessEventMessaging()
+
+	store := func(version uint, name string) es.Event {
+		data := testEvent{ID: "1", UserID: "user-1", Name: name}
+		events := []es.Event{{AggregateType: "Test", AggregateID: "aggregate-1", EventType: data.EventType(), Data: data, Timestamp: 1, Version: version}}
+		assert.NoError(t, eventStore.StoreEvents(ctx, events))
+
+		return events[0]
+	}
+
+	first, second := store(1, "Summer House"), store(2, "Winter House")
+	assert.Equal(t, uint64(1), first.Position)
+	assert.Equal(t, uint64(2), second.Position)
+
+	handler := &recordingHandler{}
+	checkpoints, err := infrastructure.NewSQLiteCheckpointStore(db)
+	assert.NoError(t, err)
+
+	subscription := infrastructure.NewCatchUpSubscription(eventStore, messaging, handler, checkpoints)
+	assert.NoError(t, subscription.Start(ctx))
+
+	// The history is delivered first
+	assert.Equal(t, []string{"Summer House", "Winter House"}, handler.names)
+	assert.Equal(t, uint64(2), subscription.Position())
+
+	// Then live events, once each
+	third := store(3, "Beach House")
+	assert.NoError(t, messaging.PublishEvents(ctx, []es.Event{third}))
+	assert.NoError(t, messaging.PublishEvents(ctx, []es.Event{second, third}))
+	assert.Equal(t, []string{"Summer House", "Winter House", "Beach House"}, handler.names)
+
+	// An event missing from the stream is read back from the store
+	fourth, fifth := store(4, "Lake House"), store(5, "City Flat")
+	assert.NoError(t, messaging.PublishEvents(ctx, []es.Event{fifth, fourth}))
+	assert.Equal(t, []string{"Summer House", "Winter House", "Beach House", "Lake House", "City Flat"}, handler.names)
+	assert.Equal(t, uint64(5), subscription.Position())
+
+	// The position is saved as the checkpoint, so a restarted subscription only sees what came after it
+	position, err := checkpoints.GetCheckpoint(ctx, handler.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), position)
+
+	assert.NoError(t, checkpoints.SaveCheckpoint(ctx, handler.Name(), 3))
+	resumed := &recordingHandler{}
+	assert.NoError(t, infrastructure.NewCatchUpSubscription(eventStore, messaging, resumed, checkpoints).Start(ctx))
+	assert.Equal(t, []string{"Lake House", "City Flat"}, resumed.names)
+
+	events, err := eventStore.ReadAll(ctx, 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []es.Event{second, third}, events)
+
+	t.Run("stops at a failing event and gives up on it after the last attempt", func(t *testing.T) {
+		failing := &failingHandler{failOn: "Broken House"}
+		subscription := infrastructure.NewCatchUpSubscription(eventStore, messaging, failing, checkpoints)
+		assert.NoError(t, subscription.Start(ctx))
+		assert.Equal(t, uint64(5), subscription.Position())
+
+		broken := store(6, "Broken House")
+		for i := 0; i < 4; i++ {
+			assert.NoError(t, messaging.PublishEvents(ctx, []es.Event{broken}))
+		}
+		assert.Equal(t, 4, failing.attempts)
+		assert.Equal(t, uint64(5), subscription.Position())
+
+		position, err := checkpoints.GetCheckpoint(ctx, failing.Name())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(5), position)
+
+		after := store(7, "Tiny House")
+		assert.NoError(t, messaging.PublishEvents(ctx, []es.Event{after}))
+		assert.Equal(t, 5, failing.attempts)
+		assert.Equal(t, uint64(7), subscription.Position())
+		assert.Equal(t, "Tiny House", failing.names[len(failing.names)-1])
+	})
+
+	t.Run("leaves the events to the instance holding the checkpoint lease", func(t *testing.T) {
+		claimed, err := checkpoints.ClaimCheckpoint(ctx, "IdleHandler", "other-instance", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+
+		idle := &idleHandler{}
+		subscription := infrastructure.NewCatchUpSubscription(eventStore, messaging, idle, checkpoints)
+		assert.NoError(t, subscription.Start(ctx))
+		assert.NoError(t, messaging.PublishEvents(ctx, []es.Event{store(8, "Tree House")}))
+		assert.Empty(t, idle.names)
+		assert.Equal(t, uint64(0), subscription.Position())
+	})
+}
+
+type idleHandler struct {
+	recordingHandler
+}
+
+func (h *idleHandler) Name() string {
+	return "IdleHandler"
+}
+
+func Test_SQLiteCheckpointStore_ClaimCheckpoint(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite.NewDB(filepath.Join(t.TempDir(), "events.db"), "test")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	checkpoints, err := infrastructure.NewSQLiteCheckpointStore(db)
+	assert.NoError(t, err)
+
+	claim := func(owner string, lease time.Duration) bool {
+		claimed, err := checkpoints.ClaimCheckpoint(ctx, "Projector", owner, lease)
+		assert.NoError(t, err)
+
+		return claimed
+	}
+
+	assert.True(t, claim("a", 50*time.Millisecond))
+	assert.False(t, claim("b", time.Minute), "a lease held by another owner cannot be taken")
+	assert.True(t, claim("a", 50*time.Millisecond), "the owner can renew its lease")
+
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, claim("b", time.Minute), "an expired lease can be taken over")
+	assert.False(t, claim("a", time.Minute))
+
+	assert.NoError(t, checkpoints.ReleaseCheckpoint(ctx, "Projector", "a"))
+	assert.False(t, claim("a", time.Minute), "only the owner can release its lease")
+
+	assert.NoError(t, checkpoints.ReleaseCheckpoint(ctx, "Projector", "b"))
+	assert.True(t, claim("a", time.Minute), "a released lease can be taken right away")
+}
@@ -0,0 +1,126 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// CheckpointStore remembers how far subscriptions got in the global event log, so they resume there after a restart.
+// Instances of the service take turns at a subscription through a lease on its checkpoint, so only one of them
+// moves it at a time.
+type CheckpointStore interface {
+	// GetCheckpoint returns the position of the last event the subscription handled, or 0 if it has not handled any.
+	GetCheckpoint(ctx context.Context, subscription string) (uint64, error)
+	SaveCheckpoint(ctx context.Context, subscription string, position uint64) error
+	// ClaimCheckpoint takes the lease on the subscription's checkpoint for owner, or renews it, unless another owner
+	// holds it. It reports whether owner holds the lease, which then lasts for lease.
+	ClaimCheckpoint(ctx context.Context, subscription, owner string, lease time.Duration) (bool, error)
+	// ReleaseCheckpoint gives up the lease on the subscription's checkpoint if owner holds it.
+	ReleaseCheckpoint(ctx context.Context, subscription, owner string) error
+}
+
+// CassandraCheckpointStore keeps subscription checkpoints next to the Cassandra event log.
+type CassandraCheckpointStore struct {
+	session *gocql.Session
+}
+
+func NewCassandraCheckpointStore(session *gocql.Session) (*CassandraCheckpointStore, error) {
+	checkpointStore := &CassandraCheckpointStore{
+		session: session,
+	}
+
+	if err := checkpointStore.init(); err != nil {
+		return nil, err
+	}
+
+	return checkpointStore, nil
+}
+
+func (s CassandraCheckpointStore) GetCheckpoint(ctx context.Context, subscription string) (uint64, error) {
+	var position int64
+	if err := s.session.Query("SELECT position FROM subscription_checkpoints WHERE subscription = ?", subscription).WithContext(ctx).Scan(&position); err != nil {
+		if err == gocql.ErrNotFound {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("failed to get checkpoint of %s: %w", subscription, err)
+	}
+
+	return uint64(position), nil
+}
+
+func (s CassandraCheckpointStore) SaveCheckpoint(ctx context.Context, subscription string, position uint64) error {
+	if err := s.session.Query("INSERT INTO subscription_checkpoints (subscription, position) VALUES (?, ?)", subscription, int64(position)).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to save checkpoint of %s: %w", subscription, err)
+	}
+
+	return nil
+}
+
+func (s CassandraCheckpointStore) ClaimCheckpoint(ctx context.Context, subscription, owner string, lease time.Duration) (bool, error) {
+	// The lease expires on its own should its owner stop renewing it. TTLs are whole seconds, and 0 would keep it forever.
+	ttl := max(int(lease.Seconds()), 1)
+	claimed, err := s.session.Query(
+		"INSERT INTO subscription_leases (subscription, owner) VALUES (?, ?) IF NOT EXISTS USING TTL ?",
+		subscription,
+		owner,
+		ttl,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return false, fmt.Errorf("failed to claim checkpoint of %s: %w", subscription, err)
+	}
+
+	if claimed {
+		return true, nil
+	}
+
+	renewed, err := s.session.Query(
+		"UPDATE subscription_leases USING TTL ? SET owner = ? WHERE subscription = ? IF owner = ?",
+		ttl,
+		owner,
+		subscription,
+		owner,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return false, fmt.Errorf("failed to renew checkpoint lease of %s: %w", subscription, err)
+	}
+
+	return renewed, nil
+}
+
+func (s CassandraCheckpointStore) ReleaseCheckpoint(ctx context.Context, subscription, owner string) error {
+	if _, err := s.session.Query(
+		"DELETE FROM subscription_leases WHERE subscription = ? IF owner = ?",
+		subscription,
+		owner,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{}); err != nil {
+		return fmt.Errorf("failed to release checkpoint of %s: %w", subscription, err)
+	}
+
+	return nil
+}
+
+func (s CassandraCheckpointStore) init() error {
+	if err := s.session.Query(
+		`CREATE TABLE IF NOT EXISTS subscription_checkpoints (
+			subscription text PRIMARY KEY,
+			position bigint
+		)`,
+	).Exec(); err != nil {
+		return fmt.Errorf("failed to create subscription_checkpoints table: %w", err)
+	}
+
+	if err := s.session.Query(
+		`CREATE TABLE IF NOT EXISTS subscription_leases (
+			subscription text PRIMARY KEY,
+			owner text
+		)`,
+	).Exec(); err != nil {
+		return fmt.Errorf("failed to create subscription_leases table: %w", err)
+	}
+
+	return nil
+}
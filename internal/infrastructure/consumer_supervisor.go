@@ -0,0 +1,160 @@
+package infrastructure
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cybre/home-inventory/internal/kafka"
+	"github.com/cybre/home-inventory/internal/logging"
+)
+
+const (
+	consumerMinBackoff = time.Second
+	consumerMaxBackoff = time.Minute
+	// consumerStableAfter is how long a consumer has to run before its next failure starts the backoff over
+	consumerStableAfter = time.Minute
+	// consumerStuckAfter is how long a consumer may stay down, restarting, before it is reported as stuck
+	consumerStuckAfter = 5 * time.Minute
+)
+
+// ConsumerStatus describes the state of a supervised consumer.
+type ConsumerStatus struct {
+	Name        string     `json:"name"`
+	Running     bool       `json:"running"`
+	Restarts    int        `json:"restarts"`
+	LastError   string     `json:"lastError,omitempty"`
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+	// DownSince is when the consumer last stopped, while it is not running
+	DownSince *time.Time `json:"downSince,omitempty"`
+}
+
+// consumerSupervisor keeps consumers running. A consumer that stops with an error is replaced by a new member of
+// the same group after an exponential backoff, which also makes it pick up again from the last committed offsets.
+type consumerSupervisor struct {
+	mu       sync.Mutex
+	statuses map[string]*ConsumerStatus
+	cancels  []context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func newConsumerSupervisor() *consumerSupervisor {
+	return &consumerSupervisor{
+		statuses: map[string]*ConsumerStatus{},
+	}
+}
+
+// start keeps consumers created by newConsumer running in the background until ctx is done or the supervisor is closed.
+func (s *consumerSupervisor) start(ctx context.Context, name string, newConsumer func() (*kafka.Consumer, error), callback func(context.Context, kafka.Record) error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	s.mu.Lock()
+	s.statuses[name] = &ConsumerStatus{Name: name}
+	s.cancels = append(s.cancels, cancel)
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.supervise(ctx, name, newConsumer, callback)
+	}()
+}
+
+func (s *consumerSupervisor) supervise(ctx context.Context, name string, newConsumer func() (*kafka.Consumer, error), callback func(context.Context, kafka.Record) error) {
+	logger := logging.FromContext(ctx).With(slog.String("consumer", name))
+
+	backoff := consumerMinBackoff
+	for {
+		startedAt := time.Now()
+
+		err := s.run(ctx, name, newConsumer, callback)
+		if ctx.Err() != nil {
+			s.setRunning(name, false)
+			return
+		}
+
+		if time.Since(startedAt) > consumerStableAfter {
+			backoff = consumerMinBackoff
+		}
+
+		s.recordFailure(name, err)
+		logger.Error("event consumer stopped, restarting", slog.Any("error", err), slog.Duration("backoff", backoff))
+
+		select {
+		case <-ctx.Done():
+			s.setRunning(name, false)
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, consumerMaxBackoff)
+	}
+}
+
+func (s *consumerSupervisor) run(ctx context.Context, name string, newConsumer func() (*kafka.Consumer, error), callback func(context.Context, kafka.Record) error) error {
+	consumer, err := newConsumer()
+	if err != nil {
+		return err
+	}
+
+	defer consumer.Close()
+
+	s.setRunning(name, true)
+
+	return consumer.Consume(ctx, callback)
+}
+
+func (s *consumerSupervisor) recordFailure(name string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.statuses[name]
+	status.Running = false
+	status.Restarts++
+	status.LastError = err.Error()
+	now := time.Now()
+	status.LastErrorAt = &now
+	if status.DownSince == nil {
+		status.DownSince = &now
+	}
+}
+
+func (s *consumerSupervisor) setRunning(name string, running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.statuses[name].Running = running
+	if running {
+		s.statuses[name].DownSince = nil
+	}
+}
+
+// Statuses returns the state of every supervised consumer, ordered by name.
+func (s *consumerSupervisor) Statuses() []ConsumerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]ConsumerStatus, 0, len(s.statuses))
+	for _, status := range s.statuses {
+		statuses = append(statuses, *status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
+
+// close stops all consumers and waits for them to finish.
+func (s *consumerSupervisor) close() {
+	s.mu.Lock()
+	for _, cancel := range s.cancels {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
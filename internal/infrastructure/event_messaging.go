@@ -2,55 +2,101 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
+	"time"
 
 	"github.com/cybre/home-inventory/internal/eventsourcing"
 	"github.com/cybre/home-inventory/internal/kafka"
 	"github.com/cybre/home-inventory/internal/logging"
+	"github.com/cybre/home-inventory/internal/metrics"
 	"github.com/cybre/home-inventory/internal/utils"
 )
 
+const (
+	// eventMaxAttempts is how many times an event is handled before it is given up on
+	eventMaxAttempts = 5
+	// eventRetryBackoff is how long a Kafka consumer waits before handling an event again, doubled on every attempt
+	eventRetryBackoff = time.Second
+	// deadLetterTopicSuffix names the topic events given up on are moved to, after the topic they came from
+	deadLetterTopicSuffix = ".dead-letter"
+)
+
+// Kafka record headers describing why a record was moved to the dead letter topic
+const (
+	deadLetterConsumerHeader = "dead-letter-consumer"
+	deadLetterErrorHeader    = "dead-letter-error"
+)
+
 type EventHandler interface {
-	HandleEvent(ctx context.Context, event eventsourcing.EventData) error
+	HandleEvent(ctx context.Context, event eventsourcing.Event) error
 	Events() []eventsourcing.EventType
 	Name() string
 }
 
+// EventStream delivers every published event, in full, to a named subscriber.
+// An event the callback fails on is delivered again if the stream supports redelivery.
+type EventStream interface {
+	StreamEvents(ctx context.Context, name string, callback func(ctx context.Context, event eventsourcing.Event) error) error
+}
+
 type KafkaEventMessaging struct {
-	producer      *kafka.Producer
-	brokers       []string
-	topic         string
-	eventHandlers []EventHandler
-	consumers     []*kafka.Consumer
+	producer              *kafka.Producer
+	deadLetterProducer    *kafka.Producer
+	personalDataProtector *PersonalDataProtector
+	brokers               []string
+	topic                 string
+	consumerWorkers       int
+	eventHandlers         []EventHandler
+	supervisor            *consumerSupervisor
 }
 
-func NewKafkaEventMessaging(brokers []string, topic string, logger *slog.Logger) (*KafkaEventMessaging, error) {
+// NewKafkaEventMessaging creates the messaging for a topic. Every consumer handles events with consumerWorkers
+// workers, and events of the same aggregate are always handled by the same worker, in order. Personal data is
+// published encrypted, as it is stored, so destroying a user's key also makes it unreadable in the topic.
+// Records a consumer keeps failing on are moved, as they are, to the topic's dead letter topic.
+func NewKafkaEventMessaging(brokers []string, topic string, consumerWorkers int, personalDataProtector *PersonalDataProtector, logger *slog.Logger) (*KafkaEventMessaging, error) {
 	producer, err := kafka.NewProducer(brokers, topic)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kafka producer: %w", err)
 	}
 
+	deadLetterProducer, err := kafka.NewProducer(brokers, topic+deadLetterTopicSuffix)
+	if err != nil {
+		producer.Close()
+		return nil, fmt.Errorf("failed to create kafka dead letter producer: %w", err)
+	}
+
 	return &KafkaEventMessaging{
-		producer:      producer,
-		brokers:       brokers,
-		topic:         topic,
-		eventHandlers: []EventHandler{},
-		consumers:     []*kafka.Consumer{},
+		producer:              producer,
+		deadLetterProducer:    deadLetterProducer,
+		personalDataProtector: personalDataProtector,
+		brokers:               brokers,
+		topic:                 topic,
+		consumerWorkers:       consumerWorkers,
+		eventHandlers:         []EventHandler{},
+		supervisor:            newConsumerSupervisor(),
 	}, nil
 }
 
 func (p *KafkaEventMessaging) PublishEvents(ctx context.Context, events []eventsourcing.Event) error {
 	records, err := utils.MapWithError(events, func(i uint, event eventsourcing.Event) (kafka.Record, error) {
+		event, err := p.protect(ctx, event)
+		if err != nil {
+			return kafka.Record{}, err
+		}
+
 		eventBytes, err := event.Marshal()
 		if err != nil {
 			return kafka.Record{}, fmt.Errorf("failed to marshal event: %w", err)
 		}
 
 		return kafka.Record{
-			Key:   event.AggregateID.Marshal(),
-			Value: eventBytes,
+			Key:     event.AggregateID.Marshal(),
+			Value:   eventBytes,
+			Headers: metadataHeaders(event.Metadata),
 		}, nil
 	})
 	if err != nil {
@@ -64,51 +110,265 @@ func (p *KafkaEventMessaging) PublishEvents(ctx context.Context, events []events
 	return nil
 }
 
+// ConsumeEvents feeds the handler the events it handles. An event is only acknowledged once the handler
+// succeeds or it is moved to the dead letter topic, so handlers see every event at least once and have to
+// tolerate duplicates.
 func (c *KafkaEventMessaging) ConsumeEvents(ctx context.Context, handler EventHandler) error {
-	kafkaConsumer, err := kafka.NewConsumer(
-		c.brokers,
-		c.topic,
-		handler.Name(),
+	return c.StreamEvents(ctx, handler.Name(), func(ctx context.Context, event eventsourcing.Event) error {
+		return handleEvent(ctx, handler, event)
+	})
+}
+
+// StreamEvents starts a supervised consumer group member, which is restarted if it stops with an error. A record
+// is handled up to eventMaxAttempts times, with a growing backoff, and then moved to the dead letter topic, so a
+// record that always fails does not block its partition.
+func (c *KafkaEventMessaging) StreamEvents(ctx context.Context, name string, callback func(ctx context.Context, event eventsourcing.Event) error) error {
+	logger := logging.FromContext(ctx).With(slog.String("consumer", name))
+
+	c.supervisor.start(
+		ctx,
+		name,
+		func() (*kafka.Consumer, error) {
+			kafkaConsumer, err := kafka.NewConsumer(c.brokers, c.topic, name, c.consumerWorkers)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create kafka consumer: %w", err)
+			}
+
+			return kafkaConsumer, nil
+		},
+		func(ctx context.Context, record kafka.Record) error {
+			event, err := eventsourcing.UnmarshalEvent(record.Value)
+			if err != nil {
+				// Retrying cannot make the record readable
+				return c.deadLetter(ctx, logger, name, record, fmt.Errorf("failed to unmarshal event: %w", err))
+			}
+
+			event.Metadata = metadataFromHeaders(record.Headers)
+
+			backoff := eventRetryBackoff
+			for attempt := 1; ; attempt++ {
+				if err = c.deliver(ctx, event, callback); err == nil {
+					return nil
+				}
+
+				if attempt == eventMaxAttempts {
+					return c.deadLetter(ctx, logger, name, record, err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return err
+				case <-time.After(backoff):
+				}
+
+				backoff *= 2
+			}
+		},
+	)
+
+	return nil
+}
+
+// Broadcast returns a stream delivering every event published from now on to each of its subscribers, on every
+// instance of the service, whereas StreamEvents shares the events among the instances. Nothing is redelivered, so
+// it suits subscribers that only take events as a signal, like catch-up subscriptions.
+func (c *KafkaEventMessaging) Broadcast() EventStream {
+	return kafkaBroadcast{messaging: c}
+}
+
+type kafkaBroadcast struct {
+	messaging *KafkaEventMessaging
+}
+
+// StreamEvents starts a supervised broadcast consumer. Failed events are only logged, and a restarted consumer
+// starts at the end of the topic again.
+func (b kafkaBroadcast) StreamEvents(ctx context.Context, name string, callback func(ctx context.Context, event eventsourcing.Event) error) error {
+	c := b.messaging
+	logger := logging.FromContext(ctx).With(slog.String("consumer", name))
+
+	c.supervisor.start(
+		ctx,
+		name,
+		func() (*kafka.Consumer, error) {
+			kafkaConsumer, err := kafka.NewBroadcastConsumer(c.brokers, c.topic, name, c.consumerWorkers)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create kafka consumer: %w", err)
+			}
+
+			return kafkaConsumer, nil
+		},
+		func(ctx context.Context, record kafka.Record) error {
+			event, err := eventsourcing.UnmarshalEvent(record.Value)
+			if err == nil {
+				event.Metadata = metadataFromHeaders(record.Headers)
+				err = c.deliver(ctx, event, callback)
+			}
+
+			if err != nil {
+				logger.Error("failed to deliver broadcast event", slog.String("event_id", record.Headers[eventIDHeader]), slog.Any("error", err))
+			}
+
+			return nil
+		},
 	)
+
+	return nil
+}
+
+func (c *KafkaEventMessaging) deliver(ctx context.Context, event eventsourcing.Event, callback func(ctx context.Context, event eventsourcing.Event) error) error {
+	event, err := c.unprotect(ctx, event)
 	if err != nil {
-		return fmt.Errorf("failed to create kafka consumer: %w", err)
+		return err
 	}
 
-	c.consumers = append(c.consumers, kafkaConsumer)
+	return callback(ctx, event)
+}
 
-	go kafkaConsumer.Consume(ctx, func(record kafka.Record) {
-		logger := logging.FromContext(ctx)
+// deadLetter moves a record the consumer gave up on to the dead letter topic. Should that fail, the error is
+// returned, so the record is delivered again rather than lost.
+func (c *KafkaEventMessaging) deadLetter(ctx context.Context, logger *slog.Logger, name string, record kafka.Record, cause error) error {
+	headers := metadataHeaders(metadataFromHeaders(record.Headers))
+	headers[deadLetterConsumerHeader] = name
+	headers[deadLetterErrorHeader] = cause.Error()
 
-		event, err := eventsourcing.UnmarshalEvent(record.Value)
-		if err != nil {
-			logger.Error("failed to unmarshal event", slog.Any("error", err))
-			return
-		}
+	if err := c.deadLetterProducer.Produce(ctx, kafka.Record{Key: record.Key, Value: record.Value, Headers: headers}); err != nil {
+		return fmt.Errorf("failed to move event to dead letter topic: %w", errors.Join(cause, err))
+	}
+
+	metrics.EventsDeadLettered.WithLabelValues(name).Inc()
+	logger.Error("moved event to dead letter topic", slog.String("event_id", record.Headers[eventIDHeader]), slog.Any("error", cause))
 
-		if !slices.Contains(handler.Events(), event.EventType) {
-			return
+	return nil
+}
+
+func (p *KafkaEventMessaging) protect(ctx context.Context, event eventsourcing.Event) (eventsourcing.Event, error) {
+	data, err := p.personalDataProtector.Protect(ctx, event.Data)
+	if err != nil {
+		return eventsourcing.Event{}, fmt.Errorf("failed to protect event data: %w", err)
+	}
+
+	metadata, err := p.personalDataProtector.ProtectMetadata(ctx, event.Metadata)
+	if err != nil {
+		return eventsourcing.Event{}, fmt.Errorf("failed to protect event metadata: %w", err)
+	}
+
+	event.Data = data
+	event.Metadata = metadata
+
+	return event, nil
+}
+
+func (c *KafkaEventMessaging) unprotect(ctx context.Context, event eventsourcing.Event) (eventsourcing.Event, error) {
+	data, err := c.personalDataProtector.Unprotect(ctx, event.Data)
+	if err != nil {
+		return eventsourcing.Event{}, fmt.Errorf("failed to unprotect event data: %w", err)
+	}
+
+	metadata, err := c.personalDataProtector.UnprotectMetadata(ctx, event.Metadata)
+	if err != nil {
+		return eventsourcing.Event{}, fmt.Errorf("failed to unprotect event metadata: %w", err)
+	}
+
+	event.Data = data
+	event.Metadata = metadata
+
+	return event, nil
+}
+
+// ConsumerStatuses reports the state of every consumer started on the messaging.
+func (c *KafkaEventMessaging) ConsumerStatuses() []ConsumerStatus {
+	return c.supervisor.Statuses()
+}
+
+// Ping checks that the messaging can reach the Kafka cluster.
+func (c *KafkaEventMessaging) Ping(ctx context.Context) error {
+	return c.producer.Ping(ctx)
+}
+
+// CheckConsumers fails if any consumer started on the messaging has been down for longer than consumerStuckAfter.
+// The supervisor restarts failing consumers on its own, so a consumer is only reported once restarting it has not
+// helped for a while, which makes the check fit for liveness: restarting the service is the remaining remedy.
+func (c *KafkaEventMessaging) CheckConsumers(ctx context.Context) error {
+	errs := []error{}
+	for _, status := range c.ConsumerStatuses() {
+		if status.Running || status.DownSince == nil || time.Since(*status.DownSince) < consumerStuckAfter {
+			continue
 		}
 
-		handlerLogger := logger.With(
-			slog.String("event_handler", handler.Name()),
-			slog.Any("event_type", event.EventType),
-		)
-
-		handlerContext := logging.WithLogger(
-			ctx,
-			handlerLogger,
-		)
-		if err := handler.HandleEvent(handlerContext, event.Data); err != nil {
-			handlerLogger.Error("failed to handle event", slog.Any("error", err))
+		errs = append(errs, fmt.Errorf("consumer %s has been down since %s, last error: %s", status.Name, status.DownSince.Format(time.RFC3339), status.LastError))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Kafka record headers carrying the event metadata
+const (
+	eventIDHeader       = "event-id"
+	correlationIDHeader = "correlation-id"
+	causationIDHeader   = "causation-id"
+	actorIDHeader       = "actor-id"
+	sourceHeader        = "source"
+)
+
+func metadataHeaders(metadata eventsourcing.EventMetadata) map[string]string {
+	headers := map[string]string{}
+	for key, value := range map[string]string{
+		eventIDHeader:       metadata.EventID,
+		correlationIDHeader: metadata.CorrelationID,
+		causationIDHeader:   metadata.CausationID,
+		actorIDHeader:       metadata.ActorID,
+		sourceHeader:        metadata.Source,
+	} {
+		if value != "" {
+			headers[key] = value
 		}
-	})
+	}
+
+	return headers
+}
+
+func metadataFromHeaders(headers map[string]string) eventsourcing.EventMetadata {
+	return eventsourcing.EventMetadata{
+		EventID:       headers[eventIDHeader],
+		CorrelationID: headers[correlationIDHeader],
+		CausationID:   headers[causationIDHeader],
+		ActorID:       headers[actorIDHeader],
+		Source:        headers[sourceHeader],
+	}
+}
+
+// handleEvent passes the event to the handler, if it handles that type of event. The handler context carries the
+// event's correlation, makes the event the cause of any commands the handler dispatches, and has a logger describing
+// both the handler and the event. Failures are logged and returned.
+func handleEvent(ctx context.Context, handler EventHandler, event eventsourcing.Event) error {
+	if !slices.Contains(handler.Events(), event.EventType) {
+		return nil
+	}
+
+	handlerLogger := logging.FromContext(ctx).With(
+		slog.String("event_handler", handler.Name()),
+		slog.Any("event_type", event.EventType),
+		slog.String("event_id", event.Metadata.EventID),
+		slog.String("correlation_id", event.Metadata.CorrelationID),
+	)
+
+	handlerContext := logging.WithLogger(
+		eventsourcing.WithCausingEvent(ctx, event),
+		handlerLogger,
+	)
+
+	metrics.EventsHandled.WithLabelValues(handler.Name()).Inc()
+	if err := handler.HandleEvent(handlerContext, event); err != nil {
+		metrics.EventHandlerErrors.WithLabelValues(handler.Name()).Inc()
+		handlerLogger.Error("failed to handle event", slog.Any("error", err))
+		return err
+	}
 
 	return nil
 }
 
 func (c *KafkaEventMessaging) Close() {
-	for _, consumer := range c.consumers {
-		consumer.Close()
-	}
+	c.supervisor.close()
 	c.producer.Close()
+	c.deadLetterProducer.Close()
 }
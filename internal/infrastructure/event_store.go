@@ -3,31 +3,95 @@ package infrastructure
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math/rand"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/logging"
 	"github.com/gocql/gocql"
 )
 
+const (
+	// eventLogSequence is the row of event_log_sequence holding the last allocated global position
+	eventLogSequence = "global"
+	// eventLogBackfilled is the row of event_log_sequence marking that events stored before the log existed were logged
+	eventLogBackfilled = "backfilled"
+	// eventLogBackfillLock is the row of event_log_sequence held by the instance running the backfill
+	eventLogBackfillLock = "backfill"
+	// eventLogBackfillLockTTL is how long the backfill lock outlives its last renewal
+	eventLogBackfillLockTTL = time.Minute
+	// eventLogBackfillPollInterval is how often instances waiting for the backfill check whether it is done
+	eventLogBackfillPollInterval = 5 * time.Second
+	// eventLogBucketSize is the number of consecutive positions kept in one event_log partition
+	eventLogBucketSize = 10000
+	// eventLogResolveAfter is how long readers wait for a reserved position to be written before giving it up
+	eventLogResolveAfter = time.Minute
+	// eventLogWriteDeadline is how long a writer may take to store its events after reserving their positions,
+	// which leaves readers a margin before they give the positions up
+	eventLogWriteDeadline = eventLogResolveAfter / 2
+
+	eventLogPending   = "pending"
+	eventLogCommitted = "committed"
+	eventLogSkipped   = "skipped"
+)
+
+// CassandraEventStore keeps each aggregate's events in its own event_store partition and in a global event_log.
+// Cassandra cannot write both tables atomically, so writers reserve log positions first: the events go into the
+// log as pending, then into event_store together with their positions, and are then marked committed. Readers
+// stop at a pending position or a hole and wait for the writer. They resolve it themselves once event_store holds
+// the event, or give the position up once it is older than eventLogResolveAfter. A writer whose position was given
+// up after its event was stored logs the event again at a new position, so the log never misses an event.
 type CassandraEventStore struct {
-	session *gocql.Session
+	session               *gocql.Session
+	personalDataProtector *PersonalDataProtector
 }
 
-func NewCassandraEventStore(session *gocql.Session) (*CassandraEventStore, error) {
+// eventLogEntry is a row of event_log.
+type eventLogEntry struct {
+	position      uint64
+	status        string
+	reservedAt    time.Time
+	aggregateType string
+	aggregateID   gocql.UUID
+	eventType     string
+	eventData     string
+	timestamp     int64
+	version       uint
+	metadata      string
+}
+
+func NewCassandraEventStore(session *gocql.Session, personalDataProtector *PersonalDataProtector) (*CassandraEventStore, error) {
 	eventStore := &CassandraEventStore{
-		session: session,
+		session:               session,
+		personalDataProtector: personalDataProtector,
 	}
 
-	eventStore.init()
+	if err := eventStore.init(); err != nil {
+		return nil, err
+	}
 
 	return eventStore, nil
 }
 
 func (ces CassandraEventStore) StoreEvents(ctx context.Context, events []es.Event) error {
-	batch := ces.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
-	for _, event := range events {
-		eventData, err := json.Marshal(event.Data)
+	if len(events) == 0 {
+		return nil
+	}
+
+	entries := make([]eventLogEntry, len(events))
+	for i, event := range events {
+		data, err := encodeEventData(ctx, ces.personalDataProtector, event.Data)
+		if err != nil {
+			return err
+		}
+
+		metadata, err := encodeEventMetadata(ctx, ces.personalDataProtector, event.Metadata)
 		if err != nil {
 			return err
 		}
@@ -37,31 +101,280 @@ func (ces CassandraEventStore) StoreEvents(ctx context.Context, events []es.Even
 			return err
 		}
 
+		entries[i] = eventLogEntry{
+			aggregateType: string(event.AggregateType),
+			aggregateID:   aggregateID,
+			eventType:     string(event.EventType),
+			eventData:     string(data),
+			timestamp:     event.Timestamp,
+			version:       event.Version,
+			metadata:      metadata,
+		}
+	}
+
+	firstPosition, err := ces.allocatePositions(ctx, len(events))
+	if err != nil {
+		return err
+	}
+
+	reservedAt := time.Now()
+	for i := range entries {
+		entries[i].position = firstPosition + uint64(i)
+		entries[i].reservedAt = reservedAt
+	}
+
+	if err := ces.reserveLogEntries(ctx, entries); err != nil {
+		return err
+	}
+
+	if time.Since(reservedAt) > eventLogWriteDeadline {
+		ces.skipLogEntries(ctx, entries)
+		return fmt.Errorf("failed to store events of %s %s: event log positions were reserved too long ago", events[0].AggregateType, events[0].AggregateID)
+	}
+
+	batch := ces.session.NewBatch(gocql.LoggedBatch).WithContext(ctx)
+	for _, entry := range entries {
 		batch.Query(
-			"INSERT INTO event_store (aggregate_type, aggregate_id, event_type, event_data, timestamp, version) VALUES (?, ?, ?, ?, ?, ?) IF NOT EXISTS",
-			event.AggregateType,
-			aggregateID,
-			event.EventType,
-			eventData,
-			event.Timestamp,
-			event.Version,
+			"INSERT INTO event_store (aggregate_type, aggregate_id, event_type, event_data, timestamp, version, metadata, position) VALUES (?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS",
+			entry.aggregateType,
+			entry.aggregateID,
+			entry.eventType,
+			entry.eventData,
+			entry.timestamp,
+			entry.version,
+			entry.metadata,
+			int64(entry.position),
 		)
 	}
 
-	return ces.session.ExecuteBatch(batch)
+	// Should the outcome be unknown, the log entries stay pending and readers resolve them from event_store
+	applied, iter, err := ces.session.MapExecuteBatchCAS(batch, map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("failed to store events: %w", err)
+	}
+	iter.Close()
+
+	if !applied {
+		ces.skipLogEntries(ctx, entries)
+		return fmt.Errorf("failed to store events of %s %s: %w", events[0].AggregateType, events[0].AggregateID, es.ErrVersionConflict)
+	}
+
+	for i, entry := range entries {
+		position, err := ces.commitLogEntry(ctx, entry)
+		if err != nil {
+			return err
+		}
+
+		events[i].Position = position
+	}
+
+	return nil
 }
 
-func (ces CassandraEventStore) GetEvents(aggregateType es.AggregateType, aggregateID es.AggregateID) ([]es.Event, error) {
+// reserveLogEntries writes the entries to the log as pending. If a reader already gave up one of the positions,
+// the entries written so far are given up too and an error is returned.
+func (ces CassandraEventStore) reserveLogEntries(ctx context.Context, entries []eventLogEntry) error {
+	for i, entry := range entries {
+		applied, err := ces.insertLogEntry(ctx, entry, eventLogPending)
+		if err == nil && !applied {
+			err = fmt.Errorf("event log position %d was given up before it was written", entry.position)
+		}
+
+		if err != nil {
+			ces.skipLogEntries(ctx, entries[:i])
+			return fmt.Errorf("failed to reserve event log positions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// insertLogEntry writes the entry at its position unless the position is taken.
+func (ces CassandraEventStore) insertLogEntry(ctx context.Context, entry eventLogEntry, status string) (bool, error) {
+	return ces.session.Query(
+		"INSERT INTO event_log (bucket, position, status, reserved_at, aggregate_type, aggregate_id, event_type, event_data, timestamp, version, metadata) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS",
+		int64(entry.position/eventLogBucketSize),
+		int64(entry.position),
+		status,
+		entry.reservedAt,
+		entry.aggregateType,
+		entry.aggregateID,
+		entry.eventType,
+		entry.eventData,
+		entry.timestamp,
+		entry.version,
+		entry.metadata,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+}
+
+// skipLogEntries gives up the positions of events that were not stored. A failure is only logged, since readers
+// give up pending positions on their own.
+func (ces CassandraEventStore) skipLogEntries(ctx context.Context, entries []eventLogEntry) {
+	for _, entry := range entries {
+		if _, err := ces.setLogStatus(ctx, entry, eventLogSkipped); err != nil {
+			logging.FromContext(ctx).Warn("failed to give up event log position", slog.Uint64("position", entry.position), slog.Any("error", err))
+		}
+	}
+}
+
+// setLogStatus resolves a pending entry and returns the status it ends up with, which is the one another writer
+// or reader set if they resolved it first.
+func (ces CassandraEventStore) setLogStatus(ctx context.Context, entry eventLogEntry, status string) (string, error) {
+	previous := map[string]interface{}{}
+	applied, err := ces.session.Query(
+		"UPDATE event_log SET status = ? WHERE bucket = ? AND position = ? IF status = ?",
+		status,
+		int64(entry.position/eventLogBucketSize),
+		int64(entry.position),
+		eventLogPending,
+	).WithContext(ctx).MapScanCAS(previous)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve event log position %d: %w", entry.position, err)
+	}
+
+	if applied {
+		return status, nil
+	}
+
+	previousStatus, _ := previous["status"].(string)
+
+	return previousStatus, nil
+}
+
+// commitLogEntry marks the entry of a stored event committed and returns the position the event is logged at.
+func (ces CassandraEventStore) commitLogEntry(ctx context.Context, entry eventLogEntry) (uint64, error) {
+	status, err := ces.setLogStatus(ctx, entry, eventLogCommitted)
+	if err != nil {
+		return 0, err
+	}
+
+	if status == eventLogSkipped {
+		return ces.relog(ctx, entry)
+	}
+
+	return entry.position, nil
+}
+
+// relog logs a stored event whose position was given up at a new position. Only one of the writers and readers
+// relogging the same event moves its position in event_store, the others give their new positions up.
+func (ces CassandraEventStore) relog(ctx context.Context, entry eventLogEntry) (uint64, error) {
+	for {
+		position, err := ces.allocatePositions(ctx, 1)
+		if err != nil {
+			return 0, err
+		}
+
+		moved := entry
+		moved.position = position
+		moved.reservedAt = time.Now()
+
+		applied, err := ces.insertLogEntry(ctx, moved, eventLogPending)
+		if err != nil {
+			return 0, fmt.Errorf("failed to relog event: %w", err)
+		}
+
+		if !applied {
+			continue
+		}
+
+		current := map[string]interface{}{}
+		applied, err = ces.session.Query(
+			"UPDATE event_store SET position = ? WHERE aggregate_type = ? AND aggregate_id = ? AND version = ? IF position = ?",
+			int64(moved.position),
+			entry.aggregateType,
+			entry.aggregateID,
+			entry.version,
+			int64(entry.position),
+		).WithContext(ctx).MapScanCAS(current)
+		if err != nil {
+			return 0, fmt.Errorf("failed to relog event: %w", err)
+		}
+
+		if !applied {
+			ces.skipLogEntries(ctx, []eventLogEntry{moved})
+			currentPosition, _ := current["position"].(int64)
+
+			return uint64(currentPosition), nil
+		}
+
+		return ces.commitLogEntry(ctx, moved)
+	}
+}
+
+// allocatePositions reserves count consecutive global log positions and returns the first one.
+func (ces CassandraEventStore) allocatePositions(ctx context.Context, count int) (uint64, error) {
+	for {
+		current, err := ces.lastPosition(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		var applied bool
+		if current == 0 {
+			applied, err = ces.session.Query(
+				"INSERT INTO event_log_sequence (id, position) VALUES (?, ?) IF NOT EXISTS",
+				eventLogSequence,
+				int64(count),
+			).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+		} else {
+			applied, err = ces.session.Query(
+				"UPDATE event_log_sequence SET position = ? WHERE id = ? IF position = ?",
+				int64(current)+int64(count),
+				eventLogSequence,
+				int64(current),
+			).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to allocate event log positions: %w", err)
+		}
+
+		if applied {
+			return current + 1, nil
+		}
+	}
+}
+
+// lastPosition returns the last allocated global log position, or 0 if nothing has been logged yet.
+func (ces CassandraEventStore) lastPosition(ctx context.Context) (uint64, error) {
+	var position int64
+	if err := ces.session.Query(
+		"SELECT position FROM event_log_sequence WHERE id = ?",
+		eventLogSequence,
+	).WithContext(ctx).Scan(&position); err != nil {
+		if err == gocql.ErrNotFound {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("failed to read event log sequence: %w", err)
+	}
+
+	return uint64(position), nil
+}
+
+// lastReservedAt returns when the last global log position was allocated.
+func (ces CassandraEventStore) lastReservedAt(ctx context.Context) (time.Time, error) {
+	var writeTime int64
+	if err := ces.session.Query(
+		"SELECT WRITETIME(position) FROM event_log_sequence WHERE id = ?",
+		eventLogSequence,
+	).WithContext(ctx).Scan(&writeTime); err != nil {
+		return time.Time{}, fmt.Errorf("failed to read event log sequence: %w", err)
+	}
+
+	return time.UnixMicro(writeTime), nil
+}
+
+func (ces CassandraEventStore) GetEvents(ctx context.Context, aggregateType es.AggregateType, aggregateID es.AggregateID) ([]es.Event, error) {
 	aggregateUUID, err := gocql.ParseUUID(string(aggregateID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse aggregate id: %w", err)
 	}
 
 	scanner := ces.session.Query(
-		"SELECT event_type, event_data, timestamp, version FROM event_store WHERE aggregate_type = ? AND aggregate_id = ?",
+		"SELECT event_type, event_data, timestamp, version, metadata, position FROM event_store WHERE aggregate_type = ? AND aggregate_id = ?",
 		aggregateType,
 		aggregateUUID,
-	).Iter().Scanner()
+	).WithContext(ctx).Iter().Scanner()
 
 	events := []es.Event{}
 	for scanner.Next() {
@@ -70,36 +383,321 @@ func (ces CassandraEventStore) GetEvents(aggregateType es.AggregateType, aggrega
 			eventData []byte
 			timestamp int64
 			version   uint
+			metadata  string
+			position  int64
 		)
 
-		if err := scanner.Scan(&eventType, &eventData, &timestamp, &version); err != nil {
+		if err := scanner.Scan(&eventType, &eventData, &timestamp, &version, &metadata, &position); err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
 
-		eventDataInstance, ok := es.GetEvent(es.EventType(eventType))
-		if !ok {
-			return nil, es.ErrEventTypeNotFound
+		eventDataInstance, err := decodeEventData(ctx, ces.personalDataProtector, es.EventType(eventType), eventData)
+		if err != nil {
+			return nil, err
 		}
 
-		if err := json.Unmarshal(eventData, eventDataInstance); err != nil {
-			return nil, fmt.Errorf("failed to decode event data: %w", err)
+		eventMetadata, err := decodeEventMetadata(ctx, ces.personalDataProtector, metadata)
+		if err != nil {
+			return nil, err
 		}
 
-		eventDataInstanceValue := reflect.ValueOf(eventDataInstance).Elem().Interface().(es.EventData)
-
 		events = append(events, es.Event{
 			AggregateType: aggregateType,
 			AggregateID:   aggregateID,
 			EventType:     es.EventType(eventType),
-			Data:          eventDataInstanceValue,
+			Data:          eventDataInstance,
 			Timestamp:     timestamp,
 			Version:       version,
+			Position:      uint64(position),
+			Metadata:      eventMetadata,
 		})
 	}
 
 	return events, nil
 }
 
+func (ces CassandraEventStore) ReadAll(ctx context.Context, fromPosition uint64, batchSize int) ([]es.Event, error) {
+	lastPosition, err := ces.lastPosition(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []es.Event{}
+	next := max(fromPosition, 1)
+	for len(events) < batchSize && next <= lastPosition {
+		entries, err := ces.readLogEntries(ctx, next, lastPosition, batchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(entries) == 0 {
+			// Nothing is logged after next yet, so the positions up to the last allocated one are still being written
+			reservedAt, err := ces.lastReservedAt(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := ces.giveUpHole(ctx, next, lastPosition, reservedAt); err != nil {
+				return nil, err
+			}
+
+			return events, nil
+		}
+
+		for _, entry := range entries {
+			if entry.position > next {
+				// Positions reserved before this entry are at least as old as it is
+				resolved, err := ces.giveUpHole(ctx, next, entry.position-1, entry.reservedAt)
+				if err != nil || !resolved {
+					return events, err
+				}
+			}
+
+			if entry.status == eventLogPending {
+				entry.status, err = ces.resolveLogEntry(ctx, entry)
+				if err != nil {
+					return nil, err
+				}
+
+				if entry.status == eventLogPending {
+					return events, nil
+				}
+			}
+
+			next = entry.position + 1
+
+			// Entries logged before there were statuses are committed
+			if entry.status == eventLogSkipped {
+				continue
+			}
+
+			event, err := ces.decodeLogEntry(ctx, entry)
+			if err != nil {
+				return nil, err
+			}
+
+			events = append(events, event)
+			if len(events) == batchSize {
+				break
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// readLogEntries returns up to limit log entries from position on, moving on to later buckets while the current
+// one has none.
+func (ces CassandraEventStore) readLogEntries(ctx context.Context, position, lastPosition uint64, limit int) ([]eventLogEntry, error) {
+	for bucket := position / eventLogBucketSize; bucket <= lastPosition/eventLogBucketSize; bucket++ {
+		scanner := ces.session.Query(
+			"SELECT position, status, reserved_at, aggregate_type, aggregate_id, event_type, event_data, timestamp, version, metadata FROM event_log WHERE bucket = ? AND position >= ? LIMIT ?",
+			int64(bucket),
+			int64(position),
+			limit,
+		).WithContext(ctx).Iter().Scanner()
+
+		entries := []eventLogEntry{}
+		for scanner.Next() {
+			var (
+				entry         eventLogEntry
+				entryPosition int64
+			)
+
+			if err := scanner.Scan(&entryPosition, &entry.status, &entry.reservedAt, &entry.aggregateType, &entry.aggregateID, &entry.eventType, &entry.eventData, &entry.timestamp, &entry.version, &entry.metadata); err != nil {
+				return nil, fmt.Errorf("failed to scan event: %w", err)
+			}
+
+			entry.position = uint64(entryPosition)
+			entries = append(entries, entry)
+		}
+
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read event log: %w", err)
+		}
+
+		if len(entries) > 0 {
+			return entries, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// giveUpHole marks the unwritten positions from first to last skipped once they were reserved before
+// eventLogResolveAfter, so their writers fail instead of logging behind readers. It reports whether all of them
+// are resolved now.
+func (ces CassandraEventStore) giveUpHole(ctx context.Context, first, last uint64, reservedBefore time.Time) (bool, error) {
+	if time.Since(reservedBefore) < eventLogResolveAfter {
+		return false, nil
+	}
+
+	for position := first; position <= last; position++ {
+		applied, err := ces.session.Query(
+			"INSERT INTO event_log (bucket, position, status, reserved_at) VALUES (?, ?, ?, ?) IF NOT EXISTS",
+			int64(position/eventLogBucketSize),
+			int64(position),
+			eventLogSkipped,
+			reservedBefore,
+		).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+		if err != nil {
+			return false, fmt.Errorf("failed to give up event log position %d: %w", position, err)
+		}
+
+		// The writer got there first, so the entry is read again
+		if !applied {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// resolveLogEntry decides a pending entry from event_store: it is committed once its event is stored at its
+// position, and skipped when a different event took its version or it is older than eventLogResolveAfter. It
+// returns the new status, which stays pending while the writer may still store the event.
+func (ces CassandraEventStore) resolveLogEntry(ctx context.Context, entry eventLogEntry) (string, error) {
+	storedPosition, stored, err := ces.storedPosition(ctx, entry)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case stored && storedPosition == entry.position:
+		return ces.setLogStatus(ctx, entry, eventLogCommitted)
+	case stored:
+		return ces.setLogStatus(ctx, entry, eventLogSkipped)
+	case time.Since(entry.reservedAt) < eventLogResolveAfter:
+		return eventLogPending, nil
+	}
+
+	status, err := ces.setLogStatus(ctx, entry, eventLogSkipped)
+	if err != nil || status != eventLogSkipped {
+		return status, err
+	}
+
+	// A writer storing the event right before it was given up relogs it, but it may not live to do so
+	storedPosition, stored, err = ces.storedPosition(ctx, entry)
+	if err != nil {
+		return "", err
+	}
+
+	if stored && storedPosition == entry.position {
+		if _, err := ces.relog(ctx, entry); err != nil {
+			return "", err
+		}
+	}
+
+	return eventLogSkipped, nil
+}
+
+// storedPosition returns the log position event_store holds for the version of the entry's aggregate, if that
+// version is stored.
+func (ces CassandraEventStore) storedPosition(ctx context.Context, entry eventLogEntry) (uint64, bool, error) {
+	var position int64
+	if err := ces.session.Query(
+		"SELECT position FROM event_store WHERE aggregate_type = ? AND aggregate_id = ? AND version = ?",
+		entry.aggregateType,
+		entry.aggregateID,
+		entry.version,
+	).WithContext(ctx).Scan(&position); err != nil {
+		if err == gocql.ErrNotFound {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("failed to read stored event: %w", err)
+	}
+
+	return uint64(position), true, nil
+}
+
+func (ces CassandraEventStore) decodeLogEntry(ctx context.Context, entry eventLogEntry) (es.Event, error) {
+	eventDataInstance, err := decodeEventData(ctx, ces.personalDataProtector, es.EventType(entry.eventType), []byte(entry.eventData))
+	if err != nil {
+		return es.Event{}, err
+	}
+
+	eventMetadata, err := decodeEventMetadata(ctx, ces.personalDataProtector, entry.metadata)
+	if err != nil {
+		return es.Event{}, err
+	}
+
+	return es.Event{
+		AggregateType: es.AggregateType(entry.aggregateType),
+		AggregateID:   es.AggregateID(entry.aggregateID.String()),
+		EventType:     es.EventType(entry.eventType),
+		Data:          eventDataInstance,
+		Timestamp:     entry.timestamp,
+		Version:       entry.version,
+		Position:      entry.position,
+		Metadata:      eventMetadata,
+	}, nil
+}
+
+// encodeEventData encrypts the personal fields of event data and serializes it for storage.
+func encodeEventData(ctx context.Context, personalDataProtector *PersonalDataProtector, data es.EventData) ([]byte, error) {
+	protectedData, err := personalDataProtector.Protect(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to protect personal data: %w", err)
+	}
+
+	return json.Marshal(protectedData)
+}
+
+// decodeEventData deserializes stored event data and decrypts its personal fields.
+func decodeEventData(ctx context.Context, personalDataProtector *PersonalDataProtector, eventType es.EventType, eventData []byte) (es.EventData, error) {
+	eventDataInstance, ok := es.GetEvent(eventType)
+	if !ok {
+		return nil, es.ErrEventTypeNotFound
+	}
+
+	if err := json.Unmarshal(eventData, eventDataInstance); err != nil {
+		return nil, fmt.Errorf("failed to decode event data: %w", err)
+	}
+
+	eventDataInstanceValue, err := personalDataProtector.Unprotect(ctx, reflect.ValueOf(eventDataInstance).Elem().Interface().(es.EventData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unprotect personal data: %w", err)
+	}
+
+	return eventDataInstanceValue, nil
+}
+
+// encodeEventMetadata encrypts the acting user of an event and serializes the metadata for storage.
+func encodeEventMetadata(ctx context.Context, personalDataProtector *PersonalDataProtector, metadata es.EventMetadata) (string, error) {
+	protectedMetadata, err := personalDataProtector.ProtectMetadata(ctx, metadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to protect event metadata: %w", err)
+	}
+
+	metadataBytes, err := json.Marshal(protectedMetadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode event metadata: %w", err)
+	}
+
+	return string(metadataBytes), nil
+}
+
+// decodeEventMetadata deserializes stored event metadata and decrypts the acting user.
+// Events stored before they carried metadata have none.
+func decodeEventMetadata(ctx context.Context, personalDataProtector *PersonalDataProtector, metadata string) (es.EventMetadata, error) {
+	if metadata == "" {
+		return es.EventMetadata{}, nil
+	}
+
+	var eventMetadata es.EventMetadata
+	if err := json.Unmarshal([]byte(metadata), &eventMetadata); err != nil {
+		return es.EventMetadata{}, fmt.Errorf("failed to decode event metadata: %w", err)
+	}
+
+	eventMetadata, err := personalDataProtector.UnprotectMetadata(ctx, eventMetadata)
+	if err != nil {
+		return es.EventMetadata{}, fmt.Errorf("failed to unprotect event metadata: %w", err)
+	}
+
+	return eventMetadata, nil
+}
+
 func (ces CassandraEventStore) init() error {
 	if err := ces.session.Query(
 		`CREATE TABLE IF NOT EXISTS event_store (
@@ -109,11 +707,285 @@ func (ces CassandraEventStore) init() error {
 			event_data text,
 			timestamp timestamp,
 			version int,
+			metadata text,
 			PRIMARY KEY ((aggregate_type, aggregate_id), version)
 		) WITH CLUSTERING ORDER BY (version ASC)`,
 	).Exec(); err != nil {
 		return fmt.Errorf("failed to create event_store table: %w", err)
 	}
 
+	// Tables created before events carried metadata get the column added
+	if err := ces.session.Query(`ALTER TABLE event_store ADD metadata text`).Exec(); err != nil && !strings.Contains(err.Error(), "conflicts with an existing column") {
+		return fmt.Errorf("failed to add metadata to event_store table: %w", err)
+	}
+
+	if err := ces.session.Query(`ALTER TABLE event_store ADD position bigint`).Exec(); err != nil && !strings.Contains(err.Error(), "conflicts with an existing column") {
+		return fmt.Errorf("failed to add position to event_store table: %w", err)
+	}
+
+	if err := ces.session.Query(
+		`CREATE TABLE IF NOT EXISTS event_log (
+			bucket bigint,
+			position bigint,
+			status text,
+			reserved_at timestamp,
+			aggregate_type text,
+			aggregate_id uuid,
+			event_type text,
+			event_data text,
+			timestamp timestamp,
+			version int,
+			metadata text,
+			PRIMARY KEY (bucket, position)
+		) WITH CLUSTERING ORDER BY (position ASC)`,
+	).Exec(); err != nil {
+		return fmt.Errorf("failed to create event_log table: %w", err)
+	}
+
+	// Logs created before positions were reserved get the columns added, and their entries count as committed
+	for _, column := range []string{"status text", "reserved_at timestamp"} {
+		if err := ces.session.Query("ALTER TABLE event_log ADD " + column).Exec(); err != nil && !strings.Contains(err.Error(), "conflicts with an existing column") {
+			return fmt.Errorf("failed to add %s to event_log table: %w", column, err)
+		}
+	}
+
+	if err := ces.session.Query(
+		`CREATE TABLE IF NOT EXISTS event_log_sequence (
+			id text PRIMARY KEY,
+			position bigint
+		)`,
+	).Exec(); err != nil {
+		return fmt.Errorf("failed to create event_log_sequence table: %w", err)
+	}
+
+	return ces.backfill(context.Background())
+}
+
+// backfill logs the events stored before the log existed, or whose log entry was lost between the two writes,
+// in the order they were written, and records the log position of every stored event in event_store. It runs
+// once, on the instance holding the backfill lock, and is safe to run again if that instance stops halfway. The
+// other instances wait until it is done, since events they stored or read in the meantime would be logged ahead
+// of older ones.
+func (ces CassandraEventStore) backfill(ctx context.Context) error {
+	for {
+		var done int64
+		if err := ces.session.Query("SELECT position FROM event_log_sequence WHERE id = ?", eventLogBackfilled).WithContext(ctx).Scan(&done); err == nil {
+			return nil
+		} else if err != gocql.ErrNotFound {
+			return fmt.Errorf("failed to read event log backfill marker: %w", err)
+		}
+
+		// The lock expires on its own should its holder stop renewing it
+		owner := rand.Int63()
+		locked, err := ces.session.Query(
+			"INSERT INTO event_log_sequence (id, position) VALUES (?, ?) IF NOT EXISTS USING TTL ?",
+			eventLogBackfillLock,
+			owner,
+			int(eventLogBackfillLockTTL.Seconds()),
+		).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+		if err != nil {
+			return fmt.Errorf("failed to lock event log backfill: %w", err)
+		}
+
+		if locked {
+			return ces.runBackfill(ctx, owner)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(eventLogBackfillPollInterval):
+		}
+	}
+}
+
+// runBackfill backfills the log while holding the backfill lock, and stops once the lock is lost.
+func (ces CassandraEventStore) runBackfill(ctx context.Context, owner int64) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	go ces.renewBackfillLock(ctx, cancel, owner)
+
+	err := ces.backfillStoredPositions(ctx)
+	if err == nil {
+		err = ces.backfillLog(ctx)
+	}
+	if err == nil {
+		err = ces.session.Query("INSERT INTO event_log_sequence (id, position) VALUES (?, ?)", eventLogBackfilled, int64(0)).WithContext(ctx).Exec()
+		if err != nil {
+			err = fmt.Errorf("failed to mark event log backfilled: %w", err)
+		}
+	}
+	if cause := context.Cause(ctx); cause != nil {
+		return cause
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := ces.session.Query(
+		"DELETE FROM event_log_sequence WHERE id = ? IF position = ?",
+		eventLogBackfillLock,
+		owner,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{}); err != nil {
+		logging.FromContext(ctx).Warn("failed to release event log backfill lock", slog.Any("error", err))
+	}
+
+	return nil
+}
+
+// renewBackfillLock keeps the backfill lock until ctx is done, and cancels it once the lock is lost. A failed
+// renewal is retried at the next one, since the lock outlives a few of them.
+func (ces CassandraEventStore) renewBackfillLock(ctx context.Context, cancel context.CancelCauseFunc, owner int64) {
+	ticker := time.NewTicker(eventLogBackfillLockTTL / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := ces.session.Query(
+			"UPDATE event_log_sequence USING TTL ? SET position = ? WHERE id = ? IF position = ?",
+			int(eventLogBackfillLockTTL.Seconds()),
+			owner,
+			eventLogBackfillLock,
+			owner,
+		).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+		if err != nil {
+			logging.FromContext(ctx).Warn("failed to renew event log backfill lock", slog.Any("error", err))
+			continue
+		}
+
+		if !renewed {
+			cancel(errors.New("event log backfill lock was lost"))
+			return
+		}
+	}
+}
+
+// backfillStoredPositions records the positions of events logged before there were statuses in event_store.
+// Entries with a status were written together with the position of their event.
+func (ces CassandraEventStore) backfillStoredPositions(ctx context.Context) error {
+	scanner := ces.session.Query("SELECT position, status, aggregate_type, aggregate_id, version FROM event_log").WithContext(ctx).Iter().Scanner()
+	for scanner.Next() {
+		var (
+			entry    eventLogEntry
+			position int64
+		)
+
+		if err := scanner.Scan(&position, &entry.status, &entry.aggregateType, &entry.aggregateID, &entry.version); err != nil {
+			return fmt.Errorf("failed to scan event log: %w", err)
+		}
+
+		if entry.status != "" {
+			continue
+		}
+
+		if err := ces.setStoredPosition(ctx, entry, uint64(position)); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event log: %w", err)
+	}
+
+	return nil
+}
+
+// backfillLog logs the events event_store holds no position for. Only their keys are kept while event_store is
+// scanned, and each event is read again when it is logged.
+func (ces CassandraEventStore) backfillLog(ctx context.Context) error {
+	unlogged := []eventLogEntry{}
+	scanner := ces.session.Query("SELECT aggregate_type, aggregate_id, version, timestamp, position FROM event_store").WithContext(ctx).Iter().Scanner()
+	for scanner.Next() {
+		var (
+			entry    eventLogEntry
+			position *int64
+		)
+
+		if err := scanner.Scan(&entry.aggregateType, &entry.aggregateID, &entry.version, &entry.timestamp, &position); err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		if position == nil {
+			unlogged = append(unlogged, entry)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event store: %w", err)
+	}
+
+	sort.SliceStable(unlogged, func(i, j int) bool {
+		return unlogged[i].timestamp < unlogged[j].timestamp
+	})
+
+	for len(unlogged) > 0 {
+		count := min(len(unlogged), 1000)
+		firstPosition, err := ces.allocatePositions(ctx, count)
+		if err != nil {
+			return err
+		}
+
+		for i, key := range unlogged[:count] {
+			entry, err := ces.storedEntry(ctx, key)
+			if err != nil {
+				return err
+			}
+
+			entry.position = firstPosition + uint64(i)
+			entry.reservedAt = time.Now()
+
+			// The entry stays pending until event_store holds its position, so a backfill stopping in between
+			// leaves an entry readers skip and an event the next backfill logs again
+			if _, err := ces.insertLogEntry(ctx, entry, eventLogPending); err != nil {
+				return fmt.Errorf("failed to backfill event log: %w", err)
+			}
+
+			if err := ces.setStoredPosition(ctx, entry, entry.position); err != nil {
+				return err
+			}
+
+			if _, err := ces.setLogStatus(ctx, entry, eventLogCommitted); err != nil {
+				return err
+			}
+		}
+
+		unlogged = unlogged[count:]
+	}
+
+	return nil
+}
+
+// storedEntry reads the stored event with the key of the entry.
+func (ces CassandraEventStore) storedEntry(ctx context.Context, key eventLogEntry) (eventLogEntry, error) {
+	entry := key
+	if err := ces.session.Query(
+		"SELECT event_type, event_data, timestamp, metadata FROM event_store WHERE aggregate_type = ? AND aggregate_id = ? AND version = ?",
+		key.aggregateType,
+		key.aggregateID,
+		key.version,
+	).WithContext(ctx).Scan(&entry.eventType, &entry.eventData, &entry.timestamp, &entry.metadata); err != nil {
+		return eventLogEntry{}, fmt.Errorf("failed to read stored event: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (ces CassandraEventStore) setStoredPosition(ctx context.Context, entry eventLogEntry, position uint64) error {
+	if err := ces.session.Query(
+		"UPDATE event_store SET position = ? WHERE aggregate_type = ? AND aggregate_id = ? AND version = ?",
+		int64(position),
+		entry.aggregateType,
+		entry.aggregateID,
+		entry.version,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to backfill event position: %w", err)
+	}
+
 	return nil
 }
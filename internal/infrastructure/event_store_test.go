@@ -0,0 +1,284 @@
+package infrastructure_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cybre/home-inventory/internal/cassandra"
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/infrastructure"
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cassandraSession connects to the cluster in CASSANDRA_HOSTS, in a keyspace of its own that is dropped after the
+// test. Tests needing Cassandra are skipped without one.
+func cassandraSession(t *testing.T) *gocql.Session {
+	t.Helper()
+
+	hosts := os.Getenv("CASSANDRA_HOSTS")
+	if hosts == "" {
+		t.Skip("CASSANDRA_HOSTS is not set")
+	}
+
+	keyspace := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	session, err := cassandra.NewSession(strings.Split(hosts, ","), keyspace)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, session.Query("DROP KEYSPACE "+keyspace).Exec())
+		session.Close()
+	})
+
+	return session
+}
+
+func Test_CassandraEventStore(t *testing.T) {
+	ctx := context.Background()
+	es.RegisterEvent(testEvent{})
+
+	session := cassandraSession(t)
+
+	keyStore, err := infrastructure.NewCassandraPersonalDataKeyStore(session)
+	require.NoError(t, err)
+
+	protector := infrastructure.NewPersonalDataProtector(keyStore)
+	eventStore, err := infrastructure.NewCassandraEventStore(session, protector)
+	require.NoError(t, err)
+
+	event := func(aggregateID gocql.UUID, version uint, name string) es.Event {
+		data := testEvent{ID: "1", UserID: "user-1", Name: name}
+		return es.Event{AggregateType: "Test", AggregateID: es.AggregateID(aggregateID.String()), EventType: data.EventType(), Data: data, Timestamp: time.Now().UnixMilli(), Version: version}
+	}
+
+	store := func(aggregateID gocql.UUID, version uint, name string) es.Event {
+		events := []es.Event{event(aggregateID, version, name)}
+		require.NoError(t, eventStore.StoreEvents(ctx, events))
+
+		return events[0]
+	}
+
+	readNames := func(from uint64) []string {
+		events, err := eventStore.ReadAll(ctx, from, 100)
+		require.NoError(t, err)
+
+		names := []string{}
+		for _, event := range events {
+			names = append(names, event.Data.(testEvent).Name)
+		}
+
+		return names
+	}
+
+	logStatus := func(position uint64) string {
+		var status string
+		require.NoError(t, session.Query("SELECT status FROM event_log WHERE bucket = 0 AND position = ?", int64(position)).Scan(&status))
+
+		return status
+	}
+
+	// unstore removes an event from event_store as if its writer had not stored it yet, and returns a function
+	// storing it again
+	unstore := func(event es.Event) func() {
+		var (
+			eventData string
+			timestamp int64
+			metadata  string
+		)
+		require.NoError(t, session.Query(
+			"SELECT event_data, timestamp, metadata FROM event_store WHERE aggregate_type = ? AND aggregate_id = ? AND version = ?",
+			string(event.AggregateType), string(event.AggregateID), event.Version,
+		).Scan(&eventData, &timestamp, &metadata))
+		require.NoError(t, session.Query(
+			"DELETE FROM event_store WHERE aggregate_type = ? AND aggregate_id = ? AND version = ?",
+			string(event.AggregateType), string(event.AggregateID), event.Version,
+		).Exec())
+
+		return func() {
+			require.NoError(t, session.Query(
+				"INSERT INTO event_store (aggregate_type, aggregate_id, event_type, event_data, timestamp, version, metadata, position) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+				string(event.AggregateType), string(event.AggregateID), string(event.EventType), eventData, timestamp, event.Version, metadata, int64(event.Position),
+			).Exec())
+		}
+	}
+
+	// reopen sets the log entry of an event back to pending, reserved at the given time
+	reopen := func(event es.Event, reservedAt time.Time) {
+		require.NoError(t, session.Query(
+			"UPDATE event_log SET status = ?, reserved_at = ? WHERE bucket = 0 AND position = ?",
+			"pending", reservedAt, int64(event.Position),
+		).Exec())
+	}
+
+	first := gocql.TimeUUID()
+	second := gocql.TimeUUID()
+
+	t.Run("logs events in the order they are stored", func(t *testing.T) {
+		a1, a2, b1 := store(first, 1, "Summer House"), store(first, 2, "Winter House"), store(second, 1, "Beach House")
+		assert.Equal(t, []uint64{1, 2, 3}, []uint64{a1.Position, a2.Position, b1.Position})
+		assert.Equal(t, []string{"Summer House", "Winter House", "Beach House"}, readNames(1))
+
+		events, err := eventStore.ReadAll(ctx, 2, 1)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, uint64(2), events[0].Position)
+
+		stored, err := eventStore.GetEvents(ctx, "Test", es.AggregateID(first.String()))
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{1, 2}, []uint64{stored[0].Position, stored[1].Position})
+	})
+
+	t.Run("gives up the positions of a rejected writer", func(t *testing.T) {
+		err := eventStore.StoreEvents(ctx, []es.Event{event(first, 3, "Lake House"), event(first, 2, "Mountain House")})
+		assert.ErrorIs(t, err, es.ErrVersionConflict)
+		assert.Equal(t, "skipped", logStatus(4))
+		assert.Equal(t, "skipped", logStatus(5))
+
+		lake := store(first, 3, "Lake House")
+		assert.Equal(t, uint64(6), lake.Position)
+		assert.Equal(t, []string{"Lake House"}, readNames(4))
+	})
+
+	t.Run("waits for a writer between reserving a position and storing its event", func(t *testing.T) {
+		cottage := store(second, 2, "Cottage")
+		restore := unstore(cottage)
+		reopen(cottage, time.Now())
+		store(second, 3, "Barn")
+
+		assert.Empty(t, readNames(cottage.Position), "events after a position being written are held back")
+
+		restore()
+		assert.Equal(t, []string{"Cottage", "Barn"}, readNames(cottage.Position), "the position is resolved once its event is stored")
+		assert.Equal(t, "committed", logStatus(cottage.Position))
+	})
+
+	t.Run("commits the position of a writer that stopped after storing its event", func(t *testing.T) {
+		villa := store(second, 4, "Villa")
+		reopen(villa, time.Now().Add(-2*time.Minute))
+
+		assert.Equal(t, []string{"Villa"}, readNames(villa.Position))
+		assert.Equal(t, "committed", logStatus(villa.Position))
+	})
+
+	t.Run("gives up the position of a writer that stopped before storing its event", func(t *testing.T) {
+		shed := store(second, 5, "Shed")
+		unstore(shed)
+		reopen(shed, time.Now().Add(-2*time.Minute))
+		store(second, 6, "Garage")
+
+		assert.Equal(t, []string{"Garage"}, readNames(shed.Position))
+		assert.Equal(t, "skipped", logStatus(shed.Position))
+	})
+
+	t.Run("logs every event of concurrent writers once", func(t *testing.T) {
+		last, err := eventStore.ReadAll(ctx, 1, 1000)
+		require.NoError(t, err)
+		from := last[len(last)-1].Position + 1
+
+		var wg sync.WaitGroup
+		for writer := 0; writer < 10; writer++ {
+			wg.Add(1)
+			go func(writer int) {
+				defer wg.Done()
+
+				aggregateID := gocql.TimeUUID()
+				for version := uint(1); version <= 5; version++ {
+					assert.NoError(t, eventStore.StoreEvents(ctx, []es.Event{event(aggregateID, version, fmt.Sprintf("House %d-%d", writer, version))}))
+				}
+			}(writer)
+		}
+		wg.Wait()
+
+		events, err := eventStore.ReadAll(ctx, from, 1000)
+		require.NoError(t, err)
+		require.Len(t, events, 50)
+
+		seen := map[string]bool{}
+		for i, event := range events {
+			if i > 0 {
+				assert.Greater(t, event.Position, events[i-1].Position)
+			}
+
+			name := event.Data.(testEvent).Name
+			assert.False(t, seen[name], "%s was logged more than once", name)
+			seen[name] = true
+		}
+	})
+
+	t.Run("backfills events stored before the log existed", func(t *testing.T) {
+		legacy := store(gocql.TimeUUID(), 1, "Old House")
+		require.NoError(t, session.Query("DELETE FROM event_log WHERE bucket = 0 AND position = ?", int64(legacy.Position)).Exec())
+		require.NoError(t, session.Query(
+			"DELETE position FROM event_store WHERE aggregate_type = ? AND aggregate_id = ? AND version = ?",
+			string(legacy.AggregateType), string(legacy.AggregateID), legacy.Version,
+		).Exec())
+		require.NoError(t, session.Query("DELETE FROM event_log_sequence WHERE id = 'backfilled'").Exec())
+
+		// Another instance is running the backfill, so the store waits until it is done
+		require.NoError(t, session.Query("INSERT INTO event_log_sequence (id, position) VALUES ('backfill', 1) USING TTL 60").Exec())
+		opened := make(chan error, 1)
+		go func() {
+			_, err := infrastructure.NewCassandraEventStore(session, protector)
+			opened <- err
+		}()
+
+		select {
+		case <-opened:
+			t.Fatal("the store should wait for the backfill of another instance")
+		case <-time.After(time.Second):
+		}
+
+		require.NoError(t, session.Query("DELETE FROM event_log_sequence WHERE id = 'backfill'").Exec())
+		select {
+		case err := <-opened:
+			require.NoError(t, err)
+		case <-time.After(30 * time.Second):
+			t.Fatal("the store should take the backfill over once the lock is released")
+		}
+
+		stored, err := eventStore.GetEvents(ctx, legacy.AggregateType, legacy.AggregateID)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Greater(t, stored[0].Position, legacy.Position)
+		assert.Equal(t, []string{"Old House"}, readNames(stored[0].Position))
+	})
+}
+
+func Test_CassandraCheckpointStore_ClaimCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	session := cassandraSession(t)
+
+	checkpoints, err := infrastructure.NewCassandraCheckpointStore(session)
+	require.NoError(t, err)
+
+	claim := func(owner string, lease time.Duration) bool {
+		claimed, err := checkpoints.ClaimCheckpoint(ctx, "Projector", owner, lease)
+		require.NoError(t, err)
+
+		return claimed
+	}
+
+	assert.True(t, claim("a", time.Second))
+	assert.False(t, claim("b", time.Minute), "a lease held by another owner cannot be taken")
+	assert.True(t, claim("a", time.Second), "the owner can renew its lease")
+
+	time.Sleep(2 * time.Second)
+	assert.True(t, claim("b", time.Minute), "an expired lease can be taken over")
+
+	assert.NoError(t, checkpoints.ReleaseCheckpoint(ctx, "Projector", "a"))
+	assert.False(t, claim("a", time.Minute), "only the owner can release its lease")
+
+	assert.NoError(t, checkpoints.ReleaseCheckpoint(ctx, "Projector", "b"))
+	assert.True(t, claim("a", time.Minute), "a released lease can be taken right away")
+
+	require.NoError(t, checkpoints.SaveCheckpoint(ctx, "Projector", 42))
+	position, err := checkpoints.GetCheckpoint(ctx, "Projector")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), position)
+}
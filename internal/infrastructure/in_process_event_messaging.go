@@ -0,0 +1,85 @@
+package infrastructure
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/logging"
+)
+
+// InProcessEventMessaging hands published events straight to the registered handlers, for single-node
+// deployments without Kafka. Handlers run synchronously and in publishing order, so read models are
+// up to date once a command returns. A failing handler is logged and does not fail the command. There is nothing
+// to redeliver a failed event from, so handlers that must not miss events, like projectors, follow the event log
+// through a CatchUpEventConsumer instead of consuming events here directly.
+//
+// Events published by a handler, for instance by a process manager dispatching a command, are queued behind
+// the events being handled rather than handled right away, so every handler still sees events in publishing order.
+type InProcessEventMessaging struct {
+	mu          sync.Mutex
+	subscribers []inProcessSubscriber
+}
+
+type inProcessSubscriber struct {
+	name     string
+	callback func(ctx context.Context, event eventsourcing.Event) error
+}
+
+func NewInProcessEventMessaging() *InProcessEventMessaging {
+	return &InProcessEventMessaging{
+		subscribers: []inProcessSubscriber{},
+	}
+}
+
+// publishQueueKey holds the queue of events still to be handled while handlers run
+const publishQueueKey = "in_process_publish_queue"
+
+func (m *InProcessEventMessaging) PublishEvents(ctx context.Context, events []eventsourcing.Event) error {
+	if queue, ok := ctx.Value(publishQueueKey).(*[]eventsourcing.Event); ok {
+		*queue = append(*queue, events...)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	queue := append([]eventsourcing.Event{}, events...)
+	// The projection has to finish even if the request that issued the command goes away
+	ctx = context.WithValue(context.WithoutCancel(ctx), publishQueueKey, &queue)
+	for len(queue) > 0 {
+		event := queue[0]
+		queue = queue[1:]
+		for _, subscriber := range m.subscribers {
+			if err := subscriber.callback(ctx, event); err != nil {
+				logging.FromContext(ctx).Error(
+					"failed to deliver event",
+					slog.String("subscriber", subscriber.name),
+					slog.Any("event_type", event.EventType),
+					slog.Uint64("position", event.Position),
+					slog.Any("error", err),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *InProcessEventMessaging) ConsumeEvents(ctx context.Context, handler EventHandler) error {
+	return m.StreamEvents(ctx, handler.Name(), func(ctx context.Context, event eventsourcing.Event) error {
+		return handleEvent(ctx, handler, event)
+	})
+}
+
+func (m *InProcessEventMessaging) StreamEvents(ctx context.Context, name string, callback func(ctx context.Context, event eventsourcing.Event) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subscribers = append(m.subscribers, inProcessSubscriber{name: name, callback: callback})
+
+	return nil
+}
+
+func (m *InProcessEventMessaging) Close() {}
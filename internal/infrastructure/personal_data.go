@@ -0,0 +1,269 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+)
+
+const (
+	// PersonalDataTag marks string fields of event data that contain personal data.
+	// Fields tagged with `personal:"subject"` additionally identify the user the data belongs to.
+	PersonalDataTag        = "personal"
+	PersonalDataSubjectTag = "subject"
+
+	// ErasedPersonalData replaces personal data whose encryption key has been destroyed.
+	ErasedPersonalData = "[erased]"
+
+	encryptedValuePrefix = "pd:"
+)
+
+type PersonalDataKey struct {
+	ID  string
+	Key []byte
+}
+
+type PersonalDataKeyStore interface {
+	GetOrCreateUserKey(ctx context.Context, userID string) (PersonalDataKey, error)
+	GetKey(ctx context.Context, keyID string) (PersonalDataKey, bool, error)
+}
+
+// PersonalDataProtector encrypts personal fields of event data with a per-user key, so that
+// destroying the key makes the data unreadable everywhere it has been stored (crypto-shredding).
+type PersonalDataProtector struct {
+	keyStore PersonalDataKeyStore
+}
+
+func NewPersonalDataProtector(keyStore PersonalDataKeyStore) *PersonalDataProtector {
+	return &PersonalDataProtector{
+		keyStore: keyStore,
+	}
+}
+
+func (p PersonalDataProtector) Protect(ctx context.Context, data es.EventData) (es.EventData, error) {
+	protected, err := p.protect(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return protected.(es.EventData), nil
+}
+
+func (p PersonalDataProtector) Unprotect(ctx context.Context, data es.EventData) (es.EventData, error) {
+	unprotected, err := p.unprotect(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return unprotected.(es.EventData), nil
+}
+
+// ProtectMetadata encrypts the acting user of an event with that user's key.
+func (p PersonalDataProtector) ProtectMetadata(ctx context.Context, metadata es.EventMetadata) (es.EventMetadata, error) {
+	protected, err := p.protect(ctx, metadata)
+	if err != nil {
+		return es.EventMetadata{}, err
+	}
+
+	return protected.(es.EventMetadata), nil
+}
+
+func (p PersonalDataProtector) UnprotectMetadata(ctx context.Context, metadata es.EventMetadata) (es.EventMetadata, error) {
+	unprotected, err := p.unprotect(ctx, metadata)
+	if err != nil {
+		return es.EventMetadata{}, err
+	}
+
+	return unprotected.(es.EventMetadata), nil
+}
+
+func (p PersonalDataProtector) protect(ctx context.Context, data any) (any, error) {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Struct {
+		return data, nil
+	}
+
+	subject, ok := personalDataSubject(value)
+	if !ok || subject == "" {
+		return data, nil
+	}
+
+	key, err := p.keyStore.GetOrCreateUserKey(ctx, subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal data key: %w", err)
+	}
+
+	protected := reflect.New(value.Type()).Elem()
+	protected.Set(value)
+
+	for _, i := range personalDataFields(value.Type()) {
+		field := protected.Field(i)
+		if field.String() == "" {
+			continue
+		}
+
+		encrypted, err := encryptPersonalData(key, field.String())
+		if err != nil {
+			return nil, err
+		}
+
+		field.SetString(encrypted)
+	}
+
+	return protected.Interface(), nil
+}
+
+func (p PersonalDataProtector) unprotect(ctx context.Context, data any) (any, error) {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Struct {
+		return data, nil
+	}
+
+	fields := personalDataFields(value.Type())
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	unprotected := reflect.New(value.Type()).Elem()
+	unprotected.Set(value)
+
+	keys := map[string]*PersonalDataKey{}
+	for _, i := range fields {
+		field := unprotected.Field(i)
+		if !strings.HasPrefix(field.String(), encryptedValuePrefix) {
+			continue
+		}
+
+		keyID, ciphertext, ok := strings.Cut(strings.TrimPrefix(field.String(), encryptedValuePrefix), ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed personal data in field %s", value.Type().Field(i).Name)
+		}
+
+		key, ok := keys[keyID]
+		if !ok {
+			found, exists, err := p.keyStore.GetKey(ctx, keyID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get personal data key: %w", err)
+			}
+
+			if exists {
+				key = &found
+			}
+
+			keys[keyID] = key
+		}
+
+		if key == nil {
+			field.SetString(ErasedPersonalData)
+			continue
+		}
+
+		decrypted, err := decryptPersonalData(*key, ciphertext)
+		if err != nil {
+			return nil, err
+		}
+
+		field.SetString(decrypted)
+	}
+
+	return unprotected.Interface(), nil
+}
+
+// HasReadablePersonalData tells whether any personal field of event data or metadata still holds a value
+// other than ErasedPersonalData, for instance because it was never encrypted or its key still exists.
+func HasReadablePersonalData(data any) bool {
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Struct {
+		return false
+	}
+
+	for _, i := range personalDataFields(value.Type()) {
+		if field := value.Field(i).String(); field != "" && field != ErasedPersonalData {
+			return true
+		}
+	}
+
+	return false
+}
+
+func personalDataSubject(value reflect.Value) (string, bool) {
+	for i := 0; i < value.NumField(); i++ {
+		if value.Type().Field(i).Tag.Get(PersonalDataTag) == PersonalDataSubjectTag && value.Field(i).Kind() == reflect.String {
+			return value.Field(i).String(), true
+		}
+	}
+
+	return "", false
+}
+
+func personalDataFields(t reflect.Type) []int {
+	fields := []int{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get(PersonalDataTag) != "" && field.Type.Kind() == reflect.String && field.IsExported() {
+			fields = append(fields, i)
+		}
+	}
+
+	return fields
+}
+
+func encryptPersonalData(key PersonalDataKey, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), []byte(key.ID))
+
+	return encryptedValuePrefix + key.ID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptPersonalData(key PersonalDataKey, ciphertext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode personal data: %w", err)
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("personal data ciphertext is too short")
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(key.ID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt personal data: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+func newGCM(key PersonalDataKey) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,170 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+const personalDataKeySize = 32
+
+// CassandraPersonalDataKeyStore keeps per-user encryption keys apart from the event store,
+// so that deleting them is enough to erase a user's personal data from the event history.
+type CassandraPersonalDataKeyStore struct {
+	session *gocql.Session
+}
+
+func NewCassandraPersonalDataKeyStore(session *gocql.Session) (*CassandraPersonalDataKeyStore, error) {
+	keyStore := &CassandraPersonalDataKeyStore{
+		session: session,
+	}
+
+	if err := keyStore.init(); err != nil {
+		return nil, err
+	}
+
+	return keyStore, nil
+}
+
+func (s CassandraPersonalDataKeyStore) GetOrCreateUserKey(ctx context.Context, userID string) (PersonalDataKey, error) {
+	var keyID gocql.UUID
+	err := s.session.Query("SELECT key_id FROM user_personal_data_keys WHERE user_id = ?", userID).WithContext(ctx).Scan(&keyID)
+	if err == nil {
+		key, ok, err := s.GetKey(ctx, keyID.String())
+		if err != nil {
+			return PersonalDataKey{}, err
+		}
+
+		if !ok {
+			return PersonalDataKey{}, fmt.Errorf("personal data key %s is missing", keyID)
+		}
+
+		return key, nil
+	}
+
+	if err != gocql.ErrNotFound {
+		return PersonalDataKey{}, fmt.Errorf("failed to get user personal data key: %w", err)
+	}
+
+	return s.createUserKey(ctx, userID)
+}
+
+func (s CassandraPersonalDataKeyStore) GetKey(ctx context.Context, keyID string) (PersonalDataKey, bool, error) {
+	keyUUID, err := gocql.ParseUUID(keyID)
+	if err != nil {
+		return PersonalDataKey{}, false, fmt.Errorf("invalid personal data key ID: %s", keyID)
+	}
+
+	var key []byte
+	if err := s.session.Query("SELECT key FROM personal_data_keys WHERE key_id = ?", keyUUID).WithContext(ctx).Scan(&key); err != nil {
+		if err == gocql.ErrNotFound {
+			return PersonalDataKey{}, false, nil
+		}
+
+		return PersonalDataKey{}, false, fmt.Errorf("failed to get personal data key: %w", err)
+	}
+
+	return PersonalDataKey{ID: keyID, Key: key}, true, nil
+}
+
+func (s CassandraPersonalDataKeyStore) HasUserKey(ctx context.Context, userID string) (bool, error) {
+	var keyID gocql.UUID
+	if err := s.session.Query("SELECT key_id FROM user_personal_data_keys WHERE user_id = ?", userID).WithContext(ctx).Scan(&keyID); err != nil {
+		if err == gocql.ErrNotFound {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get user personal data key: %w", err)
+	}
+
+	return true, nil
+}
+
+// DeleteUserKey destroys the user's key, after which their personal data can no longer be decrypted.
+func (s CassandraPersonalDataKeyStore) DeleteUserKey(ctx context.Context, userID string) (bool, error) {
+	var keyID gocql.UUID
+	if err := s.session.Query("SELECT key_id FROM user_personal_data_keys WHERE user_id = ?", userID).WithContext(ctx).Scan(&keyID); err != nil {
+		if err == gocql.ErrNotFound {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get user personal data key: %w", err)
+	}
+
+	if err := s.session.Query("DELETE FROM personal_data_keys WHERE key_id = ?", keyID).WithContext(ctx).Exec(); err != nil {
+		return false, fmt.Errorf("failed to delete personal data key: %w", err)
+	}
+
+	if err := s.session.Query("DELETE FROM user_personal_data_keys WHERE user_id = ?", userID).WithContext(ctx).Exec(); err != nil {
+		return false, fmt.Errorf("failed to delete user personal data key: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s CassandraPersonalDataKeyStore) createUserKey(ctx context.Context, userID string) (PersonalDataKey, error) {
+	key := make([]byte, personalDataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return PersonalDataKey{}, fmt.Errorf("failed to generate personal data key: %w", err)
+	}
+
+	keyID, err := gocql.RandomUUID()
+	if err != nil {
+		return PersonalDataKey{}, fmt.Errorf("failed to generate personal data key ID: %w", err)
+	}
+
+	if err := s.session.Query("INSERT INTO personal_data_keys (key_id, key) VALUES (?, ?)", keyID, key).WithContext(ctx).Exec(); err != nil {
+		return PersonalDataKey{}, fmt.Errorf("failed to store personal data key: %w", err)
+	}
+
+	var existingUserID string
+	var existingKeyID gocql.UUID
+	applied, err := s.session.Query("INSERT INTO user_personal_data_keys (user_id, key_id) VALUES (?, ?) IF NOT EXISTS", userID, keyID).WithContext(ctx).ScanCAS(&existingUserID, &existingKeyID)
+	if err != nil {
+		return PersonalDataKey{}, fmt.Errorf("failed to store user personal data key: %w", err)
+	}
+
+	if applied {
+		return PersonalDataKey{ID: keyID.String(), Key: key}, nil
+	}
+
+	// Another writer created the user's key first, so drop ours and use theirs
+	if err := s.session.Query("DELETE FROM personal_data_keys WHERE key_id = ?", keyID).WithContext(ctx).Exec(); err != nil {
+		return PersonalDataKey{}, fmt.Errorf("failed to delete unused personal data key: %w", err)
+	}
+
+	existing, ok, err := s.GetKey(ctx, existingKeyID.String())
+	if err != nil {
+		return PersonalDataKey{}, err
+	}
+
+	if !ok {
+		return PersonalDataKey{}, fmt.Errorf("personal data key %s is missing", existingKeyID)
+	}
+
+	return existing, nil
+}
+
+func (s CassandraPersonalDataKeyStore) init() error {
+	if err := s.session.Query(
+		`CREATE TABLE IF NOT EXISTS personal_data_keys (
+			key_id uuid PRIMARY KEY,
+			key blob
+		)`,
+	).Exec(); err != nil {
+		return fmt.Errorf("failed to create personal_data_keys table: %w", err)
+	}
+
+	if err := s.session.Query(
+		`CREATE TABLE IF NOT EXISTS user_personal_data_keys (
+			user_id text PRIMARY KEY,
+			key_id uuid
+		)`,
+	).Exec(); err != nil {
+		return fmt.Errorf("failed to create user_personal_data_keys table: %w", err)
+	}
+
+	return nil
+}
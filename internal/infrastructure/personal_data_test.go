@@ -0,0 +1,74 @@
+package infrastructure_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/infrastructure"
+	"github.com/stretchr/testify/assert"
+)
+
+type testEvent struct {
+	ID     string `json:"id"`
+	UserID string `json:"userId" personal:"subject"`
+	Name   string `json:"name" personal:"true"`
+}
+
+func (e testEvent) EventType() es.EventType {
+	return "TestEvent"
+}
+
+type memoryKeyStore struct {
+	userKeys map[string]string
+	keys     map[string]infrastructure.PersonalDataKey
+}
+
+func (s *memoryKeyStore) GetOrCreateUserKey(ctx context.Context, userID string) (infrastructure.PersonalDataKey, error) {
+	if keyID, ok := s.userKeys[userID]; ok {
+		return s.keys[keyID], nil
+	}
+
+	key := infrastructure.PersonalDataKey{ID: fmt.Sprintf("key-%d", len(s.keys)+1), Key: []byte(strings.Repeat("k", 32))}
+	s.userKeys[userID] = key.ID
+	s.keys[key.ID] = key
+
+	return key, nil
+}
+
+func (s *memoryKeyStore) GetKey(ctx context.Context, keyID string) (infrastructure.PersonalDataKey, bool, error) {
+	key, ok := s.keys[keyID]
+
+	return key, ok, nil
+}
+
+func Test_PersonalDataProtector(t *testing.T) {
+	ctx := context.Background()
+	keyStore := &memoryKeyStore{userKeys: map[string]string{}, keys: map[string]infrastructure.PersonalDataKey{}}
+	protector := infrastructure.NewPersonalDataProtector(keyStore)
+
+	event := testEvent{ID: "1", UserID: "user-1", Name: "Summer House"}
+
+	protected, err := protector.Protect(ctx, event)
+	assert.NoError(t, err)
+
+	protectedEvent := protected.(testEvent)
+	assert.Equal(t, "1", protectedEvent.ID)
+	assert.NotContains(t, protectedEvent.UserID, "user-1")
+	assert.NotContains(t, protectedEvent.Name, "Summer House")
+
+	unprotected, err := protector.Unprotect(ctx, protected)
+	assert.NoError(t, err)
+	assert.Equal(t, event, unprotected)
+	assert.True(t, infrastructure.HasReadablePersonalData(unprotected))
+
+	// Destroying the key erases the personal data
+	delete(keyStore.keys, "key-1")
+
+	erased, err := protector.Unprotect(ctx, protected)
+	assert.NoError(t, err)
+	assert.Equal(t, testEvent{ID: "1", UserID: infrastructure.ErasedPersonalData, Name: infrastructure.ErasedPersonalData}, erased)
+	assert.False(t, infrastructure.HasReadablePersonalData(erased))
+}
@@ -0,0 +1,31 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+)
+
+// EventConsumer delivers the events an event handler subscribes to, like KafkaEventMessaging and CatchUpEventConsumer.
+type EventConsumer interface {
+	EventStream
+	ConsumeEvents(ctx context.Context, handler EventHandler) error
+}
+
+// StartProcessManager feeds the events the process manager reacts to into the runner and fires its timeouts
+// until ctx is done. Pending timeouts are recorded in the runner's timeout store by a catch-up subscription,
+// which resumes from its checkpoint, so they survive restarts without the global event log being read again.
+func StartProcessManager(ctx context.Context, runner *es.ProcessRunner, eventStore es.EventStore, eventConsumer EventConsumer, checkpoints CheckpointStore) error {
+	if err := eventConsumer.ConsumeEvents(ctx, runner); err != nil {
+		return fmt.Errorf("failed to consume events for process manager %s: %w", runner.Name(), err)
+	}
+
+	if err := NewCatchUpSubscription(eventStore, eventConsumer, runner.Timeouts(), checkpoints).Start(ctx); err != nil {
+		return fmt.Errorf("failed to load timeouts of process manager %s: %w", runner.Name(), err)
+	}
+
+	go runner.RunTimeouts(ctx)
+
+	return nil
+}
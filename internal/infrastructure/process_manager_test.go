@@ -0,0 +1,295 @@
+package infrastructure_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bnkamalesh/errors"
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/infrastructure"
+	"github.com/cybre/home-inventory/internal/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+// A transfer debits one account when it is requested, and the transfer process then credits the other one.
+
+type openAccountCommand struct {
+	AccountID string
+}
+
+type depositCommand struct {
+	AccountID  string
+	TransferID string
+	Amount     int
+}
+
+type requestTransferCommand struct {
+	AccountID  string
+	TransferID string
+	To         string
+	Amount     int
+}
+
+func (c openAccountCommand) AggregateType() es.AggregateType     { return "Account" }
+func (c openAccountCommand) AggregateID() es.AggregateID         { return es.AggregateID(c.AccountID) }
+func (c depositCommand) AggregateType() es.AggregateType         { return "Account" }
+func (c depositCommand) AggregateID() es.AggregateID             { return es.AggregateID(c.AccountID) }
+func (c requestTransferCommand) AggregateType() es.AggregateType { return "Account" }
+func (c requestTransferCommand) AggregateID() es.AggregateID     { return es.AggregateID(c.AccountID) }
+
+type accountOpenedEvent struct{}
+
+type depositedEvent struct {
+	TransferID string `json:"transferId"`
+	Amount     int    `json:"amount"`
+}
+
+type transferRequestedEvent struct {
+	TransferID string `json:"transferId"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Amount     int    `json:"amount"`
+}
+
+func (e accountOpenedEvent) EventType() es.EventType     { return "AccountOpenedEvent" }
+func (e depositedEvent) EventType() es.EventType         { return "DepositedEvent" }
+func (e transferRequestedEvent) EventType() es.EventType { return "TransferRequestedEvent" }
+
+type account struct {
+	es.AggregateContext
+	open    bool
+	balance int
+}
+
+func (a *account) ApplyEvent(event es.EventData) {
+	switch e := event.(type) {
+	case accountOpenedEvent:
+		a.open = true
+	case depositedEvent:
+		a.balance += e.Amount
+	case transferRequestedEvent:
+		a.balance -= e.Amount
+	}
+}
+
+func (a *account) HandleCommand(ctx context.Context, command es.Command) ([]es.EventData, error) {
+	if _, ok := command.(openAccountCommand); ok {
+		return []es.EventData{accountOpenedEvent{}}, nil
+	}
+
+	if !a.open {
+		return nil, errors.NotFound("account not found")
+	}
+
+	switch c := command.(type) {
+	case depositCommand:
+		return []es.EventData{depositedEvent{TransferID: c.TransferID, Amount: c.Amount}}, nil
+	case requestTransferCommand:
+		return []es.EventData{transferRequestedEvent{TransferID: c.TransferID, From: c.AccountID, To: c.To, Amount: c.Amount}}, nil
+	}
+
+	return nil, errors.Validation("unknown command")
+}
+
+type transferState struct {
+	transferRequestedEvent
+	failed bool
+}
+
+func (s *transferState) ApplyEvent(event es.EventData) {
+	switch e := event.(type) {
+	case transferStartedEvent:
+		s.transferRequestedEvent = e.transferRequestedEvent
+	case transferFailedEvent:
+		s.failed = true
+	}
+}
+
+type transferStartedEvent struct {
+	transferRequestedEvent
+}
+
+type transferFailedEvent struct {
+	Reason string `json:"reason"`
+}
+
+func (e transferStartedEvent) EventType() es.EventType { return "TransferStartedEvent" }
+func (e transferFailedEvent) EventType() es.EventType  { return "TransferFailedEvent" }
+
+type transferProcess struct{}
+
+func (transferProcess) Name() string {
+	return "Transfer"
+}
+
+func (transferProcess) Events() []es.EventType {
+	return []es.EventType{transferRequestedEvent{}.EventType(), depositedEvent{}.EventType()}
+}
+
+func (transferProcess) ProcessID(event es.Event) (es.ProcessID, bool) {
+	switch e := event.Data.(type) {
+	case transferRequestedEvent:
+		return es.ProcessID(e.TransferID), true
+	case depositedEvent:
+		return es.ProcessID(e.TransferID), e.TransferID != ""
+	}
+
+	return "", false
+}
+
+func (transferProcess) NewState() es.ProcessState {
+	return &transferState{}
+}
+
+func (transferProcess) HandleEvent(ctx context.Context, process *es.Process, event es.Event) error {
+	switch e := event.Data.(type) {
+	case transferRequestedEvent:
+		process.Record(transferStartedEvent{e})
+		process.Dispatch(depositCommand{AccountID: e.To, TransferID: e.TransferID, Amount: e.Amount})
+		process.ScheduleTimeout("deposit", time.Minute)
+	case depositedEvent:
+		process.Complete()
+	}
+
+	return nil
+}
+
+func (transferProcess) HandleTimeout(ctx context.Context, process *es.Process, timeout string) error {
+	process.Record(transferFailedEvent{Reason: "deposit timed out"})
+	process.Complete()
+
+	return nil
+}
+
+func (transferProcess) Compensate(ctx context.Context, process *es.Process, command es.Command, err error) error {
+	transfer := process.State().(*transferState)
+	process.Dispatch(depositCommand{AccountID: transfer.From, Amount: transfer.Amount})
+	process.Record(transferFailedEvent{Reason: err.Error()})
+	process.Complete()
+
+	return nil
+}
+
+func Test_ProcessManager(t *testing.T) {
+	ctx := context.Background()
+	es.RegisterAggregateRoot("Account", func(aggregateContext es.AggregateContext) es.AggregateRoot {
+		return &account{AggregateContext: aggregateContext}
+	})
+	for _, event := range []es.EventData{accountOpenedEvent{}, depositedEvent{}, transferRequestedEvent{}, transferStartedEvent{}, transferFailedEvent{}} {
+		es.RegisterEvent(event)
+	}
+
+	db, err := sqlite.NewDB(filepath.Join(t.TempDir(), "events.db"), "test")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	keyStore, err := infrastructure.NewSQLitePersonalDataKeyStore(db)
+	assert.NoError(t, err)
+
+	eventStore, err := infrastructure.NewSQLiteEventStore(db, infrastructure.NewPersonalDataProtector(keyStore))
+	assert.NoError(t, err)
+
+	balance := func(accountID string) int {
+		events, err := eventStore.GetEvents(ctx, "Account", es.AggregateID(accountID))
+		assert.NoError(t, err)
+
+		a := &account{}
+		for _, event := range events {
+			a.ApplyEvent(event.Data)
+		}
+
+		return a.balance
+	}
+
+	process := func(transferID string) (*transferState, bool) {
+		events, err := eventStore.GetEvents(ctx, "Transfer", es.AggregateID(transferID))
+		assert.NoError(t, err)
+
+		state, completed := &transferState{}, false
+		for _, event := range events {
+			state.ApplyEvent(event.Data)
+			_, done := event.Data.(es.ProcessCompletedEvent)
+			completed = completed || done
+		}
+
+		return state, completed
+	}
+
+	timeoutStore, err := infrastructure.NewSQLiteProcessTimeoutStore(db)
+	assert.NoError(t, err)
+
+	checkpoints, err := infrastructure.NewSQLiteCheckpointStore(db)
+	assert.NoError(t, err)
+
+	messaging := infrastructure.NewInProcessEventMessaging()
+	commandBus := es.NewCommandBus(eventStore, messaging, "test")
+	runner := es.NewProcessRunner(transferProcess{}, eventStore, messaging, commandBus, timeoutStore)
+	assert.NoError(t, infrastructure.StartProcessManager(ctx, runner, eventStore, messaging, checkpoints))
+
+	assert.NoError(t, commandBus.Dispatch(ctx, openAccountCommand{AccountID: "A"}))
+	assert.NoError(t, commandBus.Dispatch(ctx, openAccountCommand{AccountID: "B"}))
+	assert.NoError(t, commandBus.Dispatch(ctx, depositCommand{AccountID: "A", Amount: 100}))
+
+	t.Run("completes a transfer across accounts", func(t *testing.T) {
+		assert.NoError(t, commandBus.Dispatch(ctx, requestTransferCommand{AccountID: "A", TransferID: "t1", To: "B", Amount: 30}))
+
+		assert.Equal(t, 70, balance("A"))
+		assert.Equal(t, 30, balance("B"))
+
+		state, completed := process("t1")
+		assert.True(t, completed)
+		assert.False(t, state.failed)
+
+		// Completing cancelled the timeout
+		assert.NoError(t, runner.FireDueTimeouts(ctx, time.Now().Add(time.Hour)))
+		_, completed = process("t1")
+		assert.True(t, completed)
+	})
+
+	t.Run("compensates when a command is rejected", func(t *testing.T) {
+		assert.NoError(t, commandBus.Dispatch(ctx, requestTransferCommand{AccountID: "A", TransferID: "t2", To: "C", Amount: 20}))
+
+		assert.Equal(t, 70, balance("A"))
+		assert.Equal(t, 0, balance("C"))
+
+		state, completed := process("t2")
+		assert.True(t, completed)
+		assert.True(t, state.failed)
+	})
+
+	t.Run("ignores redelivered events and fires timeouts after a restart", func(t *testing.T) {
+		// Nothing reacts to events published here, as if the service stopped right after the transfer was requested
+		detached := infrastructure.NewInProcessEventMessaging()
+		detachedBus := es.NewCommandBus(eventStore, detached, "test")
+		assert.NoError(t, detachedBus.Dispatch(ctx, requestTransferCommand{AccountID: "A", TransferID: "t3", To: "B", Amount: 10}))
+
+		events, err := eventStore.GetEvents(ctx, "Account", "A")
+		assert.NoError(t, err)
+		requested := events[len(events)-1]
+
+		first := es.NewProcessRunner(transferProcess{}, eventStore, detached, detachedBus, timeoutStore)
+		assert.NoError(t, first.HandleEvent(ctx, requested))
+		assert.NoError(t, first.HandleEvent(ctx, requested))
+		assert.Equal(t, 40, balance("B"))
+
+		// The timeouts were followed up to here, so a restart only reads the log after that
+		checkpoint, err := checkpoints.GetCheckpoint(ctx, runner.Timeouts().Name())
+		assert.NoError(t, err)
+		assert.Greater(t, checkpoint, uint64(0))
+
+		// The deposit never reached the process, so after a restart its timeout is still pending
+		restarted := es.NewProcessRunner(transferProcess{}, eventStore, detached, detachedBus, timeoutStore)
+		assert.NoError(t, infrastructure.NewCatchUpSubscription(eventStore, detached, restarted.Timeouts(), checkpoints).Start(ctx))
+
+		assert.NoError(t, restarted.FireDueTimeouts(ctx, time.Now()))
+		_, completed := process("t3")
+		assert.False(t, completed)
+
+		assert.NoError(t, restarted.FireDueTimeouts(ctx, time.Now().Add(time.Hour)))
+		state, completed := process("t3")
+		assert.True(t, completed)
+		assert.True(t, state.failed)
+	})
+}
@@ -0,0 +1,102 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/gocql/gocql"
+)
+
+// CassandraProcessTimeoutStore keeps the pending timeouts of process managers in Cassandra, one partition per
+// process manager. Only pending timeouts are kept, so a partition stays small enough to be read whole.
+type CassandraProcessTimeoutStore struct {
+	session *gocql.Session
+}
+
+func NewCassandraProcessTimeoutStore(session *gocql.Session) (*CassandraProcessTimeoutStore, error) {
+	timeoutStore := &CassandraProcessTimeoutStore{
+		session: session,
+	}
+
+	if err := timeoutStore.init(); err != nil {
+		return nil, err
+	}
+
+	return timeoutStore, nil
+}
+
+func (s CassandraProcessTimeoutStore) SaveTimeout(ctx context.Context, processType es.AggregateType, timeout es.ProcessTimeout) error {
+	if err := s.session.Query(
+		"INSERT INTO process_timeouts (process_type, timeout_id, process_id, name, deadline) VALUES (?, ?, ?, ?, ?)",
+		processType,
+		timeout.TimeoutID,
+		timeout.ProcessID,
+		timeout.Name,
+		timeout.Deadline,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to save process timeout: %w", err)
+	}
+
+	return nil
+}
+
+func (s CassandraProcessTimeoutStore) DeleteTimeout(ctx context.Context, processType es.AggregateType, timeoutID string) error {
+	if err := s.session.Query(
+		"DELETE FROM process_timeouts WHERE process_type = ? AND timeout_id = ?",
+		processType,
+		timeoutID,
+	).WithContext(ctx).Exec(); err != nil {
+		return fmt.Errorf("failed to delete process timeout: %w", err)
+	}
+
+	return nil
+}
+
+func (s CassandraProcessTimeoutStore) DueTimeouts(ctx context.Context, processType es.AggregateType, now time.Time) ([]es.ProcessTimeout, error) {
+	scanner := s.session.Query(
+		"SELECT process_id, timeout_id, name, deadline FROM process_timeouts WHERE process_type = ?",
+		processType,
+	).WithContext(ctx).Iter().Scanner()
+
+	timeouts := []es.ProcessTimeout{}
+	for scanner.Next() {
+		var timeout es.ProcessTimeout
+		if err := scanner.Scan(&timeout.ProcessID, &timeout.TimeoutID, &timeout.Name, &timeout.Deadline); err != nil {
+			return nil, fmt.Errorf("failed to scan process timeout: %w", err)
+		}
+
+		if timeout.Deadline <= now.UnixMilli() {
+			timeouts = append(timeouts, timeout)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get due process timeouts: %w", err)
+	}
+
+	sort.Slice(timeouts, func(i, j int) bool {
+		return timeouts[i].Deadline < timeouts[j].Deadline
+	})
+
+	return timeouts, nil
+}
+
+func (s CassandraProcessTimeoutStore) init() error {
+	if err := s.session.Query(
+		`CREATE TABLE IF NOT EXISTS process_timeouts (
+			process_type text,
+			timeout_id text,
+			process_id text,
+			name text,
+			deadline bigint,
+			PRIMARY KEY (process_type, timeout_id)
+		)`,
+	).Exec(); err != nil {
+		return fmt.Errorf("failed to create process_timeouts table: %w", err)
+	}
+
+	return nil
+}
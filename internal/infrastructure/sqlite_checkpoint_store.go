@@ -0,0 +1,107 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SQLiteCheckpointStore keeps subscription checkpoints in the SQLite file holding the event log.
+type SQLiteCheckpointStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteCheckpointStore(db *sql.DB) (*SQLiteCheckpointStore, error) {
+	checkpointStore := &SQLiteCheckpointStore{
+		db: db,
+	}
+
+	if err := checkpointStore.init(); err != nil {
+		return nil, err
+	}
+
+	return checkpointStore, nil
+}
+
+func (s SQLiteCheckpointStore) GetCheckpoint(ctx context.Context, subscription string) (uint64, error) {
+	var position uint64
+	if err := s.db.QueryRowContext(ctx, "SELECT position FROM subscription_checkpoints WHERE subscription = ?", subscription).Scan(&position); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("failed to get checkpoint of %s: %w", subscription, err)
+	}
+
+	return position, nil
+}
+
+func (s SQLiteCheckpointStore) SaveCheckpoint(ctx context.Context, subscription string, position uint64) error {
+	if _, err := s.db.ExecContext(
+		ctx,
+		"INSERT INTO subscription_checkpoints (subscription, position) VALUES (?, ?) ON CONFLICT (subscription) DO UPDATE SET position = excluded.position",
+		subscription,
+		position,
+	); err != nil {
+		return fmt.Errorf("failed to save checkpoint of %s: %w", subscription, err)
+	}
+
+	return nil
+}
+
+func (s SQLiteCheckpointStore) ClaimCheckpoint(ctx context.Context, subscription, owner string, lease time.Duration) (bool, error) {
+	now := time.Now()
+	result, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO subscription_leases (subscription, owner, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (subscription) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+		WHERE subscription_leases.owner = excluded.owner OR subscription_leases.expires_at <= ?`,
+		subscription,
+		owner,
+		now.Add(lease).UnixMilli(),
+		now.UnixMilli(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim checkpoint of %s: %w", subscription, err)
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim checkpoint of %s: %w", subscription, err)
+	}
+
+	return claimed > 0, nil
+}
+
+func (s SQLiteCheckpointStore) ReleaseCheckpoint(ctx context.Context, subscription, owner string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM subscription_leases WHERE subscription = ? AND owner = ?", subscription, owner); err != nil {
+		return fmt.Errorf("failed to release checkpoint of %s: %w", subscription, err)
+	}
+
+	return nil
+}
+
+func (s SQLiteCheckpointStore) init() error {
+	if _, err := s.db.Exec(
+		`CREATE TABLE IF NOT EXISTS subscription_checkpoints (
+			subscription TEXT PRIMARY KEY,
+			position INTEGER NOT NULL
+		)`,
+	); err != nil {
+		return fmt.Errorf("failed to create subscription_checkpoints table: %w", err)
+	}
+
+	if _, err := s.db.Exec(
+		`CREATE TABLE IF NOT EXISTS subscription_leases (
+			subscription TEXT PRIMARY KEY,
+			owner TEXT NOT NULL,
+			expires_at INTEGER NOT NULL
+		)`,
+	); err != nil {
+		return fmt.Errorf("failed to create subscription_leases table: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,294 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/sqlite"
+)
+
+// SQLiteEventStore keeps the event history in a single SQLite file for single-node deployments.
+// The primary key on aggregate and version rejects concurrent writes of the same aggregate version.
+// The global event log is a rowid table written in the same transaction, so its positions have no gaps.
+type SQLiteEventStore struct {
+	db                    *sql.DB
+	personalDataProtector *PersonalDataProtector
+}
+
+func NewSQLiteEventStore(db *sql.DB, personalDataProtector *PersonalDataProtector) (*SQLiteEventStore, error) {
+	eventStore := &SQLiteEventStore{
+		db:                    db,
+		personalDataProtector: personalDataProtector,
+	}
+
+	if err := eventStore.init(); err != nil {
+		return nil, err
+	}
+
+	return eventStore, nil
+}
+
+func (ses SQLiteEventStore) StoreEvents(ctx context.Context, events []es.Event) error {
+	// Protecting personal data reads the key store, so it has to happen before the transaction
+	// takes the only database connection.
+	eventData := make([][]byte, len(events))
+	eventMetadata := make([]string, len(events))
+	for i, event := range events {
+		data, err := encodeEventData(ctx, ses.personalDataProtector, event.Data)
+		if err != nil {
+			return err
+		}
+
+		metadata, err := encodeEventMetadata(ctx, ses.personalDataProtector, event.Metadata)
+		if err != nil {
+			return err
+		}
+
+		eventData[i] = data
+		eventMetadata[i] = metadata
+	}
+
+	tx, err := ses.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, event := range events {
+		if _, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO event_store (aggregate_type, aggregate_id, version, event_type, event_data, timestamp, metadata) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			event.AggregateType,
+			event.AggregateID,
+			event.Version,
+			event.EventType,
+			string(eventData[i]),
+			event.Timestamp,
+			eventMetadata[i],
+		); err != nil {
+			if sqlite.IsConstraintViolation(err) {
+				return fmt.Errorf("failed to store version %d of %s %s: %w", event.Version, event.AggregateType, event.AggregateID, es.ErrVersionConflict)
+			}
+
+			return fmt.Errorf("failed to store event: %w", err)
+		}
+
+		result, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO event_log (aggregate_type, aggregate_id, version) VALUES (?, ?, ?)",
+			event.AggregateType,
+			event.AggregateID,
+			event.Version,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to append event to log: %w", err)
+		}
+
+		position, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get event log position: %w", err)
+		}
+
+		events[i].Position = uint64(position)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit events: %w", err)
+	}
+
+	return nil
+}
+
+func (ses SQLiteEventStore) GetEvents(ctx context.Context, aggregateType es.AggregateType, aggregateID es.AggregateID) ([]es.Event, error) {
+	type storedEvent struct {
+		eventType string
+		eventData string
+		timestamp int64
+		version   uint
+		position  uint64
+		metadata  string
+	}
+
+	rows, err := ses.db.QueryContext(
+		ctx,
+		`SELECT s.event_type, s.event_data, s.timestamp, s.version, l.position, s.metadata
+		FROM event_store s
+		JOIN event_log l ON l.aggregate_type = s.aggregate_type AND l.aggregate_id = s.aggregate_id AND l.version = s.version
+		WHERE s.aggregate_type = ? AND s.aggregate_id = ?
+		ORDER BY s.version`,
+		aggregateType,
+		aggregateID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	storedEvents := []storedEvent{}
+	for rows.Next() {
+		var stored storedEvent
+		if err := rows.Scan(&stored.eventType, &stored.eventData, &stored.timestamp, &stored.version, &stored.position, &stored.metadata); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		storedEvents = append(storedEvents, stored)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	rows.Close()
+
+	events := make([]es.Event, 0, len(storedEvents))
+	for _, stored := range storedEvents {
+		eventData, err := decodeEventData(ctx, ses.personalDataProtector, es.EventType(stored.eventType), []byte(stored.eventData))
+		if err != nil {
+			return nil, err
+		}
+
+		eventMetadata, err := decodeEventMetadata(ctx, ses.personalDataProtector, stored.metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, es.Event{
+			AggregateType: aggregateType,
+			AggregateID:   aggregateID,
+			EventType:     es.EventType(stored.eventType),
+			Data:          eventData,
+			Timestamp:     stored.timestamp,
+			Version:       stored.version,
+			Position:      stored.position,
+			Metadata:      eventMetadata,
+		})
+	}
+
+	return events, nil
+}
+
+func (ses SQLiteEventStore) ReadAll(ctx context.Context, fromPosition uint64, batchSize int) ([]es.Event, error) {
+	type storedEvent struct {
+		aggregateType string
+		aggregateID   string
+		eventType     string
+		eventData     string
+		timestamp     int64
+		version       uint
+		position      uint64
+		metadata      string
+	}
+
+	rows, err := ses.db.QueryContext(
+		ctx,
+		`SELECT s.aggregate_type, s.aggregate_id, s.event_type, s.event_data, s.timestamp, s.version, l.position, s.metadata
+		FROM event_log l
+		JOIN event_store s ON s.aggregate_type = l.aggregate_type AND s.aggregate_id = l.aggregate_id AND s.version = l.version
+		WHERE l.position >= ?
+		ORDER BY l.position
+		LIMIT ?`,
+		fromPosition,
+		batchSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event log: %w", err)
+	}
+	defer rows.Close()
+
+	storedEvents := []storedEvent{}
+	for rows.Next() {
+		var stored storedEvent
+		if err := rows.Scan(&stored.aggregateType, &stored.aggregateID, &stored.eventType, &stored.eventData, &stored.timestamp, &stored.version, &stored.position, &stored.metadata); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		storedEvents = append(storedEvents, stored)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	rows.Close()
+
+	events := make([]es.Event, 0, len(storedEvents))
+	for _, stored := range storedEvents {
+		eventData, err := decodeEventData(ctx, ses.personalDataProtector, es.EventType(stored.eventType), []byte(stored.eventData))
+		if err != nil {
+			return nil, err
+		}
+
+		eventMetadata, err := decodeEventMetadata(ctx, ses.personalDataProtector, stored.metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, es.Event{
+			AggregateType: es.AggregateType(stored.aggregateType),
+			AggregateID:   es.AggregateID(stored.aggregateID),
+			EventType:     es.EventType(stored.eventType),
+			Data:          eventData,
+			Timestamp:     stored.timestamp,
+			Version:       stored.version,
+			Position:      stored.position,
+			Metadata:      eventMetadata,
+		})
+	}
+
+	return events, nil
+}
+
+func (ses SQLiteEventStore) init() error {
+	if _, err := ses.db.Exec(
+		`CREATE TABLE IF NOT EXISTS event_store (
+			aggregate_type TEXT NOT NULL,
+			aggregate_id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			event_type TEXT NOT NULL,
+			event_data TEXT NOT NULL,
+			timestamp INTEGER NOT NULL,
+			metadata TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (aggregate_type, aggregate_id, version)
+		)`,
+	); err != nil {
+		return fmt.Errorf("failed to create event_store table: %w", err)
+	}
+
+	// Tables created before events carried metadata get the column added
+	var hasMetadata bool
+	if err := ses.db.QueryRow("SELECT COUNT(*) > 0 FROM pragma_table_info('event_store') WHERE name = 'metadata'").Scan(&hasMetadata); err != nil {
+		return fmt.Errorf("failed to inspect event_store table: %w", err)
+	}
+
+	if !hasMetadata {
+		if _, err := ses.db.Exec("ALTER TABLE event_store ADD COLUMN metadata TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add metadata to event_store table: %w", err)
+		}
+	}
+
+	if _, err := ses.db.Exec(
+		`CREATE TABLE IF NOT EXISTS event_log (
+			position INTEGER PRIMARY KEY,
+			aggregate_type TEXT NOT NULL,
+			aggregate_id TEXT NOT NULL,
+			version INTEGER NOT NULL,
+			UNIQUE (aggregate_type, aggregate_id, version)
+		)`,
+	); err != nil {
+		return fmt.Errorf("failed to create event_log table: %w", err)
+	}
+
+	// Events stored before the log existed are appended in the order they were written
+	if _, err := ses.db.Exec(
+		`INSERT INTO event_log (aggregate_type, aggregate_id, version)
+		SELECT s.aggregate_type, s.aggregate_id, s.version FROM event_store s
+		WHERE NOT EXISTS (
+			SELECT 1 FROM event_log l WHERE l.aggregate_type = s.aggregate_type AND l.aggregate_id = s.aggregate_id AND l.version = s.version
+		)
+		ORDER BY s.timestamp, s.rowid`,
+	); err != nil {
+		return fmt.Errorf("failed to backfill event_log table: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package infrastructure_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/infrastructure"
+	"github.com/cybre/home-inventory/internal/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SQLiteEventStore(t *testing.T) {
+	ctx := context.Background()
+	es.RegisterEvent(testEvent{})
+
+	db, err := sqlite.NewDB(filepath.Join(t.TempDir(), "events.db"), "test")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	keyStore, err := infrastructure.NewSQLitePersonalDataKeyStore(db)
+	assert.NoError(t, err)
+
+	eventStore, err := infrastructure.NewSQLiteEventStore(db, infrastructure.NewPersonalDataProtector(keyStore))
+	assert.NoError(t, err)
+
+	event := func(version uint, name string) es.Event {
+		data := testEvent{ID: "1", UserID: "user-1", Name: name}
+		metadata := es.EventMetadata{EventID: fmt.Sprintf("event-%d", version), CorrelationID: "correlation-1", CausationID: "request-1", ActorID: "user-1", Source: "test"}
+		return es.Event{AggregateType: "Test", AggregateID: "aggregate-1", EventType: data.EventType(), Data: data, Timestamp: 1, Version: version, Metadata: metadata}
+	}
+
+	stored := []es.Event{event(1, "Summer House"), event(2, "Winter House")}
+	assert.NoError(t, eventStore.StoreEvents(ctx, stored))
+
+	// A second writer of an existing version is rejected, along with the rest of its batch
+	err = eventStore.StoreEvents(ctx, []es.Event{event(3, "Beach House"), event(2, "Mountain House")})
+	assert.ErrorIs(t, err, es.ErrVersionConflict)
+
+	events, err := eventStore.GetEvents(ctx, "Test", "aggregate-1")
+	assert.NoError(t, err)
+	assert.Equal(t, stored, events)
+
+	// Destroying the user's key erases the personal data from the history
+	deleted, err := keyStore.DeleteUserKey(ctx, "user-1")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	events, err = eventStore.GetEvents(ctx, "Test", "aggregate-1")
+	assert.NoError(t, err)
+	assert.Equal(t, infrastructure.ErasedPersonalData, events[0].Data.(testEvent).Name)
+	assert.Equal(t, infrastructure.ErasedPersonalData, events[0].Metadata.ActorID)
+	assert.Equal(t, "correlation-1", events[0].Metadata.CorrelationID)
+}
@@ -0,0 +1,178 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// SQLitePersonalDataKeyStore keeps per-user encryption keys for single-node deployments.
+// The keys live in their own tables, so deleting them erases personal data just like with Cassandra.
+type SQLitePersonalDataKeyStore struct {
+	db *sql.DB
+}
+
+func NewSQLitePersonalDataKeyStore(db *sql.DB) (*SQLitePersonalDataKeyStore, error) {
+	keyStore := &SQLitePersonalDataKeyStore{
+		db: db,
+	}
+
+	if err := keyStore.init(); err != nil {
+		return nil, err
+	}
+
+	return keyStore, nil
+}
+
+func (s SQLitePersonalDataKeyStore) GetOrCreateUserKey(ctx context.Context, userID string) (PersonalDataKey, error) {
+	keyID, ok, err := s.getUserKeyID(ctx, userID)
+	if err != nil {
+		return PersonalDataKey{}, err
+	}
+
+	if ok {
+		key, ok, err := s.GetKey(ctx, keyID)
+		if err != nil {
+			return PersonalDataKey{}, err
+		}
+
+		if !ok {
+			return PersonalDataKey{}, fmt.Errorf("personal data key %s is missing", keyID)
+		}
+
+		return key, nil
+	}
+
+	return s.createUserKey(ctx, userID)
+}
+
+func (s SQLitePersonalDataKeyStore) GetKey(ctx context.Context, keyID string) (PersonalDataKey, bool, error) {
+	var key []byte
+	if err := s.db.QueryRowContext(ctx, "SELECT key FROM personal_data_keys WHERE key_id = ?", keyID).Scan(&key); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PersonalDataKey{}, false, nil
+		}
+
+		return PersonalDataKey{}, false, fmt.Errorf("failed to get personal data key: %w", err)
+	}
+
+	return PersonalDataKey{ID: keyID, Key: key}, true, nil
+}
+
+func (s SQLitePersonalDataKeyStore) HasUserKey(ctx context.Context, userID string) (bool, error) {
+	_, ok, err := s.getUserKeyID(ctx, userID)
+
+	return ok, err
+}
+
+// DeleteUserKey destroys the user's key, after which their personal data can no longer be decrypted.
+func (s SQLitePersonalDataKeyStore) DeleteUserKey(ctx context.Context, userID string) (bool, error) {
+	keyID, ok, err := s.getUserKeyID(ctx, userID)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM personal_data_keys WHERE key_id = ?", keyID); err != nil {
+		return false, fmt.Errorf("failed to delete personal data key: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_personal_data_keys WHERE user_id = ?", userID); err != nil {
+		return false, fmt.Errorf("failed to delete user personal data key: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to delete user personal data key: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s SQLitePersonalDataKeyStore) getUserKeyID(ctx context.Context, userID string) (string, bool, error) {
+	var keyID string
+	if err := s.db.QueryRowContext(ctx, "SELECT key_id FROM user_personal_data_keys WHERE user_id = ?", userID).Scan(&keyID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+
+		return "", false, fmt.Errorf("failed to get user personal data key: %w", err)
+	}
+
+	return keyID, true, nil
+}
+
+func (s SQLitePersonalDataKeyStore) createUserKey(ctx context.Context, userID string) (PersonalDataKey, error) {
+	key := make([]byte, personalDataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return PersonalDataKey{}, fmt.Errorf("failed to generate personal data key: %w", err)
+	}
+
+	keyID := uuid.NewString()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return PersonalDataKey{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Another writer may have created the user's key since it was looked up, in which case theirs is kept
+	var existingKeyID string
+	err = tx.QueryRowContext(ctx, "SELECT key_id FROM user_personal_data_keys WHERE user_id = ?", userID).Scan(&existingKeyID)
+	if err == nil {
+		var existingKey []byte
+		if err := tx.QueryRowContext(ctx, "SELECT key FROM personal_data_keys WHERE key_id = ?", existingKeyID).Scan(&existingKey); err != nil {
+			return PersonalDataKey{}, fmt.Errorf("failed to get personal data key %s: %w", existingKeyID, err)
+		}
+
+		return PersonalDataKey{ID: existingKeyID, Key: existingKey}, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return PersonalDataKey{}, fmt.Errorf("failed to get user personal data key: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO personal_data_keys (key_id, key) VALUES (?, ?)", keyID, key); err != nil {
+		return PersonalDataKey{}, fmt.Errorf("failed to store personal data key: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO user_personal_data_keys (user_id, key_id) VALUES (?, ?)", userID, keyID); err != nil {
+		return PersonalDataKey{}, fmt.Errorf("failed to store user personal data key: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return PersonalDataKey{}, fmt.Errorf("failed to store user personal data key: %w", err)
+	}
+
+	return PersonalDataKey{ID: keyID, Key: key}, nil
+}
+
+func (s SQLitePersonalDataKeyStore) init() error {
+	if _, err := s.db.Exec(
+		`CREATE TABLE IF NOT EXISTS personal_data_keys (
+			key_id TEXT PRIMARY KEY,
+			key BLOB NOT NULL
+		)`,
+	); err != nil {
+		return fmt.Errorf("failed to create personal_data_keys table: %w", err)
+	}
+
+	if _, err := s.db.Exec(
+		`CREATE TABLE IF NOT EXISTS user_personal_data_keys (
+			user_id TEXT PRIMARY KEY,
+			key_id TEXT NOT NULL
+		)`,
+	); err != nil {
+		return fmt.Errorf("failed to create user_personal_data_keys table: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+)
+
+// SQLiteProcessTimeoutStore keeps the pending timeouts of process managers in the SQLite file holding the event log.
+type SQLiteProcessTimeoutStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteProcessTimeoutStore(db *sql.DB) (*SQLiteProcessTimeoutStore, error) {
+	timeoutStore := &SQLiteProcessTimeoutStore{
+		db: db,
+	}
+
+	if err := timeoutStore.init(); err != nil {
+		return nil, err
+	}
+
+	return timeoutStore, nil
+}
+
+func (s SQLiteProcessTimeoutStore) SaveTimeout(ctx context.Context, processType es.AggregateType, timeout es.ProcessTimeout) error {
+	if _, err := s.db.ExecContext(
+		ctx,
+		"INSERT OR REPLACE INTO process_timeouts (process_type, timeout_id, process_id, name, deadline) VALUES (?, ?, ?, ?, ?)",
+		processType,
+		timeout.TimeoutID,
+		timeout.ProcessID,
+		timeout.Name,
+		timeout.Deadline,
+	); err != nil {
+		return fmt.Errorf("failed to save process timeout: %w", err)
+	}
+
+	return nil
+}
+
+func (s SQLiteProcessTimeoutStore) DeleteTimeout(ctx context.Context, processType es.AggregateType, timeoutID string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM process_timeouts WHERE process_type = ? AND timeout_id = ?", processType, timeoutID); err != nil {
+		return fmt.Errorf("failed to delete process timeout: %w", err)
+	}
+
+	return nil
+}
+
+func (s SQLiteProcessTimeoutStore) DueTimeouts(ctx context.Context, processType es.AggregateType, now time.Time) ([]es.ProcessTimeout, error) {
+	rows, err := s.db.QueryContext(
+		ctx,
+		"SELECT process_id, timeout_id, name, deadline FROM process_timeouts WHERE process_type = ? AND deadline <= ? ORDER BY deadline",
+		processType,
+		now.UnixMilli(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due process timeouts: %w", err)
+	}
+	defer rows.Close()
+
+	timeouts := []es.ProcessTimeout{}
+	for rows.Next() {
+		var timeout es.ProcessTimeout
+		if err := rows.Scan(&timeout.ProcessID, &timeout.TimeoutID, &timeout.Name, &timeout.Deadline); err != nil {
+			return nil, fmt.Errorf("failed to scan process timeout: %w", err)
+		}
+
+		timeouts = append(timeouts, timeout)
+	}
+
+	return timeouts, rows.Err()
+}
+
+func (s SQLiteProcessTimeoutStore) init() error {
+	if _, err := s.db.Exec(
+		`CREATE TABLE IF NOT EXISTS process_timeouts (
+			process_type TEXT NOT NULL,
+			timeout_id TEXT NOT NULL,
+			process_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			deadline INTEGER NOT NULL,
+			PRIMARY KEY (process_type, timeout_id)
+		)`,
+	); err != nil {
+		return fmt.Errorf("failed to create process_timeouts table: %w", err)
+	}
+
+	return nil
+}
@@ -2,30 +2,71 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"strconv"
+	"sync"
 
+	"github.com/cybre/home-inventory/internal/metrics"
+	"github.com/cybre/home-inventory/internal/tracing"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Consumer struct {
-	client *kgo.Client
+	client        *kgo.Client
+	consumerGroup string
+	workers       int
+	// broadcast consumers belong to no group, so there are no offsets to commit
+	broadcast bool
 }
 
-func NewConsumer(brokers []string, topic, consumerGroup string) (*Consumer, error) {
+// NewConsumer creates a consumer group member that handles records with the given number of workers.
+// Records with the same key always go to the same worker, so they are handled in order.
+func NewConsumer(brokers []string, topic, consumerGroup string, workers int) (*Consumer, error) {
 	cl, err := kgo.NewClient(
 		kgo.SeedBrokers(brokers...),
 		kgo.ConsumerGroup(consumerGroup),
 		kgo.ConsumeTopics(topic),
 		kgo.RequireStableFetchOffsets(),
+		kgo.DisableAutoCommit(),
+		kgo.BlockRebalanceOnPoll(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing Kafka consumer: %w", err)
 	}
 
-	return &Consumer{client: cl}, nil
+	return &Consumer{client: cl, consumerGroup: consumerGroup, workers: max(workers, 1)}, nil
 }
 
-func (c Consumer) Consume(ctx context.Context, callback func(Record)) error {
+// NewBroadcastConsumer creates a consumer reading every partition of the topic from its end, outside of any
+// consumer group, so it gets every record produced after it started no matter how many others read the topic.
+// Nothing is committed, so a new consumer does not get the records its predecessor missed. The name only labels
+// its metrics.
+func NewBroadcastConsumer(brokers []string, topic, name string, workers int) (*Consumer, error) {
+	cl, err := kgo.NewClient(
+		kgo.SeedBrokers(brokers...),
+		kgo.ConsumeTopics(topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtEnd()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing Kafka consumer: %w", err)
+	}
+
+	return &Consumer{client: cl, consumerGroup: name, workers: max(workers, 1), broadcast: true}, nil
+}
+
+// Consume passes records to the callback and commits their offsets once the callback has succeeded.
+// If the callback fails, the offsets handled without gaps are committed and the error is returned,
+// so the failed record and everything after it on its partition is delivered again by the next
+// consumer of the group.
+//
+// Each record is handled in a span continuing the trace carried in its headers.
+func (c Consumer) Consume(ctx context.Context, callback func(context.Context, Record) error) error {
 	for {
 		select {
 		case <-ctx.Done():
@@ -42,15 +83,160 @@ func (c Consumer) Consume(ctx context.Context, callback func(Record)) error {
 			return fmt.Errorf("error consuming message from Kafka: %w", fetches.Err())
 		}
 
-		fetches.EachRecord(func(record *kgo.Record) {
-			callback(Record{
-				Key:   record.Key,
-				Value: record.Value,
-			})
-		})
+		if err := c.handleRecords(ctx, fetches.Records(), callback); err != nil {
+			return err
+		}
+
+		c.recordLag(fetches)
+		c.client.AllowRebalance()
 	}
 }
 
+// handleRecords spreads the records over the workers by key. A worker stops at its first failure,
+// so no record is handled before an earlier record with the same key.
+func (c Consumer) handleRecords(ctx context.Context, records []*kgo.Record, callback func(context.Context, Record) error) error {
+	queues := make([][]int, c.workers)
+	for i, record := range records {
+		worker := workerFor(record.Key, c.workers)
+		queues[worker] = append(queues[worker], i)
+	}
+
+	handled := make([]bool, len(records))
+	errs := make([]error, c.workers)
+
+	var wg sync.WaitGroup
+	for worker, queue := range queues {
+		if len(queue) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(worker int, queue []int) {
+			defer wg.Done()
+
+			for _, i := range queue {
+				record := records[i]
+				if err := handleRecord(ctx, record, callback); err != nil {
+					errs[worker] = fmt.Errorf("error handling record at offset %d of partition %d: %w", record.Offset, record.Partition, err)
+					return
+				}
+
+				handled[i] = true
+			}
+		}(worker, queue)
+	}
+	wg.Wait()
+
+	handleErr := errors.Join(errs...)
+
+	if err := c.commit(ctx, committable(records, handled)); err != nil {
+		return fmt.Errorf("error committing offsets: %w", errors.Join(handleErr, err))
+	}
+
+	return handleErr
+}
+
+func handleRecord(ctx context.Context, record *kgo.Record, callback func(context.Context, Record) error) (err error) {
+	headers := headersOf(record)
+
+	ctx, span := tracing.Tracer().Start(
+		otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers)),
+		record.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationReceive,
+			semconv.MessagingDestinationName(record.Topic),
+			semconv.MessagingKafkaDestinationPartition(int(record.Partition)),
+			semconv.MessagingKafkaMessageOffset(int(record.Offset)),
+		),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	return callback(ctx, Record{
+		Key:     record.Key,
+		Value:   record.Value,
+		Headers: headers,
+	})
+}
+
+// committable returns, for every partition, the last record that was handled along with all records before it.
+func committable(records []*kgo.Record, handled []bool) []*kgo.Record {
+	type topicPartition struct {
+		topic     string
+		partition int32
+	}
+
+	last := map[topicPartition]*kgo.Record{}
+	blocked := map[topicPartition]bool{}
+	order := []topicPartition{}
+	for i, record := range records {
+		tp := topicPartition{record.Topic, record.Partition}
+		if blocked[tp] {
+			continue
+		}
+
+		if !handled[i] {
+			blocked[tp] = true
+			continue
+		}
+
+		if _, ok := last[tp]; !ok {
+			order = append(order, tp)
+		}
+		last[tp] = record
+	}
+
+	result := make([]*kgo.Record, 0, len(order))
+	for _, tp := range order {
+		result = append(result, last[tp])
+	}
+
+	return result
+}
+
+// recordLag sets the lag of every fetched partition to the records left after the last one handled.
+func (c Consumer) recordLag(fetches kgo.Fetches) {
+	fetches.EachPartition(func(partition kgo.FetchTopicPartition) {
+		if len(partition.Records) == 0 {
+			return
+		}
+
+		lastOffset := partition.Records[len(partition.Records)-1].Offset
+		metrics.ConsumerLag.
+			WithLabelValues(c.consumerGroup, partition.Topic, strconv.Itoa(int(partition.Partition))).
+			Set(float64(max(partition.HighWatermark-lastOffset-1, 0)))
+	})
+}
+
+func headersOf(record *kgo.Record) map[string]string {
+	headers := make(map[string]string, len(record.Headers))
+	for _, header := range record.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+
+	return headers
+}
+
+func workerFor(key []byte, workers int) int {
+	if workers == 1 {
+		return 0
+	}
+
+	hash := fnv.New32a()
+	hash.Write(key)
+
+	return int(hash.Sum32() % uint32(workers))
+}
+
+func (c Consumer) commit(ctx context.Context, records []*kgo.Record) error {
+	if len(records) == 0 || c.broadcast {
+		return nil
+	}
+
+	return c.client.CommitRecords(ctx, records...)
+}
+
 func (c Consumer) Close() {
 	c.client.Close()
 }
@@ -6,21 +6,29 @@ import (
 	"os"
 	"strconv"
 
+	"github.com/cybre/home-inventory/internal/tracing"
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Record struct {
-	Key   []byte
-	Value []byte
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
 }
 
 type Producer struct {
 	client *kgo.Client
+	topic  string
 }
 
 func NewProducer(brokers []string, topic string) (*Producer, error) {
-	producerId := strconv.FormatInt(int64(os.Getpid()), 10)
+	// A process may produce to several topics, each with its own transactional producer
+	producerId := strconv.FormatInt(int64(os.Getpid()), 10) + "-" + topic
 	cl, err := kgo.NewClient(
 		kgo.SeedBrokers(brokers...),
 		kgo.TransactionalID(producerId),
@@ -31,10 +39,23 @@ func NewProducer(brokers []string, topic string) (*Producer, error) {
 		return nil, fmt.Errorf("error initializing Kafka producer: %w", err)
 	}
 
-	return &Producer{client: cl}, nil
+	return &Producer{client: cl, topic: topic}, nil
 }
 
-func (p Producer) Produce(ctx context.Context, records ...Record) error {
+// Produce writes the records in a single transaction. The trace context is added to the headers of every record,
+// so consumers continue the trace.
+func (p Producer) Produce(ctx context.Context, records ...Record) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, p.topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationPublish,
+			semconv.MessagingDestinationName(p.topic),
+			semconv.MessagingBatchMessageCount(len(records)),
+		),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	if err := p.client.BeginTransaction(); err != nil {
 		return fmt.Errorf("unable to start transaction: %v", err)
 	}
@@ -43,8 +64,9 @@ func (p Producer) Produce(ctx context.Context, records ...Record) error {
 
 	for _, record := range records {
 		p.client.Produce(ctx, &kgo.Record{
-			Key:   record.Key,
-			Value: record.Value,
+			Key:     record.Key,
+			Value:   record.Value,
+			Headers: recordHeaders(ctx, record.Headers),
 		}, e.Promise())
 	}
 
@@ -63,6 +85,27 @@ func (p Producer) Produce(ctx context.Context, records ...Record) error {
 	return nil
 }
 
+func recordHeaders(ctx context.Context, headers map[string]string) []kgo.RecordHeader {
+	traceHeaders := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, traceHeaders)
+
+	recordHeaders := make([]kgo.RecordHeader, 0, len(headers)+len(traceHeaders))
+	for key, value := range headers {
+		recordHeaders = append(recordHeaders, kgo.RecordHeader{Key: key, Value: []byte(value)})
+	}
+
+	for key, value := range traceHeaders {
+		recordHeaders = append(recordHeaders, kgo.RecordHeader{Key: key, Value: []byte(value)})
+	}
+
+	return recordHeaders
+}
+
+// Ping checks that the producer can reach a broker.
+func (p Producer) Ping(ctx context.Context) error {
+	return p.client.Ping(ctx)
+}
+
 func (p Producer) Close() {
 	p.client.Close()
 }
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"github.com/labstack/echo-contrib/echoprometheus"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	namespace = "home_inventory"
+
+	// Path is where both services expose their metrics
+	Path = "/metrics"
+)
+
+// Results of a command dispatch
+const (
+	DispatchOK       = "ok"
+	DispatchConflict = "conflict"
+	DispatchError    = "error"
+)
+
+// Results of a cached request
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+var (
+	CommandDispatchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "command_bus",
+		Name:      "dispatch_duration_seconds",
+		Help:      "How long dispatching a command took, partitioned by command type and result (ok, conflict or error).",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"command", "result"})
+
+	EventsStored = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_stored_total",
+		Help:      "How many events were stored, partitioned by event type.",
+	}, []string{"event_type"})
+
+	EventsPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_published_total",
+		Help:      "How many events were published, partitioned by event type.",
+	}, []string{"event_type"})
+
+	EventsHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_handled_total",
+		Help:      "How many events were passed to event handlers, partitioned by handler.",
+	}, []string{"handler"})
+
+	EventHandlerErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_handler_errors_total",
+		Help:      "How many events event handlers failed on, partitioned by handler.",
+	}, []string{"handler"})
+
+	EventsDeadLettered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_dead_lettered_total",
+		Help:      "How many events were given up on after failing every attempt, partitioned by handler or consumer.",
+	}, []string{"handler"})
+
+	ConsumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "consumer_lag",
+		Help:      "How many records of a partition a consumer group has not handled yet, as of its last fetch.",
+	}, []string{"consumer", "topic", "partition"})
+
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http_cache",
+		Name:      "requests_total",
+		Help:      "How many cacheable requests were answered from the cache (hit) or sent on (miss).",
+	}, []string{"result"})
+)
+
+// Middleware records the rate, errors and duration of HTTP requests by route.
+func Middleware() echo.MiddlewareFunc {
+	return echoprometheus.NewMiddlewareWithConfig(echoprometheus.MiddlewareConfig{
+		Namespace: namespace,
+		Subsystem: "http",
+		Skipper: func(c echo.Context) bool {
+			return c.Path() == Path
+		},
+	})
+}
+
+// Handler serves the metrics of the process.
+func Handler() echo.HandlerFunc {
+	return echoprometheus.NewHandler()
+}
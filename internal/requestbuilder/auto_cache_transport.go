@@ -11,6 +11,7 @@ import (
 
 	"github.com/cybre/home-inventory/internal/cache"
 	"github.com/cybre/home-inventory/internal/logging"
+	"github.com/cybre/home-inventory/internal/metrics"
 	"github.com/pquerna/cachecontrol"
 	"github.com/pquerna/cachecontrol/cacheobject"
 )
@@ -43,6 +44,7 @@ func (t AutoCacheTransport) RoundTrip(req *http.Request) (*http.Response, error)
 
 	if reqDir == nil || !reqDir.NoCache {
 		if value, err := t.Cache.Get(req.Context(), t.CacheKey); err == nil {
+			metrics.CacheRequests.WithLabelValues(metrics.CacheHit).Inc()
 			return &http.Response{
 				StatusCode: http.StatusOK,
 				Body:       io.NopCloser(strings.NewReader(value)),
@@ -50,6 +52,8 @@ func (t AutoCacheTransport) RoundTrip(req *http.Request) (*http.Response, error)
 		}
 	}
 
+	metrics.CacheRequests.WithLabelValues(metrics.CacheMiss).Inc()
+
 	resp, err := t.Transport.RoundTrip(req)
 	if err != nil {
 		return nil, err
@@ -14,6 +14,8 @@ import (
 	"github.com/PuerkitoBio/rehttp"
 	"github.com/cybre/home-inventory/internal/cache"
 	"github.com/cybre/home-inventory/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 const (
@@ -172,6 +174,8 @@ func (r *RequestBuilder) Build(ctx context.Context) (*http.Request, error) {
 		req.Header.Set("X-Correlation-ID", ctx.Value("correlation_id").(string))
 	}
 
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
 	return req, nil
 }
 
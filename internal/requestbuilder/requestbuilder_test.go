@@ -4,11 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/cybre/home-inventory/internal/requestbuilder"
+	"github.com/cybre/home-inventory/internal/tracing"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func Test_RequestBuilder_Build(t *testing.T) {
@@ -61,3 +69,43 @@ func Test_RequestBuilder_Build(t *testing.T) {
 	assert.Equal(t, ctx, req.Context())
 	assert.Equal(t, "123", req.Header.Get("X-Correlation-ID"))
 }
+
+func Test_RequestBuilder_Build_PropagatesTraceContext(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider, err := tracing.NewTracerProvider(context.Background(), "test", sdktrace.WithSyncer(exporter))
+	assert.NoError(t, err)
+	defer tracerProvider.Shutdown(context.Background())
+
+	// The provider and propagator are global, so the ones of other tests are put back afterwards
+	previousTracerProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousTracerProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	// The server continues the trace of the request in its own span
+	e := echo.New()
+	e.Use(otelecho.Middleware("server"))
+	e.GET("/ping", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	ctx, span := tracing.Tracer().Start(context.Background(), "client")
+	resp, err := requestbuilder.New(http.MethodGet, server.URL+"/ping").Do(ctx)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	span.End()
+
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 2) {
+		assert.Equal(t, "/ping", spans[0].Name)
+		assert.Equal(t, "client", spans[1].Name)
+		assert.Equal(t, spans[1].SpanContext.TraceID(), spans[0].SpanContext.TraceID())
+		assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	}
+}
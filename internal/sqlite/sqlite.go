@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	sqlitedriver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// NewDB opens the SQLite database at path and runs the migrations of the service on it.
+// SQLite allows a single writer, so the pool is limited to one connection and writes queue up
+// behind each other instead of failing with SQLITE_BUSY.
+func NewDB(path string, service string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	databaseInstance, err := sqlite.WithInstance(db, &sqlite.Config{
+		MigrationsTable: "schema_migrations",
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create database instance for migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance(fmt.Sprintf("file://migrations/%s_sqlite", service), "sqlite", databaseInstance)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return db, nil
+		}
+
+		db.Close()
+		return nil, fmt.Errorf("failed to create migrations: %w", err)
+	}
+
+	if err := m.Up(); err != nil {
+		if !errors.Is(err, migrate.ErrNoChange) {
+			db.Close()
+			return nil, fmt.Errorf("failed to run migrations: %w", err)
+		}
+	}
+
+	return db, nil
+}
+
+// IsConstraintViolation reports whether err was caused by a primary key or unique constraint.
+func IsConstraintViolation(err error) bool {
+	var sqliteErr *sqlitedriver.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/cybre/home-inventory"
+
+// Setup installs the W3C trace context propagator and, if OTEL_EXPORTER_OTLP_ENDPOINT is set, a tracer provider
+// exporting the spans of the service over OTLP/HTTP. The exporter is configured by the standard OTEL_EXPORTER_OTLP_*
+// variables. Without an endpoint no spans are recorded, but incoming trace context is still passed on.
+// The returned function flushes the remaining spans and stops the exporter.
+func Setup(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	tracerProvider, err := NewTracerProvider(ctx, serviceName, sdktrace.WithBatcher(exporter))
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetTracerProvider(tracerProvider)
+
+	return tracerProvider.Shutdown, nil
+}
+
+// NewTracerProvider creates a tracer provider describing the service, which processes spans as configured by the options.
+func NewTracerProvider(ctx context.Context, serviceName string, options ...sdktrace.TracerProviderOption) (*sdktrace.TracerProvider, error) {
+	serviceResource, err := resource.New(
+		ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(append(options, sdktrace.WithResource(serviceResource))...), nil
+}
+
+// Tracer returns the tracer of the module. It follows the global tracer provider, so it can be used before Setup.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// End records the error, if any, on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
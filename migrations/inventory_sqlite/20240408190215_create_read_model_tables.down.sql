@@ -0,0 +1,8 @@
+DROP TABLE household_activity;
+DROP TABLE product_catalog;
+DROP TABLE label_codes;
+DROP TABLE user_tags;
+DROP TABLE user_category_fields;
+DROP TABLE user_categories;
+DROP TABLE user_household_rooms;
+DROP TABLE user_households;
@@ -0,0 +1,82 @@
+CREATE TABLE user_households (
+  user_id TEXT NOT NULL,
+  household_id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  location TEXT NOT NULL,
+  description TEXT NOT NULL,
+  tstamp INTEGER NOT NULL,
+  sort_order INTEGER NOT NULL,
+  PRIMARY KEY (user_id, household_id)
+);
+
+CREATE TABLE user_household_rooms (
+  user_id TEXT NOT NULL,
+  household_id TEXT NOT NULL,
+  room_id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  tstamp INTEGER NOT NULL,
+  sort_order INTEGER NOT NULL,
+  PRIMARY KEY (user_id, household_id, room_id)
+);
+
+CREATE TABLE user_categories (
+  user_id TEXT NOT NULL,
+  category_id TEXT NOT NULL,
+  parent_id TEXT,
+  name TEXT NOT NULL,
+  tstamp INTEGER NOT NULL,
+  PRIMARY KEY (user_id, category_id)
+);
+
+CREATE TABLE user_category_fields (
+  user_id TEXT NOT NULL,
+  category_id TEXT NOT NULL,
+  field_id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  field_type TEXT NOT NULL,
+  options TEXT NOT NULL,
+  required INTEGER NOT NULL,
+  tstamp INTEGER NOT NULL,
+  PRIMARY KEY (user_id, category_id, field_id)
+);
+
+CREATE TABLE user_tags (
+  user_id TEXT NOT NULL,
+  tag_id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  tstamp INTEGER NOT NULL,
+  PRIMARY KEY (user_id, tag_id)
+);
+
+CREATE TABLE label_codes (
+  code TEXT PRIMARY KEY,
+  user_id TEXT NOT NULL,
+  entity_type TEXT NOT NULL,
+  household_id TEXT NOT NULL,
+  room_id TEXT
+);
+
+CREATE INDEX label_codes_user_id ON label_codes (user_id);
+
+CREATE TABLE product_catalog (
+  code TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  brand TEXT NOT NULL,
+  category TEXT NOT NULL,
+  source TEXT NOT NULL
+);
+
+CREATE TABLE household_activity (
+  household_id TEXT NOT NULL,
+  activity_id TEXT NOT NULL,
+  activity_time INTEGER NOT NULL,
+  actor_id TEXT NOT NULL,
+  action TEXT NOT NULL,
+  subject_type TEXT NOT NULL,
+  subject_id TEXT NOT NULL,
+  subject_name TEXT NOT NULL,
+  tstamp INTEGER NOT NULL,
+  PRIMARY KEY (household_id, activity_id)
+);
+
+CREATE INDEX household_activity_time ON household_activity (household_id, activity_time DESC, activity_id DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE user_tags DROP COLUMN version;
+ALTER TABLE user_categories DROP COLUMN version;
+ALTER TABLE user_households DROP COLUMN version;
@@ -0,0 +1,3 @@
+ALTER TABLE user_households ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE user_categories ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE user_tags ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
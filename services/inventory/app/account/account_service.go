@@ -0,0 +1,208 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bnkamalesh/errors"
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/infrastructure"
+	appactivity "github.com/cybre/home-inventory/services/inventory/app/activity"
+	"github.com/cybre/home-inventory/services/inventory/app/common"
+	apphousehold "github.com/cybre/home-inventory/services/inventory/app/household"
+	apptaxonomy "github.com/cybre/home-inventory/services/inventory/app/taxonomy"
+	"github.com/cybre/home-inventory/services/inventory/domain/household"
+	"github.com/cybre/home-inventory/services/inventory/domain/taxonomy"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+)
+
+type UserHouseholdRepo interface {
+	GetUserHouseholds(ctx context.Context, userID string) ([]apphousehold.UserHouseholdModel, error)
+	DeleteUserHouseholds(ctx context.Context, userID string) error
+}
+
+type UserTaxonomyRepo interface {
+	GetUserCategories(ctx context.Context, userID string) ([]apptaxonomy.UserCategoryModel, error)
+	GetUserCategoryFields(ctx context.Context, userID string) ([]apptaxonomy.UserCategoryFieldModel, error)
+	GetUserTags(ctx context.Context, userID string) ([]apptaxonomy.UserTagModel, error)
+	DeleteUserTaxonomy(ctx context.Context, userID string) error
+}
+
+type LabelCodeRepo interface {
+	GetUserLabelCodes(ctx context.Context, userID string) ([]string, error)
+	DeleteUserLabelCodes(ctx context.Context, userID string) error
+}
+
+type HouseholdActivityRepo interface {
+	GetHouseholdActivity(ctx context.Context, householdID string, before string, limit int) ([]appactivity.HouseholdActivityModel, error)
+	DeleteHouseholdActivity(ctx context.Context, householdID string) error
+}
+
+type PersonalDataKeyStore interface {
+	HasUserKey(ctx context.Context, userID string) (bool, error)
+	DeleteUserKey(ctx context.Context, userID string) (bool, error)
+}
+
+type EventStore interface {
+	GetEvents(ctx context.Context, aggregateType es.AggregateType, aggregateID es.AggregateID) ([]es.Event, error)
+}
+
+type AccountService struct {
+	commandBus         common.CommandBus
+	repository         UserHouseholdRepo
+	taxonomyRepository UserTaxonomyRepo
+	labelRepository    LabelCodeRepo
+	activityRepository HouseholdActivityRepo
+	keyStore           PersonalDataKeyStore
+	eventStore         EventStore
+}
+
+func NewAccountService(commandBus common.CommandBus, repository UserHouseholdRepo, taxonomyRepository UserTaxonomyRepo, labelRepository LabelCodeRepo, activityRepository HouseholdActivityRepo, keyStore PersonalDataKeyStore, eventStore EventStore) *AccountService {
+	return &AccountService{
+		commandBus:         commandBus,
+		repository:         repository,
+		taxonomyRepository: taxonomyRepository,
+		labelRepository:    labelRepository,
+		activityRepository: activityRepository,
+		keyStore:           keyStore,
+		eventStore:         eventStore,
+	}
+}
+
+// ForgetUser erases all personal data of a user. Households and the taxonomy are deleted, the read model rows are removed and
+// the user's encryption key is destroyed, which leaves the personal fields in the event history unreadable.
+// The returned report lists what was erased along with checks verifying that nothing can be read back.
+func (s AccountService) ForgetUser(ctx context.Context, data shared.ForgetUserCommandData) (shared.ErasureReport, error) {
+	report := shared.ErasureReport{
+		UserID:       data.UserID,
+		RequestedAt:  time.Now().UnixMilli(),
+		HouseholdIDs: []string{},
+	}
+
+	households, err := s.repository.GetUserHouseholds(ctx, data.UserID)
+	if err != nil {
+		return shared.ErasureReport{}, errors.InternalErr(err, "failed to get user households")
+	}
+
+	for _, userHousehold := range households {
+		if err := s.commandBus.Dispatch(ctx, household.DeleteHouseholdCommand{
+			HouseholdID: userHousehold.HouseholdID.String(),
+			UserID:      data.UserID,
+		}); err != nil && !errors.HasType(err, errors.TypeNotFound) {
+			return shared.ErasureReport{}, errors.InternalErrf(err, "failed to delete household %s", userHousehold.HouseholdID)
+		}
+
+		report.HouseholdIDs = append(report.HouseholdIDs, userHousehold.HouseholdID.String())
+	}
+
+	if err := s.repository.DeleteUserHouseholds(ctx, data.UserID); err != nil {
+		return shared.ErasureReport{}, errors.InternalErr(err, "failed to delete user households")
+	}
+
+	for _, householdID := range report.HouseholdIDs {
+		if err := s.activityRepository.DeleteHouseholdActivity(ctx, householdID); err != nil {
+			return shared.ErasureReport{}, errors.InternalErrf(err, "failed to delete activity of household %s", householdID)
+		}
+	}
+
+	if err := s.commandBus.Dispatch(ctx, taxonomy.ClearTaxonomyCommand{UserID: data.UserID}); err != nil {
+		return shared.ErasureReport{}, errors.InternalErr(err, "failed to clear user taxonomy")
+	}
+
+	if err := s.taxonomyRepository.DeleteUserTaxonomy(ctx, data.UserID); err != nil {
+		return shared.ErasureReport{}, errors.InternalErr(err, "failed to delete user taxonomy")
+	}
+
+	if err := s.labelRepository.DeleteUserLabelCodes(ctx, data.UserID); err != nil {
+		return shared.ErasureReport{}, errors.InternalErr(err, "failed to delete user label codes")
+	}
+
+	report.EncryptionKeyDestroyed, err = s.keyStore.DeleteUserKey(ctx, data.UserID)
+	if err != nil {
+		return shared.ErasureReport{}, errors.InternalErr(err, "failed to destroy personal data key")
+	}
+
+	report.Checks = s.verify(ctx, data.UserID, report.HouseholdIDs)
+	report.Verified = true
+	for _, check := range report.Checks {
+		report.Verified = report.Verified && check.Passed
+	}
+
+	report.CompletedAt = time.Now().UnixMilli()
+
+	return report, nil
+}
+
+func (s AccountService) verify(ctx context.Context, userID string, householdIDs []string) []shared.ErasureCheck {
+	checks := []shared.ErasureCheck{}
+
+	hasKey, err := s.keyStore.HasUserKey(ctx, userID)
+	checks = append(checks, newCheck("encryption key destroyed", err == nil && !hasKey, err))
+
+	households, err := s.repository.GetUserHouseholds(ctx, userID)
+	checks = append(checks, newCheck("household projections removed", err == nil && len(households) == 0, err))
+
+	categories, err := s.taxonomyRepository.GetUserCategories(ctx, userID)
+	checks = append(checks, newCheck("category projections removed", err == nil && len(categories) == 0, err))
+
+	fields, err := s.taxonomyRepository.GetUserCategoryFields(ctx, userID)
+	checks = append(checks, newCheck("category field projections removed", err == nil && len(fields) == 0, err))
+
+	tags, err := s.taxonomyRepository.GetUserTags(ctx, userID)
+	checks = append(checks, newCheck("tag projections removed", err == nil && len(tags) == 0, err))
+
+	labelCodes, err := s.labelRepository.GetUserLabelCodes(ctx, userID)
+	checks = append(checks, newCheck("label codes removed", err == nil && len(labelCodes) == 0, err))
+
+	for _, householdID := range householdIDs {
+		activity, err := s.activityRepository.GetHouseholdActivity(ctx, householdID, "", 1)
+		checks = append(checks, newCheck(fmt.Sprintf("household %s activity removed", householdID), err == nil && len(activity) == 0, err))
+	}
+
+	for _, householdID := range householdIDs {
+		checks = append(checks, s.verifyEventsUnreadable(ctx, fmt.Sprintf("household %s events unreadable", householdID), household.HouseholdAggregateType, es.AggregateID(householdID), userID))
+	}
+
+	checks = append(checks, s.verifyEventsUnreadable(ctx, "taxonomy events unreadable", taxonomy.TaxonomyAggregateType, es.AggregateID(taxonomy.UserTaxonomyID(userID)), userID))
+
+	return checks
+}
+
+func (s AccountService) verifyEventsUnreadable(ctx context.Context, name string, aggregateType es.AggregateType, aggregateID es.AggregateID, userID string) shared.ErasureCheck {
+	events, err := s.eventStore.GetEvents(ctx, aggregateType, aggregateID)
+	if err != nil {
+		return newCheck(name, false, err)
+	}
+
+	readable := 0
+	for _, event := range events {
+		eventData, err := json.Marshal(event.Data)
+		if err != nil || strings.Contains(string(eventData), userID) ||
+			infrastructure.HasReadablePersonalData(event.Data) || infrastructure.HasReadablePersonalData(event.Metadata) {
+			readable++
+		}
+	}
+
+	var detail error
+	if readable > 0 {
+		detail = fmt.Errorf("%d of %d events still contain personal data", readable, len(events))
+	}
+
+	return newCheck(name, readable == 0, detail)
+}
+
+func newCheck(name string, passed bool, err error) shared.ErasureCheck {
+	check := shared.ErasureCheck{
+		Name:   name,
+		Passed: passed,
+	}
+
+	if err != nil {
+		check.Detail = err.Error()
+	}
+
+	return check
+}
@@ -0,0 +1,77 @@
+package activity
+
+import (
+	"context"
+
+	"github.com/bnkamalesh/errors"
+	apphousehold "github.com/cybre/home-inventory/services/inventory/app/household"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+)
+
+type UserHouseholdRepo interface {
+	GetUserHousehold(ctx context.Context, userID, householdID string) (apphousehold.UserHouseholdModel, bool, error)
+}
+
+type HouseholdActivityRepo interface {
+	GetHouseholdActivity(ctx context.Context, householdID string, before string, limit int) ([]HouseholdActivityModel, error)
+}
+
+type ActivityService struct {
+	householdRepository UserHouseholdRepo
+	repository          HouseholdActivityRepo
+}
+
+func NewActivityService(householdRepository UserHouseholdRepo, repository HouseholdActivityRepo) *ActivityService {
+	return &ActivityService{
+		householdRepository: householdRepository,
+		repository:          repository,
+	}
+}
+
+// GetHouseholdActivity returns a page of the household's feed. The cursor is the ID of the last activity
+// of the previous page.
+func (s ActivityService) GetHouseholdActivity(ctx context.Context, data shared.HouseholdActivityQueryData) (shared.HouseholdActivityPage, error) {
+	_, found, err := s.householdRepository.GetUserHousehold(ctx, data.UserID, data.HouseholdID)
+	if err != nil {
+		return shared.HouseholdActivityPage{}, errors.InternalErr(err, "failed to get user household")
+	}
+
+	if !found {
+		return shared.HouseholdActivityPage{}, errors.NotFoundf("household with ID %s not found", data.HouseholdID)
+	}
+
+	limit := data.Limit
+	if limit == 0 {
+		limit = shared.DefaultActivityPageSize
+	}
+
+	// One extra row tells whether there is a next page
+	activities, err := s.repository.GetHouseholdActivity(ctx, data.HouseholdID, data.Cursor, limit+1)
+	if err != nil {
+		return shared.HouseholdActivityPage{}, errors.InternalErr(err, "failed to get household activity")
+	}
+
+	page := shared.HouseholdActivityPage{
+		Activities: make([]shared.HouseholdActivity, 0, limit),
+	}
+
+	if len(activities) > limit {
+		activities = activities[:limit]
+		page.NextCursor = activities[limit-1].ActivityID.String()
+	}
+
+	for _, activity := range activities {
+		page.Activities = append(page.Activities, shared.HouseholdActivity{
+			ActivityID:  activity.ActivityID.String(),
+			HouseholdID: activity.HouseholdID.String(),
+			ActorID:     activity.ActorID,
+			Action:      activity.Action,
+			SubjectType: activity.SubjectType,
+			SubjectID:   activity.SubjectID.String(),
+			SubjectName: activity.SubjectName,
+			Timestamp:   activity.Timestamp,
+		})
+	}
+
+	return page, nil
+}
@@ -0,0 +1,126 @@
+package activity
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/services/inventory/domain/household"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/gocql/gocql"
+)
+
+type ActivityRepo interface {
+	InsertActivity(ctx context.Context, model HouseholdActivityModel) error
+	DeleteHouseholdActivity(ctx context.Context, householdId string) error
+}
+
+type HouseholdActivityProjector struct {
+	repository ActivityRepo
+}
+
+func NewHouseholdActivityProjector(repository ActivityRepo) *HouseholdActivityProjector {
+	return &HouseholdActivityProjector{
+		repository: repository,
+	}
+}
+
+func (p HouseholdActivityProjector) HandleEvent(ctx context.Context, event es.Event) error {
+	switch e := event.Data.(type) {
+	case household.HouseholdCreatedEvent:
+		return p.record(ctx, e, e.HouseholdID, e.UserID, shared.ActivityHouseholdCreated, shared.ActivitySubjectHousehold, e.HouseholdID, e.Name, e.Timestamp)
+	case household.HouseholdUpdatedEvent:
+		return p.record(ctx, e, e.HouseholdID, e.UserID, shared.ActivityHouseholdUpdated, shared.ActivitySubjectHousehold, e.HouseholdID, e.Name, e.Timestamp)
+	case household.HouseholdDeletedEvent:
+		return p.handleHouseholdDeletedEvent(ctx, e)
+	case household.RoomAddedEvent:
+		return p.record(ctx, e, e.HouseholdID, e.UserID, shared.ActivityRoomAdded, shared.ActivitySubjectRoom, e.RoomID, e.Name, e.Timestamp)
+	case household.RoomUpdatedEvent:
+		return p.record(ctx, e, e.HouseholdID, e.UserID, shared.ActivityRoomUpdated, shared.ActivitySubjectRoom, e.RoomID, e.Name, e.Timestamp)
+	case household.RoomDeletedEvent:
+		return p.record(ctx, e, e.HouseholdID, e.UserID, shared.ActivityRoomDeleted, shared.ActivitySubjectRoom, e.RoomID, e.Name, e.Timestamp)
+	default:
+		return es.ErrUnknownEvent
+	}
+}
+
+func (p HouseholdActivityProjector) Events() []es.EventType {
+	return []es.EventType{
+		household.EventTypeHouseholdCreated,
+		household.EventTypeHouseholdUpdated,
+		household.EventTypeHouseholdDeleted,
+		household.EventTypeRoomAdded,
+		household.EventTypeRoomUpdated,
+		household.EventTypeRoomDeleted,
+	}
+}
+
+func (p HouseholdActivityProjector) Name() string {
+	return "activity.HouseholdActivityProjector"
+}
+
+// The feed belongs to the household, so it goes away together with it.
+func (p HouseholdActivityProjector) handleHouseholdDeletedEvent(ctx context.Context, e household.HouseholdDeletedEvent) error {
+	if err := p.repository.DeleteHouseholdActivity(ctx, e.HouseholdID); err != nil {
+		return fmt.Errorf("failed to delete household activity: %w", err)
+	}
+
+	return nil
+}
+
+func (p HouseholdActivityProjector) record(ctx context.Context, event es.EventData, householdID, actorID, action, subjectType, subjectID, subjectName string, timestamp int64) error {
+	householdUUID, err := gocql.ParseUUID(householdID)
+	if err != nil {
+		return fmt.Errorf("failed to parse household ID: %w", err)
+	}
+
+	subjectUUID, err := gocql.ParseUUID(subjectID)
+	if err != nil {
+		return fmt.Errorf("failed to parse subject ID: %w", err)
+	}
+
+	// Events recorded before they carried a timestamp fall back to the time they are projected
+	if timestamp == 0 {
+		timestamp = time.Now().UnixMilli()
+	}
+
+	activityID, err := activityID(event, timestamp)
+	if err != nil {
+		return err
+	}
+
+	if err := p.repository.InsertActivity(ctx, HouseholdActivityModel{
+		HouseholdID: householdUUID,
+		ActivityID:  activityID,
+		ActorID:     actorID,
+		Action:      action,
+		SubjectType: subjectType,
+		SubjectID:   subjectUUID,
+		SubjectName: subjectName,
+		Timestamp:   timestamp,
+	}); err != nil {
+		return fmt.Errorf("failed to insert household activity: %w", err)
+	}
+
+	return nil
+}
+
+// activityID returns a time based UUID for the event. The non-time bits are derived from the event itself,
+// so an event delivered more than once overwrites its own feed entry instead of adding a duplicate.
+func activityID(event es.EventData, timestamp int64) (gocql.UUID, error) {
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return gocql.UUID{}, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	sum := sha1.Sum(append([]byte(event.EventType()), eventData...))
+
+	id := gocql.UUIDFromTime(time.UnixMilli(timestamp))
+	copy(id[8:], sum[:8])
+	id[8] = id[8]&0x3f | 0x80
+
+	return id, nil
+}
@@ -0,0 +1,63 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+type HouseholdActivityRepository struct {
+	db *gocql.Session
+}
+
+func NewHouseholdActivityRepository(db *gocql.Session) *HouseholdActivityRepository {
+	return &HouseholdActivityRepository{db: db}
+}
+
+func (r HouseholdActivityRepository) InsertActivity(ctx context.Context, model HouseholdActivityModel) error {
+	return r.db.Query("INSERT INTO household_activity (household_id, activity_id, actor_id, action, subject_type, subject_id, subject_name, tstamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", model.HouseholdID, model.ActivityID, model.ActorID, model.Action, model.SubjectType, model.SubjectID, model.SubjectName, model.Timestamp).WithContext(ctx).Exec()
+}
+
+// GetHouseholdActivity returns up to limit activities of a household, newest first. When before is set,
+// only activities older than it are returned.
+func (r HouseholdActivityRepository) GetHouseholdActivity(ctx context.Context, householdId string, before string, limit int) ([]HouseholdActivityModel, error) {
+	householdUUID, err := gocql.ParseUUID(householdId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid household ID: %s", householdId)
+	}
+
+	query := r.db.Query("SELECT activity_id, actor_id, action, subject_type, subject_id, subject_name, tstamp FROM household_activity WHERE household_id = ? LIMIT ?", householdUUID, limit)
+	if before != "" {
+		beforeUUID, err := gocql.ParseUUID(before)
+		if err != nil {
+			return nil, fmt.Errorf("invalid activity cursor: %s", before)
+		}
+
+		query = r.db.Query("SELECT activity_id, actor_id, action, subject_type, subject_id, subject_name, tstamp FROM household_activity WHERE household_id = ? AND activity_id < ? LIMIT ?", householdUUID, beforeUUID, limit)
+	}
+
+	iter := query.WithContext(ctx).Iter()
+	defer iter.Close()
+
+	activities := make([]HouseholdActivityModel, 0, limit)
+	model := HouseholdActivityModel{HouseholdID: householdUUID}
+	for iter.Scan(&model.ActivityID, &model.ActorID, &model.Action, &model.SubjectType, &model.SubjectID, &model.SubjectName, &model.Timestamp) {
+		activities = append(activities, model)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get household activity: %w", err)
+	}
+
+	return activities, nil
+}
+
+func (r HouseholdActivityRepository) DeleteHouseholdActivity(ctx context.Context, householdId string) error {
+	householdUUID, err := gocql.ParseUUID(householdId)
+	if err != nil {
+		return fmt.Errorf("invalid household ID: %s", householdId)
+	}
+
+	return r.db.Query("DELETE FROM household_activity WHERE household_id = ?", householdUUID).WithContext(ctx).Exec()
+}
@@ -0,0 +1,14 @@
+package activity
+
+import "github.com/gocql/gocql"
+
+type HouseholdActivityModel struct {
+	HouseholdID gocql.UUID
+	ActivityID  gocql.UUID
+	ActorID     string
+	Action      string
+	SubjectType string
+	SubjectID   gocql.UUID
+	SubjectName string
+	Timestamp   int64
+}
@@ -0,0 +1,89 @@
+package activity
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+// SQLiteHouseholdActivityRepository stores household activity in SQLite for single-node deployments.
+// Activity IDs are time UUIDs, whose text form does not sort by time, so their time is stored alongside for ordering.
+type SQLiteHouseholdActivityRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteHouseholdActivityRepository(db *sql.DB) *SQLiteHouseholdActivityRepository {
+	return &SQLiteHouseholdActivityRepository{db: db}
+}
+
+func (r SQLiteHouseholdActivityRepository) InsertActivity(ctx context.Context, model HouseholdActivityModel) error {
+	_, err := r.db.ExecContext(ctx, "INSERT OR REPLACE INTO household_activity (household_id, activity_id, activity_time, actor_id, action, subject_type, subject_id, subject_name, tstamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", model.HouseholdID.String(), model.ActivityID.String(), model.ActivityID.Time().UnixNano(), model.ActorID, model.Action, model.SubjectType, model.SubjectID.String(), model.SubjectName, model.Timestamp)
+
+	return err
+}
+
+// GetHouseholdActivity returns up to limit activities of a household, newest first. When before is set,
+// only activities older than it are returned.
+func (r SQLiteHouseholdActivityRepository) GetHouseholdActivity(ctx context.Context, householdId string, before string, limit int) ([]HouseholdActivityModel, error) {
+	householdUUID, err := gocql.ParseUUID(householdId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid household ID: %s", householdId)
+	}
+
+	query := "SELECT activity_id, actor_id, action, subject_type, subject_id, subject_name, tstamp FROM household_activity WHERE household_id = ? ORDER BY activity_time DESC, activity_id DESC LIMIT ?"
+	args := []interface{}{householdUUID.String(), limit}
+	if before != "" {
+		beforeUUID, err := gocql.ParseUUID(before)
+		if err != nil {
+			return nil, fmt.Errorf("invalid activity cursor: %s", before)
+		}
+
+		beforeTime := beforeUUID.Time().UnixNano()
+		query = "SELECT activity_id, actor_id, action, subject_type, subject_id, subject_name, tstamp FROM household_activity WHERE household_id = ? AND (activity_time < ? OR (activity_time = ? AND activity_id < ?)) ORDER BY activity_time DESC, activity_id DESC LIMIT ?"
+		args = []interface{}{householdUUID.String(), beforeTime, beforeTime, beforeUUID.String(), limit}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get household activity: %w", err)
+	}
+	defer rows.Close()
+
+	activities := make([]HouseholdActivityModel, 0, limit)
+	for rows.Next() {
+		var activityId, subjectId string
+		model := HouseholdActivityModel{HouseholdID: householdUUID}
+		if err := rows.Scan(&activityId, &model.ActorID, &model.Action, &model.SubjectType, &subjectId, &model.SubjectName, &model.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan household activity: %w", err)
+		}
+
+		if model.ActivityID, err = gocql.ParseUUID(activityId); err != nil {
+			return nil, fmt.Errorf("invalid activity ID: %s", activityId)
+		}
+
+		if model.SubjectID, err = gocql.ParseUUID(subjectId); err != nil {
+			return nil, fmt.Errorf("invalid subject ID: %s", subjectId)
+		}
+
+		activities = append(activities, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get household activity: %w", err)
+	}
+
+	return activities, nil
+}
+
+func (r SQLiteHouseholdActivityRepository) DeleteHouseholdActivity(ctx context.Context, householdId string) error {
+	householdUUID, err := gocql.ParseUUID(householdId)
+	if err != nil {
+		return fmt.Errorf("invalid household ID: %s", householdId)
+	}
+
+	_, err = r.db.ExecContext(ctx, "DELETE FROM household_activity WHERE household_id = ?", householdUUID.String())
+
+	return err
+}
@@ -0,0 +1,62 @@
+package catalog
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bnkamalesh/errors"
+)
+
+// Product holds the details a barcode lookup can prefill.
+type Product struct {
+	Code     string
+	Name     string
+	Brand    string
+	Category string
+	Source   string
+}
+
+// ProductCatalog looks up products by their normalized UPC/EAN code.
+type ProductCatalog interface {
+	Lookup(ctx context.Context, code string) (Product, bool, error)
+}
+
+// NormalizeCode validates an EAN-8, UPC-A or EAN-13 code and returns it in its canonical form.
+// UPC-A codes are returned as their EAN-13 equivalent, so both spellings of a product find the same entry.
+func NormalizeCode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return "", errors.InputBodyf("invalid barcode %q: must only contain digits", code)
+		}
+	}
+
+	switch len(code) {
+	case 8, 13:
+	case 12:
+		code = "0" + code
+	default:
+		return "", errors.InputBodyf("invalid barcode %q: must be an EAN-8, UPC-A or EAN-13 code", code)
+	}
+
+	if !validCheckDigit(code) {
+		return "", errors.InputBodyf("invalid barcode %q: check digit does not match", code)
+	}
+
+	return code, nil
+}
+
+// validCheckDigit verifies the GS1 check digit, weighting digits 3 and 1 alternately from the right.
+func validCheckDigit(code string) bool {
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		digit := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return (10-sum%10)%10 == int(code[len(code)-1]-'0')
+}
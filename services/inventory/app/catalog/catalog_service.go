@@ -0,0 +1,92 @@
+package catalog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bnkamalesh/errors"
+	"github.com/cybre/home-inventory/internal/logging"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+)
+
+type LocalProductCatalog interface {
+	ProductCatalog
+	UpsertProduct(ctx context.Context, product Product) error
+}
+
+type CatalogService struct {
+	local  LocalProductCatalog
+	remote ProductCatalog
+}
+
+// NewCatalogService creates a service looking products up in the local catalog first. The remote catalog is
+// optional; products found there are saved locally, so every code only has to be fetched once, and failures
+// to reach it are logged and reported as the product not being found.
+func NewCatalogService(local LocalProductCatalog, remote ProductCatalog) *CatalogService {
+	return &CatalogService{
+		local:  local,
+		remote: remote,
+	}
+}
+
+func (s CatalogService) LookupProduct(ctx context.Context, code string) (shared.CatalogProduct, error) {
+	code, err := NormalizeCode(code)
+	if err != nil {
+		return shared.CatalogProduct{}, err
+	}
+
+	product, found, err := s.local.Lookup(ctx, code)
+	if err != nil {
+		return shared.CatalogProduct{}, errors.InternalErr(err, "failed to look up product")
+	}
+
+	if !found && s.remote != nil {
+		product, found, err = s.remote.Lookup(ctx, code)
+		if err != nil {
+			// The remote catalog is only a convenience, so it being down or rate limited is treated as a miss
+			logging.FromContext(ctx).Warn("failed to look up product in remote catalog", slog.String("code", code), slog.Any("error", err))
+			found = false
+		}
+
+		if found {
+			if err := s.local.UpsertProduct(ctx, product); err != nil {
+				logging.FromContext(ctx).Warn("failed to save product to local catalog", slog.String("code", code), slog.Any("error", err))
+			}
+		}
+	}
+
+	if !found {
+		return shared.CatalogProduct{}, errors.NotFoundf("product with barcode %s not found", code)
+	}
+
+	return toSharedCatalogProduct(product), nil
+}
+
+func (s CatalogService) AddProduct(ctx context.Context, data shared.AddCatalogProductCommandData) error {
+	code, err := NormalizeCode(data.Code)
+	if err != nil {
+		return err
+	}
+
+	if err := s.local.UpsertProduct(ctx, Product{
+		Code:     code,
+		Name:     data.Name,
+		Brand:    data.Brand,
+		Category: data.Category,
+		Source:   localCatalogSource,
+	}); err != nil {
+		return errors.InternalErr(err, "failed to save product")
+	}
+
+	return nil
+}
+
+func toSharedCatalogProduct(product Product) shared.CatalogProduct {
+	return shared.CatalogProduct{
+		Code:     product.Code,
+		Name:     product.Name,
+		Brand:    product.Brand,
+		Category: product.Category,
+		Source:   product.Source,
+	}
+}
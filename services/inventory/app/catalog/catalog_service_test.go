@@ -0,0 +1,89 @@
+package catalog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bnkamalesh/errors"
+	"github.com/cybre/home-inventory/services/inventory/app/catalog"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticCatalog map[string]catalog.Product
+
+func (c staticCatalog) Lookup(ctx context.Context, code string) (catalog.Product, bool, error) {
+	product, ok := c[code]
+
+	return product, ok, nil
+}
+
+type memoryCatalog struct {
+	staticCatalog
+}
+
+func (c memoryCatalog) UpsertProduct(ctx context.Context, product catalog.Product) error {
+	c.staticCatalog[product.Code] = product
+	return nil
+}
+
+type failingCatalog struct{}
+
+func (failingCatalog) Lookup(ctx context.Context, code string) (catalog.Product, bool, error) {
+	return catalog.Product{}, false, errors.New("rate limited")
+}
+
+func Test_NormalizeCode(t *testing.T) {
+	code, err := catalog.NormalizeCode("036000291452")
+	assert.NoError(t, err)
+	assert.Equal(t, "0036000291452", code, "UPC-A codes should be normalized to EAN-13")
+
+	code, err = catalog.NormalizeCode(" 4006381333931 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "4006381333931", code)
+
+	code, err = catalog.NormalizeCode("96385074")
+	assert.NoError(t, err)
+	assert.Equal(t, "96385074", code)
+
+	_, err = catalog.NormalizeCode("4006381333932")
+	assert.Error(t, err, "wrong check digit should be rejected")
+
+	_, err = catalog.NormalizeCode("40063813339")
+	assert.Error(t, err, "unsupported length should be rejected")
+}
+
+func Test_CatalogService_LookupProduct(t *testing.T) {
+	ctx := context.Background()
+
+	local := memoryCatalog{staticCatalog{
+		"4006381333931": {Code: "4006381333931", Name: "Textmarker", Brand: "Stabilo", Category: "Office", Source: "local"},
+	}}
+	remote := staticCatalog{
+		"0036000291452": {Code: "0036000291452", Name: "Tissues", Brand: "Kleenex", Category: "Household", Source: "remote"},
+	}
+	service := catalog.NewCatalogService(local, remote)
+
+	product, err := service.LookupProduct(ctx, "4006381333931")
+	assert.NoError(t, err)
+	assert.Equal(t, shared.CatalogProduct{Code: "4006381333931", Name: "Textmarker", Brand: "Stabilo", Category: "Office", Source: "local"}, product)
+
+	product, err = service.LookupProduct(ctx, "036000291452")
+	assert.NoError(t, err)
+	assert.Equal(t, "Tissues", product.Name)
+	_, savedLocally, _ := local.Lookup(ctx, "0036000291452")
+	assert.True(t, savedLocally, "products found remotely should be saved to the local catalog")
+
+	_, err = service.LookupProduct(ctx, "96385074")
+	assert.True(t, errors.HasType(err, errors.TypeNotFound))
+
+	_, err = catalog.NewCatalogService(local, nil).LookupProduct(ctx, "96385074")
+	assert.True(t, errors.HasType(err, errors.TypeNotFound), "lookups should work without a remote catalog")
+
+	_, err = catalog.NewCatalogService(local, failingCatalog{}).LookupProduct(ctx, "96385074")
+	assert.True(t, errors.HasType(err, errors.TypeNotFound), "a failing remote catalog should count as a miss")
+
+	product, err = catalog.NewCatalogService(local, failingCatalog{}).LookupProduct(ctx, "4006381333931")
+	assert.NoError(t, err)
+	assert.Equal(t, "Textmarker", product.Name)
+}
@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cybre/home-inventory/internal/requestbuilder"
+)
+
+const httpCatalogSource = "upcitemdb"
+
+// HTTPProductCatalog looks products up in an UPCitemdb compatible web service.
+type HTTPProductCatalog struct {
+	address string
+}
+
+func NewHTTPProductCatalog(address string) *HTTPProductCatalog {
+	return &HTTPProductCatalog{
+		address: address,
+	}
+}
+
+type upcItemDBResponse struct {
+	Code  string `json:"code"`
+	Items []struct {
+		Title    string `json:"title"`
+		Brand    string `json:"brand"`
+		Category string `json:"category"`
+	} `json:"items"`
+}
+
+func (c HTTPProductCatalog) Lookup(ctx context.Context, code string) (Product, bool, error) {
+	resp, err := requestbuilder.New(http.MethodGet, c.address).
+		WithQueryParam("upc", code).
+		WithHeader("Accept", "application/json").
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return Product{}, false, fmt.Errorf("failed to look up product: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Product{}, false, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Product{}, false, fmt.Errorf("failed to look up product: unexpected status %d", resp.StatusCode)
+	}
+
+	var body upcItemDBResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Product{}, false, fmt.Errorf("failed to decode product lookup response: %w", err)
+	}
+
+	if len(body.Items) == 0 {
+		return Product{}, false, nil
+	}
+
+	return Product{
+		Code:     code,
+		Name:     body.Items[0].Title,
+		Brand:    body.Items[0].Brand,
+		Category: body.Items[0].Category,
+		Source:   httpCatalogSource,
+	}, true, nil
+}
@@ -0,0 +1,88 @@
+package catalog
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gocql/gocql"
+)
+
+const localCatalogSource = "local"
+
+// CassandraProductCatalog is the local product database. It can be seeded from a CSV file and grows
+// with products added through the API or found by a remote catalog.
+type CassandraProductCatalog struct {
+	db *gocql.Session
+}
+
+func NewCassandraProductCatalog(db *gocql.Session) *CassandraProductCatalog {
+	return &CassandraProductCatalog{db: db}
+}
+
+func (c CassandraProductCatalog) Lookup(ctx context.Context, code string) (Product, bool, error) {
+	product := Product{Code: code}
+	if err := c.db.Query("SELECT name, brand, category, source FROM product_catalog WHERE code = ?", code).WithContext(ctx).Scan(&product.Name, &product.Brand, &product.Category, &product.Source); err != nil {
+		if err == gocql.ErrNotFound {
+			return Product{}, false, nil
+		}
+
+		return Product{}, false, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	return product, true, nil
+}
+
+func (c CassandraProductCatalog) UpsertProduct(ctx context.Context, product Product) error {
+	return c.db.Query("INSERT INTO product_catalog (code, name, brand, category, source) VALUES (?, ?, ?, ?, ?)", product.Code, product.Name, product.Brand, product.Category, product.Source).WithContext(ctx).Exec()
+}
+
+// Seed loads products from CSV rows of code, name, brand and category into the catalog.
+func (c CassandraProductCatalog) Seed(ctx context.Context, r io.Reader) (loaded int, skipped int, err error) {
+	return seed(ctx, c, r)
+}
+
+// seed loads products from CSV rows of code, name, brand and category. The first row is treated as a header.
+// Rows with invalid codes are skipped and counted, so one bad line does not prevent the rest from loading.
+func seed(ctx context.Context, c LocalProductCatalog, r io.Reader) (loaded int, skipped int, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+
+	if _, err := reader.Read(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, 0, nil
+		}
+
+		return 0, 0, fmt.Errorf("failed to read product catalog header: %w", err)
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return loaded, skipped, nil
+		}
+		if err != nil {
+			return loaded, skipped, fmt.Errorf("failed to read product catalog: %w", err)
+		}
+
+		code, err := NormalizeCode(record[0])
+		if err != nil || record[1] == "" {
+			skipped++
+			continue
+		}
+
+		if err := c.UpsertProduct(ctx, Product{
+			Code:     code,
+			Name:     record[1],
+			Brand:    record[2],
+			Category: record[3],
+			Source:   localCatalogSource,
+		}); err != nil {
+			return loaded, skipped, fmt.Errorf("failed to seed product %s: %w", code, err)
+		}
+
+		loaded++
+	}
+}
@@ -0,0 +1,42 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SQLiteProductCatalog is the local product database for single-node deployments.
+type SQLiteProductCatalog struct {
+	db *sql.DB
+}
+
+func NewSQLiteProductCatalog(db *sql.DB) *SQLiteProductCatalog {
+	return &SQLiteProductCatalog{db: db}
+}
+
+func (c SQLiteProductCatalog) Lookup(ctx context.Context, code string) (Product, bool, error) {
+	product := Product{Code: code}
+	if err := c.db.QueryRowContext(ctx, "SELECT name, brand, category, source FROM product_catalog WHERE code = ?", code).Scan(&product.Name, &product.Brand, &product.Category, &product.Source); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Product{}, false, nil
+		}
+
+		return Product{}, false, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	return product, true, nil
+}
+
+func (c SQLiteProductCatalog) UpsertProduct(ctx context.Context, product Product) error {
+	_, err := c.db.ExecContext(ctx, "INSERT OR REPLACE INTO product_catalog (code, name, brand, category, source) VALUES (?, ?, ?, ?, ?)", product.Code, product.Name, product.Brand, product.Category, product.Source)
+
+	return err
+}
+
+// Seed loads products from CSV rows of code, name, brand and category into the catalog.
+func (c SQLiteProductCatalog) Seed(ctx context.Context, r io.Reader) (loaded int, skipped int, err error) {
+	return seed(ctx, c, r)
+}
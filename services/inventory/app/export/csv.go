@@ -0,0 +1,71 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/cybre/home-inventory/services/inventory/shared"
+)
+
+const (
+	householdsCSVFileName = "households.csv"
+	roomsCSVFileName      = "rooms.csv"
+)
+
+// WriteCSV writes a zip archive containing one CSV file per entity.
+func WriteCSV(w io.Writer, archive shared.InventoryArchive) error {
+	zw := zip.NewWriter(w)
+
+	households := [][]string{{"household_id", "name", "location", "description", "order", "timestamp"}}
+	rooms := [][]string{{"household_id", "room_id", "name", "order", "timestamp"}}
+	for _, household := range archive.Households {
+		households = append(households, []string{
+			household.HouseholdID,
+			household.Name,
+			household.Location,
+			household.Description,
+			strconv.FormatUint(uint64(household.Order), 10),
+			strconv.FormatInt(household.Timestamp, 10),
+		})
+
+		for _, room := range household.Rooms {
+			rooms = append(rooms, []string{
+				household.HouseholdID,
+				room.RoomID,
+				room.Name,
+				strconv.FormatUint(uint64(room.Order), 10),
+				strconv.FormatInt(room.Timestamp, 10),
+			})
+		}
+	}
+
+	if err := writeCSVFile(zw, householdsCSVFileName, households); err != nil {
+		return err
+	}
+
+	if err := writeCSVFile(zw, roomsCSVFileName, rooms); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to close zip archive: %w", err)
+	}
+
+	return nil
+}
+
+func writeCSVFile(zw *zip.Writer, name string, records [][]string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+
+	if err := csv.NewWriter(f).WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
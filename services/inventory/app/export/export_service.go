@@ -0,0 +1,199 @@
+package export
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bnkamalesh/errors"
+	"github.com/cybre/home-inventory/services/inventory/app/common"
+	apphousehold "github.com/cybre/home-inventory/services/inventory/app/household"
+	"github.com/cybre/home-inventory/services/inventory/domain/household"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/google/uuid"
+)
+
+type UserHouseholdRepo interface {
+	GetUserHouseholds(ctx context.Context, userID string) ([]apphousehold.UserHouseholdModel, error)
+}
+
+type ExportService struct {
+	commandBus common.CommandBus
+	repository UserHouseholdRepo
+}
+
+func NewExportService(commandBus common.CommandBus, repository UserHouseholdRepo) *ExportService {
+	return &ExportService{
+		commandBus: commandBus,
+		repository: repository,
+	}
+}
+
+func (s ExportService) Export(ctx context.Context, userID string) (shared.InventoryArchive, error) {
+	households, err := s.repository.GetUserHouseholds(ctx, userID)
+	if err != nil {
+		return shared.InventoryArchive{}, errors.InternalErr(err, "failed to get user households")
+	}
+
+	return shared.InventoryArchive{
+		Version:    shared.InventoryArchiveVersion,
+		UserID:     userID,
+		ExportedAt: time.Now().UnixMilli(),
+		Households: apphousehold.ToSharedUserHouseholds(households),
+	}, nil
+}
+
+func (s ExportService) ExportCSV(ctx context.Context, userID string, w io.Writer) error {
+	archive, err := s.Export(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return WriteCSV(w, archive)
+}
+
+func (s ExportService) ExportPDF(ctx context.Context, userID string, w io.Writer) error {
+	archive, err := s.Export(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return WritePDF(w, archive)
+}
+
+// importNamespace scopes the name based IDs of imported households and rooms
+var importNamespace = uuid.MustParse("9a3f6c52-0d1e-4b7a-8f25-6e4c1b0d7a93")
+
+// Import restores households and rooms from an archive produced by Export.
+// Archived households the user no longer has are created under IDs derived from the user and the archived ID, since
+// the household aggregate rejects IDs that were used before, for instance by another account. Rooms missing from a
+// household are added the same way. Derived IDs make a retry resume where a failed import stopped: entities created
+// by the earlier attempt are found again and skipped instead of being created twice.
+func (s ExportService) Import(ctx context.Context, data shared.ImportInventoryCommandData) error {
+	if err := validateArchive(data.Households); err != nil {
+		return err
+	}
+
+	existing, err := s.repository.GetUserHouseholds(ctx, data.UserID)
+	if err != nil {
+		return errors.InternalErr(err, "failed to get user households")
+	}
+
+	existingByID := make(map[string]apphousehold.UserHouseholdModel, len(existing))
+	for _, household := range existing {
+		existingByID[household.HouseholdID.String()] = household
+	}
+
+	targetIDs := make([]string, len(data.Households))
+	for i, archived := range data.Households {
+		targetIDs[i] = importedID(data.UserID, archived.HouseholdID)
+		if _, ok := existingByID[archived.HouseholdID]; ok {
+			targetIDs[i] = archived.HouseholdID
+		}
+
+		if slices.ContainsFunc(existing, func(h apphousehold.UserHouseholdModel) bool {
+			return h.Name == archived.Name && h.HouseholdID.String() != targetIDs[i]
+		}) {
+			return errors.Duplicatef("household with name %s already exists", archived.Name)
+		}
+
+		target := existingByID[targetIDs[i]]
+		for _, room := range archived.Rooms {
+			roomID := importedID(data.UserID, archived.HouseholdID, room.RoomID)
+			if slices.ContainsFunc(target.Rooms, func(r apphousehold.UserHouseholdRoomModel) bool {
+				return r.Name == room.Name && r.RoomID.String() != room.RoomID && r.RoomID.String() != roomID
+			}) {
+				return errors.Duplicatef("room with name %s already exists in household %s", room.Name, archived.Name)
+			}
+		}
+	}
+
+	order := uint(len(existing))
+	for i, archived := range data.Households {
+		householdID := targetIDs[i]
+		target, ok := existingByID[householdID]
+		if !ok {
+			order++
+			if err := s.commandBus.Dispatch(ctx, household.CreateHouseholdCommand{
+				HouseholdID: householdID,
+				UserID:      data.UserID,
+				Name:        archived.Name,
+				Location:    archived.Location,
+				Description: archived.Description,
+				Order:       order,
+			}); err != nil && !errors.HasType(err, errors.TypeDuplicate) {
+				// A duplicate means an earlier attempt created the household, but it isn't projected yet
+				return err
+			}
+		}
+
+		rooms := slices.Clone(archived.Rooms)
+		slices.SortStableFunc(rooms, func(a, b shared.UserHouseholdRoom) int {
+			return int(a.Order) - int(b.Order)
+		})
+
+		for _, room := range rooms {
+			roomID := importedID(data.UserID, archived.HouseholdID, room.RoomID)
+			if slices.ContainsFunc(target.Rooms, func(r apphousehold.UserHouseholdRoomModel) bool {
+				return r.RoomID.String() == room.RoomID || r.RoomID.String() == roomID
+			}) {
+				continue
+			}
+
+			if err := s.commandBus.Dispatch(ctx, household.AddRoomCommand{
+				HouseholdID: householdID,
+				UserID:      data.UserID,
+				RoomID:      roomID,
+				Name:        room.Name,
+			}); err != nil && !errors.HasType(err, errors.TypeDuplicate) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateArchive rejects archives that can't be imported completely, before anything is dispatched
+func validateArchive(households []shared.UserHousehold) error {
+	householdNames := make(map[string]struct{}, len(households))
+	householdIDs := make(map[string]struct{}, len(households))
+	for _, household := range households {
+		if _, ok := householdNames[household.Name]; ok {
+			return errors.Validationf("archive contains more than one household named %s", household.Name)
+		}
+		householdNames[household.Name] = struct{}{}
+
+		if _, ok := householdIDs[household.HouseholdID]; ok {
+			return errors.Validationf("archive contains household %s more than once", household.HouseholdID)
+		}
+		householdIDs[household.HouseholdID] = struct{}{}
+
+		roomNames := make(map[string]struct{}, len(household.Rooms))
+		roomIDs := make(map[string]struct{}, len(household.Rooms))
+		for _, room := range household.Rooms {
+			if _, ok := roomNames[room.Name]; ok {
+				return errors.Validationf("archive contains more than one room named %s in household %s", room.Name, household.Name)
+			}
+			roomNames[room.Name] = struct{}{}
+
+			if _, ok := roomIDs[room.RoomID]; ok {
+				return errors.Validationf("archive contains room %s more than once", room.RoomID)
+			}
+			roomIDs[room.RoomID] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// importedID derives the ID an archived entity gets when it's imported for the user.
+// Commands only accept version 4 UUIDs, so the name based UUID is stamped with that version.
+func importedID(userID string, archivedIDs ...string) string {
+	id := uuid.NewSHA1(importNamespace, []byte(strings.Join(append([]string{userID}, archivedIDs...), "/")))
+	id[6] = (id[6] & 0x0f) | 0x40
+
+	return id.String()
+}
@@ -0,0 +1,243 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"slices"
+	"testing"
+
+	"github.com/bnkamalesh/errors"
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/services/inventory/app/export"
+	apphousehold "github.com/cybre/home-inventory/services/inventory/app/household"
+	"github.com/cybre/home-inventory/services/inventory/domain/household"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inventory applies household commands the way the household aggregate does and serves them as the projection
+type inventory struct {
+	households []apphousehold.UserHouseholdModel
+	dispatched int
+	// failAt fails the dispatch with this number, counting from one
+	failAt int
+	// stale hides everything from the projection, as if it hadn't caught up yet
+	stale bool
+}
+
+func (inv *inventory) Dispatch(ctx context.Context, command es.Command) error {
+	inv.dispatched++
+	if inv.dispatched == inv.failAt {
+		return errors.New("command bus unavailable")
+	}
+
+	switch c := command.(type) {
+	case household.CreateHouseholdCommand:
+		if inv.find(c.HouseholdID) != nil {
+			return errors.Duplicate("household with provided ID already exists")
+		}
+
+		inv.households = append(inv.households, apphousehold.UserHouseholdModel{
+			UserID:      c.UserID,
+			HouseholdID: mustUUID(c.HouseholdID),
+			Name:        c.Name,
+			Location:    c.Location,
+			Description: c.Description,
+			Order:       c.Order,
+		})
+	case household.AddRoomCommand:
+		h := inv.find(c.HouseholdID)
+		if h == nil {
+			return errors.NotFound("household with provided ID does not exist")
+		}
+
+		for _, room := range h.Rooms {
+			if room.RoomID.String() == c.RoomID {
+				return errors.Duplicatef("room with ID %s already exists", c.RoomID)
+			}
+			if room.Name == c.Name {
+				return errors.Duplicatef("room with name %s already exists", c.Name)
+			}
+		}
+
+		h.Rooms = append(h.Rooms, apphousehold.UserHouseholdRoomModel{
+			HouseholdID: h.HouseholdID,
+			RoomID:      mustUUID(c.RoomID),
+			Name:        c.Name,
+			Order:       uint(len(h.Rooms) + 1),
+		})
+	}
+
+	return nil
+}
+
+func (inv *inventory) GetUserHouseholds(ctx context.Context, userID string) ([]apphousehold.UserHouseholdModel, error) {
+	if inv.stale {
+		return nil, nil
+	}
+
+	var households []apphousehold.UserHouseholdModel
+	for _, h := range inv.households {
+		if h.UserID == userID {
+			households = append(households, h)
+		}
+	}
+
+	return households, nil
+}
+
+func (inv *inventory) find(householdID string) *apphousehold.UserHouseholdModel {
+	for i := range inv.households {
+		if inv.households[i].HouseholdID.String() == householdID {
+			return &inv.households[i]
+		}
+	}
+
+	return nil
+}
+
+func mustUUID(id string) gocql.UUID {
+	uuid, err := gocql.ParseUUID(id)
+	if err != nil {
+		panic(err)
+	}
+
+	return uuid
+}
+
+func seededInventory(t *testing.T) (*inventory, *export.ExportService) {
+	t.Helper()
+
+	inv := &inventory{}
+	service := export.NewExportService(inv, inv)
+	require.NoError(t, inv.Dispatch(context.Background(), household.CreateHouseholdCommand{HouseholdID: "8a1c2f6e-3b4d-4e5f-9a6b-7c8d9e0f1a2b", UserID: "alice", Name: "Home", Location: "Zagreb", Description: "Flat", Order: 1}))
+	require.NoError(t, inv.Dispatch(context.Background(), household.AddRoomCommand{HouseholdID: "8a1c2f6e-3b4d-4e5f-9a6b-7c8d9e0f1a2b", UserID: "alice", RoomID: "1f2e3d4c-5b6a-4978-8a6b-5c4d3e2f1a0b", Name: "Kitchen"}))
+	require.NoError(t, inv.Dispatch(context.Background(), household.AddRoomCommand{HouseholdID: "8a1c2f6e-3b4d-4e5f-9a6b-7c8d9e0f1a2b", UserID: "alice", RoomID: "2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c6d", Name: "Garage"}))
+	require.NoError(t, inv.Dispatch(context.Background(), household.CreateHouseholdCommand{HouseholdID: "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f", UserID: "alice", Name: "Cottage", Location: "Split", Order: 2}))
+	require.NoError(t, inv.Dispatch(context.Background(), household.AddRoomCommand{HouseholdID: "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f", UserID: "alice", RoomID: "4d5e6f7a-8b9c-4d0e-9f1a-2b3c4d5e6f7a", Name: "Attic"}))
+	inv.dispatched = 0
+
+	return inv, service
+}
+
+// summary reduces households to what an import has to preserve, since IDs and timestamps change
+func summary(households []apphousehold.UserHouseholdModel) map[string][]string {
+	rooms := make(map[string][]string, len(households))
+	for _, h := range households {
+		names := []string{h.Location}
+		for _, room := range h.Rooms {
+			names = append(names, room.Name)
+		}
+		slices.Sort(names[1:])
+		rooms[h.Name] = names
+	}
+
+	return rooms
+}
+
+func importData(userID string, archive shared.InventoryArchive) shared.ImportInventoryCommandData {
+	return shared.ImportInventoryCommandData{UserID: userID, Version: archive.Version, Households: archive.Households}
+}
+
+func Test_ExportService_ExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	inv, service := seededInventory(t)
+
+	archive, err := service.Export(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, shared.InventoryArchiveVersion, archive.Version)
+	assert.Len(t, archive.Households, 2)
+
+	require.NoError(t, service.Import(ctx, importData("bob", archive)))
+	alice, _ := inv.GetUserHouseholds(ctx, "alice")
+	bob, _ := inv.GetUserHouseholds(ctx, "bob")
+	assert.Equal(t, summary(alice), summary(bob))
+	for _, h := range bob {
+		assert.NotContains(t, []string{"8a1c2f6e-3b4d-4e5f-9a6b-7c8d9e0f1a2b", "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f"}, h.HouseholdID.String(), "another account's household IDs can't be reused")
+	}
+
+	dispatched := inv.dispatched
+	require.NoError(t, service.Import(ctx, importData("bob", archive)))
+	assert.Equal(t, dispatched, inv.dispatched, "importing the same archive again should not dispatch anything")
+
+	require.NoError(t, service.Import(ctx, importData("alice", archive)))
+	assert.Equal(t, dispatched, inv.dispatched, "importing an export of the same account should not dispatch anything")
+}
+
+func Test_ExportService_ImportResumesAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	inv, service := seededInventory(t)
+	archive, err := service.Export(ctx, "alice")
+	require.NoError(t, err)
+
+	inv.failAt = 4
+	assert.Error(t, service.Import(ctx, importData("bob", archive)))
+
+	// The retry sees none of the households created so far, so it relies on the aggregate rejecting their IDs
+	inv.stale = true
+	require.NoError(t, service.Import(ctx, importData("bob", archive)))
+	inv.stale = false
+
+	alice, _ := inv.GetUserHouseholds(ctx, "alice")
+	bob, _ := inv.GetUserHouseholds(ctx, "bob")
+	assert.Equal(t, summary(alice), summary(bob))
+}
+
+func Test_ExportService_ImportRejectsConflicts(t *testing.T) {
+	ctx := context.Background()
+	inv, service := seededInventory(t)
+	archive, err := service.Export(ctx, "alice")
+	require.NoError(t, err)
+
+	duplicated := importData("bob", archive)
+	duplicated.Households = slices.Clone(archive.Households)
+	duplicated.Households[1].Name = duplicated.Households[0].Name
+	err = service.Import(ctx, duplicated)
+	assert.True(t, errors.HasType(err, errors.TypeValidation), "households with the same name in the archive should be rejected")
+	assert.Zero(t, inv.dispatched, "nothing should be dispatched for a rejected archive")
+
+	require.NoError(t, inv.Dispatch(ctx, household.CreateHouseholdCommand{HouseholdID: "5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b", UserID: "bob", Name: "Home", Location: "Rijeka"}))
+	err = service.Import(ctx, importData("bob", archive))
+	assert.True(t, errors.HasType(err, errors.TypeDuplicate), "households with the name of an existing household should be rejected")
+	assert.Equal(t, 1, inv.dispatched, "nothing should be dispatched for a conflicting archive")
+}
+
+func Test_WriteCSV(t *testing.T) {
+	_, service := seededInventory(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.ExportCSV(context.Background(), "alice", &buf))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := make(map[string][][]string, len(zr.File))
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		records, err := csv.NewReader(r).ReadAll()
+		require.NoError(t, err)
+		files[f.Name] = records
+	}
+
+	require.Len(t, files["households.csv"], 3)
+	assert.Equal(t, []string{"household_id", "name", "location", "description", "order", "timestamp"}, files["households.csv"][0])
+	assert.Equal(t, []string{"8a1c2f6e-3b4d-4e5f-9a6b-7c8d9e0f1a2b", "Home", "Zagreb", "Flat", "1", "0"}, files["households.csv"][1])
+
+	require.Len(t, files["rooms.csv"], 4)
+	assert.Equal(t, []string{"household_id", "room_id", "name", "order", "timestamp"}, files["rooms.csv"][0])
+	assert.Equal(t, []string{"3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f", "4d5e6f7a-8b9c-4d0e-9f1a-2b3c4d5e6f7a", "Attic", "1", "0"}, files["rooms.csv"][3])
+}
+
+func Test_WritePDF(t *testing.T) {
+	_, service := seededInventory(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.ExportPDF(context.Background(), "alice", &buf))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")), "output should be a PDF document")
+	assert.Contains(t, buf.String(), "/Count 1", "both households should fit on a single page")
+}
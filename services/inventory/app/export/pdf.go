@@ -0,0 +1,62 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/go-pdf/fpdf"
+)
+
+// WritePDF writes a printable report with a section per household and room, followed by totals.
+func WritePDF(w io.Writer, archive shared.InventoryArchive) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Home Inventory Report", true)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	// Core fonts only cover cp1252, so user provided text needs to be translated from UTF-8
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, "Home Inventory Report", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, fmt.Sprintf("Generated %s", time.UnixMilli(archive.ExportedAt).Format(time.RFC1123)), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	totalRooms := 0
+	for _, household := range archive.Households {
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(0, 9, tr(household.Name), "B", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, tr(household.Location), "", 1, "L", false, 0, "")
+		if household.Description != "" {
+			pdf.MultiCell(0, 5, tr(household.Description), "", "L", false)
+		}
+		pdf.Ln(2)
+
+		for _, room := range household.Rooms {
+			pdf.SetFont("Helvetica", "B", 11)
+			pdf.CellFormat(0, 7, tr(room.Name), "", 1, "L", false, 0, "")
+		}
+
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.CellFormat(0, 7, fmt.Sprintf("Rooms: %d", len(household.Rooms)), "T", 1, "R", false, 0, "")
+		pdf.Ln(4)
+
+		totalRooms += len(household.Rooms)
+	}
+
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 8, "Totals", "B", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, fmt.Sprintf("Households: %d", len(archive.Households)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Rooms: %d", totalRooms), "", 1, "L", false, 0, "")
+
+	return pdf.Output(w)
+}
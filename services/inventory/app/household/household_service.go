@@ -99,7 +99,7 @@ func (s HouseholdService) GetUserHouseholds(ctx context.Context, userID string)
 		return nil, err
 	}
 
-	return toSharedUserHouseholds(households), nil
+	return ToSharedUserHouseholds(households), nil
 }
 
 func (s HouseholdService) GetUserHousehold(ctx context.Context, userID, householdID string) (shared.UserHousehold, error) {
@@ -128,7 +128,7 @@ func (s HouseholdService) GetUserHouseholdRoom(ctx context.Context, userID, hous
 	return toSharedUserHouseholdRoom(0, room), nil
 }
 
-func toSharedUserHouseholds(households []UserHouseholdModel) []shared.UserHousehold {
+func ToSharedUserHouseholds(households []UserHouseholdModel) []shared.UserHousehold {
 	sharedHouseholds := make([]shared.UserHousehold, len(households))
 	for i, household := range households {
 		sharedHouseholds[i] = toSharedUserHousehold(household)
@@ -19,4 +19,6 @@ type UserHouseholdModel struct {
 	Rooms       []UserHouseholdRoomModel
 	Timestamp   int64
 	Order       uint
+	// Version is the household aggregate version last projected onto the row
+	Version uint
 }
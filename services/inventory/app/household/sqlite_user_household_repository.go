@@ -0,0 +1,236 @@
+package household
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+// SQLiteUserHouseholdRepository stores the household read model in SQLite for single-node deployments.
+// Rooms are kept in their own table rather than in a map column on the household.
+type SQLiteUserHouseholdRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteUserHouseholdRepository(db *sql.DB) *SQLiteUserHouseholdRepository {
+	return &SQLiteUserHouseholdRepository{db: db}
+}
+
+func (r SQLiteUserHouseholdRepository) InsertHousehold(ctx context.Context, model UserHouseholdModel) error {
+	_, err := r.db.ExecContext(ctx, "INSERT OR REPLACE INTO user_households (user_id, household_id, name, location, description, tstamp, sort_order, version) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", model.UserID, model.HouseholdID.String(), model.Name, model.Location, model.Description, model.Timestamp, model.Order, model.Version)
+
+	return err
+}
+
+func (r SQLiteUserHouseholdRepository) UpdateHousehold(ctx context.Context, model UserHouseholdModel) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE user_households SET name = ?, location = ?, description = ?, tstamp = ?, version = ? WHERE user_id = ? AND household_id = ?", model.Name, model.Location, model.Description, model.Timestamp, model.Version, model.UserID, model.HouseholdID.String())
+
+	return err
+}
+
+func (r SQLiteUserHouseholdRepository) GetHouseholdVersion(ctx context.Context, userId string, householdId string) (uint, bool, error) {
+	householdUUID, err := gocql.ParseUUID(householdId)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid household ID: %s", householdId)
+	}
+
+	var version uint
+	if err := r.db.QueryRowContext(ctx, "SELECT version FROM user_households WHERE user_id = ? AND household_id = ?", userId, householdUUID.String()).Scan(&version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("failed to get household version: %w", err)
+	}
+
+	return version, true, nil
+}
+
+func (r SQLiteUserHouseholdRepository) GetUserHouseholds(ctx context.Context, userId string) ([]UserHouseholdModel, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT household_id, name, location, description, tstamp, sort_order FROM user_households WHERE user_id = ? ORDER BY sort_order", userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user households: %w", err)
+	}
+	defer rows.Close()
+
+	households := make([]UserHouseholdModel, 0)
+	for rows.Next() {
+		var householdId string
+		household := UserHouseholdModel{UserID: userId}
+		if err := rows.Scan(&householdId, &household.Name, &household.Location, &household.Description, &household.Timestamp, &household.Order); err != nil {
+			return nil, fmt.Errorf("failed to scan user household: %w", err)
+		}
+
+		if household.HouseholdID, err = gocql.ParseUUID(householdId); err != nil {
+			return nil, fmt.Errorf("invalid household ID: %s", householdId)
+		}
+
+		households = append(households, household)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get user households: %w", err)
+	}
+	rows.Close()
+
+	rooms, err := r.getRooms(ctx, "SELECT household_id, room_id, name, tstamp, sort_order FROM user_household_rooms WHERE user_id = ? ORDER BY sort_order", userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range households {
+		households[i].Rooms = make([]UserHouseholdRoomModel, 0)
+		for _, room := range rooms {
+			if room.HouseholdID == households[i].HouseholdID {
+				households[i].Rooms = append(households[i].Rooms, room)
+			}
+		}
+	}
+
+	return households, nil
+}
+
+func (r SQLiteUserHouseholdRepository) GetUserHousehold(ctx context.Context, userId string, householdId string) (UserHouseholdModel, bool, error) {
+	householdUUID, err := gocql.ParseUUID(householdId)
+	if err != nil {
+		return UserHouseholdModel{}, false, fmt.Errorf("invalid household ID: %s", householdId)
+	}
+
+	household := UserHouseholdModel{UserID: userId, HouseholdID: householdUUID}
+	if err := r.db.QueryRowContext(ctx, "SELECT name, location, description, tstamp, sort_order FROM user_households WHERE user_id = ? AND household_id = ?", userId, householdUUID.String()).Scan(&household.Name, &household.Location, &household.Description, &household.Timestamp, &household.Order); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return UserHouseholdModel{}, false, nil
+		}
+
+		return UserHouseholdModel{}, false, fmt.Errorf("failed to get user household: %w", err)
+	}
+
+	household.Rooms, err = r.getRooms(ctx, "SELECT household_id, room_id, name, tstamp, sort_order FROM user_household_rooms WHERE user_id = ? AND household_id = ? ORDER BY sort_order", userId, householdUUID.String())
+	if err != nil {
+		return UserHouseholdModel{}, false, err
+	}
+
+	return household, true, nil
+}
+
+func (r SQLiteUserHouseholdRepository) DeleteHousehold(ctx context.Context, userId string, householdId string) error {
+	householdUUID, err := gocql.ParseUUID(householdId)
+	if err != nil {
+		return fmt.Errorf("invalid household ID: %s", householdId)
+	}
+
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM user_household_rooms WHERE user_id = ? AND household_id = ?", userId, householdUUID.String()); err != nil {
+		return fmt.Errorf("failed to delete household rooms: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, "DELETE FROM user_households WHERE user_id = ? AND household_id = ?", userId, householdUUID.String())
+
+	return err
+}
+
+func (r SQLiteUserHouseholdRepository) DeleteUserHouseholds(ctx context.Context, userId string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM user_household_rooms WHERE user_id = ?", userId); err != nil {
+		return fmt.Errorf("failed to delete household rooms: %w", err)
+	}
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM user_households WHERE user_id = ?", userId)
+
+	return err
+}
+
+func (r SQLiteUserHouseholdRepository) UpsertRoom(ctx context.Context, userId string, version uint, model UserHouseholdRoomModel) error {
+	return r.updateRooms(ctx, userId, model.HouseholdID.String(), version, "INSERT OR REPLACE INTO user_household_rooms (user_id, household_id, room_id, name, tstamp, sort_order) VALUES (?, ?, ?, ?, ?, ?)", userId, model.HouseholdID.String(), model.RoomID.String(), model.Name, model.Timestamp, model.Order)
+}
+
+func (r SQLiteUserHouseholdRepository) GetRoom(ctx context.Context, userId string, householdId string, roomId string) (UserHouseholdRoomModel, bool, error) {
+	householdUUID, err := gocql.ParseUUID(householdId)
+	if err != nil {
+		return UserHouseholdRoomModel{}, false, fmt.Errorf("invalid household ID: %s", householdId)
+	}
+
+	roomUUID, err := gocql.ParseUUID(roomId)
+	if err != nil {
+		return UserHouseholdRoomModel{}, false, fmt.Errorf("invalid room ID: %s", roomId)
+	}
+
+	rooms, err := r.getRooms(ctx, "SELECT household_id, room_id, name, tstamp, sort_order FROM user_household_rooms WHERE user_id = ? AND household_id = ? AND room_id = ?", userId, householdUUID.String(), roomUUID.String())
+	if err != nil {
+		return UserHouseholdRoomModel{}, false, err
+	}
+
+	if len(rooms) == 0 {
+		return UserHouseholdRoomModel{}, false, nil
+	}
+
+	return rooms[0], true, nil
+}
+
+func (r SQLiteUserHouseholdRepository) DeleteRoom(ctx context.Context, userId string, householdId string, roomId string, version uint) error {
+	householdUUID, err := gocql.ParseUUID(householdId)
+	if err != nil {
+		return fmt.Errorf("invalid household ID: %s", householdId)
+	}
+
+	roomUUID, err := gocql.ParseUUID(roomId)
+	if err != nil {
+		return fmt.Errorf("invalid room ID: %s", roomId)
+	}
+
+	return r.updateRooms(ctx, userId, householdUUID.String(), version, "DELETE FROM user_household_rooms WHERE user_id = ? AND household_id = ? AND room_id = ?", userId, householdUUID.String(), roomUUID.String())
+}
+
+// updateRooms changes the household's rooms and records the household version in one transaction,
+// like the single update of the rooms map does in Cassandra.
+func (r SQLiteUserHouseholdRepository) updateRooms(ctx context.Context, userId string, householdId string, version uint, query string, args ...interface{}) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to update rooms: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE user_households SET version = ? WHERE user_id = ? AND household_id = ?", version, userId, householdId); err != nil {
+		return fmt.Errorf("failed to update household version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r SQLiteUserHouseholdRepository) getRooms(ctx context.Context, query string, args ...interface{}) ([]UserHouseholdRoomModel, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rooms: %w", err)
+	}
+	defer rows.Close()
+
+	rooms := make([]UserHouseholdRoomModel, 0)
+	for rows.Next() {
+		var householdId, roomId string
+		var room UserHouseholdRoomModel
+		if err := rows.Scan(&householdId, &roomId, &room.Name, &room.Timestamp, &room.Order); err != nil {
+			return nil, fmt.Errorf("failed to scan room: %w", err)
+		}
+
+		if room.HouseholdID, err = gocql.ParseUUID(householdId); err != nil {
+			return nil, fmt.Errorf("invalid household ID: %s", householdId)
+		}
+
+		if room.RoomID, err = gocql.ParseUUID(roomId); err != nil {
+			return nil, fmt.Errorf("invalid room ID: %s", roomId)
+		}
+
+		rooms = append(rooms, room)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get rooms: %w", err)
+	}
+
+	return rooms, nil
+}
@@ -12,12 +12,15 @@ import (
 type HouseholdRepo interface {
 	InsertHousehold(ctx context.Context, model UserHouseholdModel) error
 	UpdateHousehold(ctx context.Context, model UserHouseholdModel) error
+	GetHouseholdVersion(ctx context.Context, userId string, householdId string) (uint, bool, error)
 	DeleteHousehold(ctx context.Context, userId string, householdId string) error
 
-	UpsertRoom(ctx context.Context, userId string, model UserHouseholdRoomModel) error
-	DeleteRoom(ctx context.Context, userId string, householdId string, roomId string) error
+	UpsertRoom(ctx context.Context, userId string, version uint, model UserHouseholdRoomModel) error
+	DeleteRoom(ctx context.Context, userId string, householdId string, roomId string, version uint) error
 }
 
+// UserHouseholdProjector keeps the user households read model. Every row records the household version it last
+// applied, so events delivered again are skipped instead of rolling the row back.
 type UserHouseholdProjector struct {
 	repository HouseholdRepo
 }
@@ -28,20 +31,20 @@ func NewUserHouseholdProjector(repository HouseholdRepo) *UserHouseholdProjector
 	}
 }
 
-func (p UserHouseholdProjector) HandleEvent(ctx context.Context, event es.EventData) error {
-	switch e := event.(type) {
+func (p UserHouseholdProjector) HandleEvent(ctx context.Context, event es.Event) error {
+	switch e := event.Data.(type) {
 	case household.HouseholdCreatedEvent:
-		return p.handleHouseholdCreatedEvent(ctx, e)
+		return p.handleHouseholdCreatedEvent(ctx, e, event.Version)
 	case household.HouseholdUpdatedEvent:
-		return p.handleHouseholdUpdatedEvent(ctx, e)
+		return p.handleHouseholdUpdatedEvent(ctx, e, event.Version)
 	case household.HouseholdDeletedEvent:
-		return p.handleHouseholdDeletedEvent(ctx, e)
+		return p.handleHouseholdDeletedEvent(ctx, e, event.Version)
 	case household.RoomAddedEvent:
-		return p.handleRoomAddedEvent(ctx, e)
+		return p.handleRoomAddedEvent(ctx, e, event.Version)
 	case household.RoomUpdatedEvent:
-		return p.handleRoomUpdatedEvent(ctx, e)
+		return p.handleRoomUpdatedEvent(ctx, e, event.Version)
 	case household.RoomDeletedEvent:
-		return p.handleRoomDeletedEvent(ctx, e)
+		return p.handleRoomDeletedEvent(ctx, e, event.Version)
 	default:
 		return es.ErrUnknownEvent
 	}
@@ -62,7 +65,11 @@ func (p UserHouseholdProjector) Name() string {
 	return "household.UserHouseholdProjector"
 }
 
-func (p UserHouseholdProjector) handleHouseholdCreatedEvent(ctx context.Context, e household.HouseholdCreatedEvent) error {
+func (p UserHouseholdProjector) handleHouseholdCreatedEvent(ctx context.Context, e household.HouseholdCreatedEvent, version uint) error {
+	if apply, err := p.applies(ctx, e.UserID, e.HouseholdID, version, true); err != nil || !apply {
+		return err
+	}
+
 	householdUUID, err := gocql.ParseUUID(e.HouseholdID)
 	if err != nil {
 		return fmt.Errorf("failed to parse household ID: %w", err)
@@ -76,6 +83,7 @@ func (p UserHouseholdProjector) handleHouseholdCreatedEvent(ctx context.Context,
 		Description: e.Description,
 		Order:       e.Order,
 		Timestamp:   e.Timestamp,
+		Version:     version,
 	}); err != nil {
 		return fmt.Errorf("failed to insert household: %w", err)
 	}
@@ -83,7 +91,11 @@ func (p UserHouseholdProjector) handleHouseholdCreatedEvent(ctx context.Context,
 	return nil
 }
 
-func (p UserHouseholdProjector) handleHouseholdUpdatedEvent(ctx context.Context, e household.HouseholdUpdatedEvent) error {
+func (p UserHouseholdProjector) handleHouseholdUpdatedEvent(ctx context.Context, e household.HouseholdUpdatedEvent, version uint) error {
+	if apply, err := p.applies(ctx, e.UserID, e.HouseholdID, version, false); err != nil || !apply {
+		return err
+	}
+
 	householdUUID, err := gocql.ParseUUID(e.HouseholdID)
 	if err != nil {
 		return fmt.Errorf("failed to parse household ID: %w", err)
@@ -96,6 +108,7 @@ func (p UserHouseholdProjector) handleHouseholdUpdatedEvent(ctx context.Context,
 		Location:    e.Location,
 		Description: e.Description,
 		Timestamp:   e.Timestamp,
+		Version:     version,
 	}); err != nil {
 		return fmt.Errorf("failed to update household: %w", err)
 	}
@@ -103,7 +116,11 @@ func (p UserHouseholdProjector) handleHouseholdUpdatedEvent(ctx context.Context,
 	return nil
 }
 
-func (p UserHouseholdProjector) handleHouseholdDeletedEvent(ctx context.Context, e household.HouseholdDeletedEvent) error {
+func (p UserHouseholdProjector) handleHouseholdDeletedEvent(ctx context.Context, e household.HouseholdDeletedEvent, version uint) error {
+	if apply, err := p.applies(ctx, e.UserID, e.HouseholdID, version, false); err != nil || !apply {
+		return err
+	}
+
 	if err := p.repository.DeleteHousehold(ctx, e.UserID, e.HouseholdID); err != nil {
 		return fmt.Errorf("failed to delete household: %w", err)
 	}
@@ -111,7 +128,11 @@ func (p UserHouseholdProjector) handleHouseholdDeletedEvent(ctx context.Context,
 	return nil
 }
 
-func (p UserHouseholdProjector) handleRoomAddedEvent(ctx context.Context, e household.RoomAddedEvent) error {
+func (p UserHouseholdProjector) handleRoomAddedEvent(ctx context.Context, e household.RoomAddedEvent, version uint) error {
+	if apply, err := p.applies(ctx, e.UserID, e.HouseholdID, version, false); err != nil || !apply {
+		return err
+	}
+
 	householdUUID, err := gocql.ParseUUID(e.HouseholdID)
 	if err != nil {
 		return fmt.Errorf("failed to parse household ID: %w", err)
@@ -122,7 +143,7 @@ func (p UserHouseholdProjector) handleRoomAddedEvent(ctx context.Context, e hous
 		return fmt.Errorf("failed to parse room ID: %w", err)
 	}
 
-	if err := p.repository.UpsertRoom(ctx, e.UserID, UserHouseholdRoomModel{
+	if err := p.repository.UpsertRoom(ctx, e.UserID, version, UserHouseholdRoomModel{
 		HouseholdID: householdUUID,
 		RoomID:      roomUUID,
 		Name:        e.Name,
@@ -135,7 +156,11 @@ func (p UserHouseholdProjector) handleRoomAddedEvent(ctx context.Context, e hous
 	return nil
 }
 
-func (p UserHouseholdProjector) handleRoomUpdatedEvent(ctx context.Context, e household.RoomUpdatedEvent) error {
+func (p UserHouseholdProjector) handleRoomUpdatedEvent(ctx context.Context, e household.RoomUpdatedEvent, version uint) error {
+	if apply, err := p.applies(ctx, e.UserID, e.HouseholdID, version, false); err != nil || !apply {
+		return err
+	}
+
 	householdUUID, err := gocql.ParseUUID(e.HouseholdID)
 	if err != nil {
 		return fmt.Errorf("failed to parse household ID: %w", err)
@@ -146,7 +171,7 @@ func (p UserHouseholdProjector) handleRoomUpdatedEvent(ctx context.Context, e ho
 		return fmt.Errorf("failed to parse room ID: %w", err)
 	}
 
-	if err := p.repository.UpsertRoom(ctx, e.UserID, UserHouseholdRoomModel{
+	if err := p.repository.UpsertRoom(ctx, e.UserID, version, UserHouseholdRoomModel{
 		HouseholdID: householdUUID,
 		RoomID:      roomUUID,
 		Name:        e.Name,
@@ -159,10 +184,30 @@ func (p UserHouseholdProjector) handleRoomUpdatedEvent(ctx context.Context, e ho
 	return nil
 }
 
-func (p UserHouseholdProjector) handleRoomDeletedEvent(ctx context.Context, e household.RoomDeletedEvent) error {
-	if err := p.repository.DeleteRoom(ctx, e.UserID, e.HouseholdID, e.RoomID); err != nil {
+func (p UserHouseholdProjector) handleRoomDeletedEvent(ctx context.Context, e household.RoomDeletedEvent, version uint) error {
+	if apply, err := p.applies(ctx, e.UserID, e.HouseholdID, version, false); err != nil || !apply {
+		return err
+	}
+
+	if err := p.repository.DeleteRoom(ctx, e.UserID, e.HouseholdID, e.RoomID, version); err != nil {
 		return fmt.Errorf("failed to delete room: %w", err)
 	}
 
 	return nil
 }
+
+// applies reports whether an event at the given household version still has to be projected. The row must not
+// have seen that version yet, and has to exist unless the event creates it; a missing row means the household
+// was deleted by a later event.
+func (p UserHouseholdProjector) applies(ctx context.Context, userID, householdID string, version uint, creates bool) (bool, error) {
+	appliedVersion, found, err := p.repository.GetHouseholdVersion(ctx, userID, householdID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get household version: %w", err)
+	}
+
+	if !found {
+		return creates, nil
+	}
+
+	return version > appliedVersion, nil
+}
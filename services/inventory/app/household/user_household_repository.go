@@ -18,11 +18,31 @@ func NewUserHouseholdRepository(db *gocql.Session) *UserHouseholdRepository {
 }
 
 func (r UserHouseholdRepository) InsertHousehold(ctx context.Context, model UserHouseholdModel) error {
-	return r.db.Query("INSERT INTO user_households (user_id, household_id, name, location, description, tstamp, sort_order) VALUES (?, ?, ?, ?, ?, ?, ?)", model.UserID, model.HouseholdID, model.Name, model.Location, model.Description, model.Timestamp, model.Order).WithContext(ctx).Exec()
+	return r.db.Query("INSERT INTO user_households (user_id, household_id, name, location, description, tstamp, sort_order, version) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", model.UserID, model.HouseholdID, model.Name, model.Location, model.Description, model.Timestamp, model.Order, model.Version).WithContext(ctx).Exec()
 }
 
 func (r UserHouseholdRepository) UpdateHousehold(ctx context.Context, model UserHouseholdModel) error {
-	return r.db.Query("UPDATE user_households SET name = ?, location = ?, description = ?, tstamp = ? WHERE user_id = ? AND household_id = ?", model.Name, model.Location, model.Description, model.Timestamp, model.UserID, model.HouseholdID).WithContext(ctx).Exec()
+	return r.db.Query("UPDATE user_households SET name = ?, location = ?, description = ?, tstamp = ?, version = ? WHERE user_id = ? AND household_id = ?", model.Name, model.Location, model.Description, model.Timestamp, model.Version, model.UserID, model.HouseholdID).WithContext(ctx).Exec()
+}
+
+// GetHouseholdVersion returns the household aggregate version last projected onto the user's household row.
+// Rows projected before versions were recorded report version 0.
+func (r UserHouseholdRepository) GetHouseholdVersion(ctx context.Context, userId string, householdId string) (uint, bool, error) {
+	householdUUID, err := gocql.ParseUUID(householdId)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid household ID: %s", householdId)
+	}
+
+	var version uint
+	if err := r.db.Query("SELECT version FROM user_households WHERE user_id = ? AND household_id = ?", userId, householdUUID).WithContext(ctx).Scan(&version); err != nil {
+		if err == gocql.ErrNotFound {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("failed to get household version: %w", err)
+	}
+
+	return version, true, nil
 }
 
 func (r UserHouseholdRepository) GetUserHouseholds(ctx context.Context, userId string) ([]UserHouseholdModel, error) {
@@ -126,8 +146,12 @@ func (r UserHouseholdRepository) DeleteHousehold(ctx context.Context, userId str
 	return r.db.Query("DELETE FROM user_households WHERE user_id = ? AND household_id = ?", userId, householdUUID).WithContext(ctx).Exec()
 }
 
-func (r UserHouseholdRepository) UpsertRoom(ctx context.Context, userId string, model UserHouseholdRoomModel) error {
-	return r.db.Query("UPDATE user_households SET rooms[?] = ? WHERE user_id = ? AND household_id = ?", model.RoomID.String(), model, userId, model.HouseholdID).WithContext(ctx).Exec()
+func (r UserHouseholdRepository) DeleteUserHouseholds(ctx context.Context, userId string) error {
+	return r.db.Query("DELETE FROM user_households WHERE user_id = ?", userId).WithContext(ctx).Exec()
+}
+
+func (r UserHouseholdRepository) UpsertRoom(ctx context.Context, userId string, version uint, model UserHouseholdRoomModel) error {
+	return r.db.Query("UPDATE user_households SET rooms[?] = ?, version = ? WHERE user_id = ? AND household_id = ?", model.RoomID.String(), model, version, userId, model.HouseholdID).WithContext(ctx).Exec()
 }
 
 func (r UserHouseholdRepository) GetRoom(ctx context.Context, userId string, householdId string, roomId string) (UserHouseholdRoomModel, bool, error) {
@@ -148,11 +172,11 @@ func (r UserHouseholdRepository) GetRoom(ctx context.Context, userId string, hou
 	return room, true, nil
 }
 
-func (r UserHouseholdRepository) DeleteRoom(ctx context.Context, userId string, householdId string, roomId string) error {
+func (r UserHouseholdRepository) DeleteRoom(ctx context.Context, userId string, householdId string, roomId string, version uint) error {
 	householdUUID, err := gocql.ParseUUID(householdId)
 	if err != nil {
 		return fmt.Errorf("invalid household ID: %s", householdId)
 	}
 
-	return r.db.Query("DELETE rooms[?] FROM user_households WHERE user_id = ? AND household_id = ?", roomId, userId, householdUUID).WithContext(ctx).Exec()
+	return r.db.Query("UPDATE user_households SET rooms = rooms - ?, version = ? WHERE user_id = ? AND household_id = ?", []string{roomId}, version, userId, householdUUID).WithContext(ctx).Exec()
 }
@@ -0,0 +1,26 @@
+package label
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"strings"
+)
+
+const codeBytes = 5
+
+var codeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Code returns the short code printed on the label of an entity. Codes are derived from the entity, so printing
+// the same label twice yields the same code and old labels keep working. Codes are short enough to collide, so
+// an entity whose code is taken moves on to the code of its next attempt.
+func Code(entityType, entityID string, attempt int) string {
+	seed := entityType + ":" + entityID
+	if attempt > 0 {
+		seed = fmt.Sprintf("%s:%d", seed, attempt)
+	}
+
+	sum := sha256.Sum256([]byte(seed))
+
+	return strings.ToLower(codeEncoding.EncodeToString(sum[:codeBytes]))
+}
@@ -0,0 +1,83 @@
+package label
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+type LabelCodeRepository struct {
+	db *gocql.Session
+}
+
+func NewLabelCodeRepository(db *gocql.Session) *LabelCodeRepository {
+	return &LabelCodeRepository{db: db}
+}
+
+func (r LabelCodeRepository) InsertLabelCode(ctx context.Context, model LabelCodeModel) (bool, error) {
+	var roomID interface{}
+	if model.RoomID != (gocql.UUID{}) {
+		roomID = model.RoomID
+	}
+
+	applied, err := r.db.Query("INSERT INTO label_codes (code, user_id, entity_type, household_id, room_id) VALUES (?, ?, ?, ?, ?) IF NOT EXISTS", model.Code, model.UserID, model.EntityType, model.HouseholdID, roomID).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return false, fmt.Errorf("failed to insert label code: %w", err)
+	}
+
+	if !applied {
+		return false, nil
+	}
+
+	if err := r.db.Query("INSERT INTO user_label_codes (user_id, code) VALUES (?, ?)", model.UserID, model.Code).WithContext(ctx).Exec(); err != nil {
+		return false, fmt.Errorf("failed to insert user label code: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r LabelCodeRepository) GetLabelCode(ctx context.Context, code string) (LabelCodeModel, bool, error) {
+	model := LabelCodeModel{Code: code}
+	if err := r.db.Query("SELECT user_id, entity_type, household_id, room_id FROM label_codes WHERE code = ?", code).WithContext(ctx).Scan(&model.UserID, &model.EntityType, &model.HouseholdID, &model.RoomID); err != nil {
+		if err == gocql.ErrNotFound {
+			return LabelCodeModel{}, false, nil
+		}
+
+		return LabelCodeModel{}, false, fmt.Errorf("failed to get label code: %w", err)
+	}
+
+	return model, true, nil
+}
+
+func (r LabelCodeRepository) GetUserLabelCodes(ctx context.Context, userId string) ([]string, error) {
+	var code string
+	iter := r.db.Query("SELECT code FROM user_label_codes WHERE user_id = ?", userId).WithContext(ctx).Iter()
+	defer iter.Close()
+
+	codes := make([]string, 0)
+	for iter.Scan(&code) {
+		codes = append(codes, code)
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get user label codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+func (r LabelCodeRepository) DeleteUserLabelCodes(ctx context.Context, userId string) error {
+	codes, err := r.GetUserLabelCodes(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, code := range codes {
+		if err := r.db.Query("DELETE FROM label_codes WHERE code = ?", code).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("failed to delete label code %s: %w", code, err)
+		}
+	}
+
+	return r.db.Query("DELETE FROM user_label_codes WHERE user_id = ?", userId).WithContext(ctx).Exec()
+}
@@ -0,0 +1,179 @@
+package label
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/bnkamalesh/errors"
+	apphousehold "github.com/cybre/home-inventory/services/inventory/app/household"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/gocql/gocql"
+)
+
+type UserHouseholdRepo interface {
+	GetUserHouseholds(ctx context.Context, userID string) ([]apphousehold.UserHouseholdModel, error)
+	GetUserHousehold(ctx context.Context, userID, householdID string) (apphousehold.UserHouseholdModel, bool, error)
+	GetRoom(ctx context.Context, userID, householdID, roomID string) (apphousehold.UserHouseholdRoomModel, bool, error)
+}
+
+type LabelCodeRepo interface {
+	// InsertLabelCode stores the code unless it is taken, and tells whether it was stored.
+	InsertLabelCode(ctx context.Context, model LabelCodeModel) (bool, error)
+	GetLabelCode(ctx context.Context, code string) (LabelCodeModel, bool, error)
+}
+
+// maxCodeAttempts bounds how many codes are tried for an entity before giving up
+const maxCodeAttempts = 10
+
+type LabelService struct {
+	householdRepository UserHouseholdRepo
+	codeRepository      LabelCodeRepo
+}
+
+func NewLabelService(householdRepository UserHouseholdRepo, codeRepository LabelCodeRepo) *LabelService {
+	return &LabelService{
+		householdRepository: householdRepository,
+		codeRepository:      codeRepository,
+	}
+}
+
+// LabelSheet writes a printable PDF with a label for every household and room of the user, or only for the
+// requested household and its rooms. Each label carries a QR code linking to the scan page of the web app.
+func (s LabelService) LabelSheet(ctx context.Context, data shared.LabelSheetQueryData, w io.Writer) error {
+	layout, ok := GetLayout(data.Layout)
+	if !ok {
+		return errors.InputBodyf("unsupported label layout: %s", data.Layout)
+	}
+
+	households, err := s.households(ctx, data.UserID, data.HouseholdID)
+	if err != nil {
+		return err
+	}
+
+	scanURL := strings.TrimSuffix(data.BaseURL, "/") + "/scan/"
+
+	labels := []Label{}
+	for _, household := range households {
+		code, err := s.registerCode(ctx, LabelCodeModel{
+			UserID:      data.UserID,
+			EntityType:  shared.LabelEntityHousehold,
+			HouseholdID: household.HouseholdID,
+		})
+		if err != nil {
+			return err
+		}
+
+		labels = append(labels, Label{Title: household.Name, Subtitle: household.Location, Code: code, URL: scanURL + code})
+
+		for _, room := range household.Rooms {
+			code, err := s.registerCode(ctx, LabelCodeModel{
+				UserID:      data.UserID,
+				EntityType:  shared.LabelEntityRoom,
+				HouseholdID: household.HouseholdID,
+				RoomID:      room.RoomID,
+			})
+			if err != nil {
+				return err
+			}
+
+			labels = append(labels, Label{Title: room.Name, Subtitle: household.Name, Code: code, URL: scanURL + code})
+		}
+	}
+
+	return WriteLabelSheet(w, layout, labels)
+}
+
+// ResolveLabel returns the entity a label code points to. Codes of other users and codes of deleted
+// entities are reported as not found.
+func (s LabelService) ResolveLabel(ctx context.Context, userID, code string) (shared.LabelTarget, error) {
+	model, found, err := s.codeRepository.GetLabelCode(ctx, strings.ToLower(code))
+	if err != nil {
+		return shared.LabelTarget{}, errors.InternalErr(err, "failed to get label code")
+	}
+
+	if !found || model.UserID != userID {
+		return shared.LabelTarget{}, errors.NotFoundf("label %s not found", code)
+	}
+
+	target := shared.LabelTarget{
+		Code:        model.Code,
+		UserID:      model.UserID,
+		EntityType:  model.EntityType,
+		HouseholdID: model.HouseholdID.String(),
+	}
+
+	switch model.EntityType {
+	case shared.LabelEntityHousehold:
+		_, found, err = s.householdRepository.GetUserHousehold(ctx, userID, target.HouseholdID)
+	case shared.LabelEntityRoom:
+		target.RoomID = model.RoomID.String()
+		_, found, err = s.householdRepository.GetRoom(ctx, userID, target.HouseholdID, target.RoomID)
+	default:
+		found = false
+	}
+	if err != nil {
+		return shared.LabelTarget{}, errors.InternalErr(err, "failed to get labelled entity")
+	}
+
+	if !found {
+		return shared.LabelTarget{}, errors.NotFoundf("%s labelled %s no longer exists", model.EntityType, code)
+	}
+
+	return target, nil
+}
+
+func (s LabelService) households(ctx context.Context, userID, householdID string) ([]apphousehold.UserHouseholdModel, error) {
+	if householdID == "" {
+		households, err := s.householdRepository.GetUserHouseholds(ctx, userID)
+		if err != nil {
+			return nil, errors.InternalErr(err, "failed to get user households")
+		}
+
+		return households, nil
+	}
+
+	household, found, err := s.householdRepository.GetUserHousehold(ctx, userID, householdID)
+	if err != nil {
+		return nil, errors.InternalErr(err, "failed to get user household")
+	}
+
+	if !found {
+		return nil, errors.NotFoundf("household with ID %s not found", householdID)
+	}
+
+	return []apphousehold.UserHouseholdModel{household}, nil
+}
+
+// registerCode finds the code of an entity, registering it if the entity has none yet. Codes taken by other
+// entities are skipped, so a collision never takes over another label.
+func (s LabelService) registerCode(ctx context.Context, model LabelCodeModel) (string, error) {
+	entityID := model.HouseholdID
+	if model.RoomID != (gocql.UUID{}) {
+		entityID = model.RoomID
+	}
+
+	for attempt := 0; attempt < maxCodeAttempts; attempt++ {
+		model.Code = Code(model.EntityType, entityID.String(), attempt)
+
+		inserted, err := s.codeRepository.InsertLabelCode(ctx, model)
+		if err != nil {
+			return "", errors.InternalErr(err, "failed to register label code")
+		}
+
+		if inserted {
+			return model.Code, nil
+		}
+
+		existing, found, err := s.codeRepository.GetLabelCode(ctx, model.Code)
+		if err != nil {
+			return "", errors.InternalErr(err, "failed to get label code")
+		}
+
+		if found && existing.labels(model) {
+			return model.Code, nil
+		}
+	}
+
+	return "", errors.Internalf("no free label code for %s %s", model.EntityType, entityID)
+}
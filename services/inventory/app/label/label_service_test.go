@@ -0,0 +1,160 @@
+package label_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bnkamalesh/errors"
+	apphousehold "github.com/cybre/home-inventory/services/inventory/app/household"
+	"github.com/cybre/home-inventory/services/inventory/app/label"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryCodes map[string]label.LabelCodeModel
+
+func (m memoryCodes) InsertLabelCode(ctx context.Context, model label.LabelCodeModel) (bool, error) {
+	if _, ok := m[model.Code]; ok {
+		return false, nil
+	}
+
+	m[model.Code] = model
+
+	return true, nil
+}
+
+func (m memoryCodes) GetLabelCode(ctx context.Context, code string) (label.LabelCodeModel, bool, error) {
+	model, ok := m[code]
+
+	return model, ok, nil
+}
+
+type memoryHouseholds []apphousehold.UserHouseholdModel
+
+func (m memoryHouseholds) GetUserHouseholds(ctx context.Context, userID string) ([]apphousehold.UserHouseholdModel, error) {
+	var households []apphousehold.UserHouseholdModel
+	for _, household := range m {
+		if household.UserID == userID {
+			households = append(households, household)
+		}
+	}
+
+	return households, nil
+}
+
+func (m memoryHouseholds) GetUserHousehold(ctx context.Context, userID, householdID string) (apphousehold.UserHouseholdModel, bool, error) {
+	for _, household := range m {
+		if household.UserID == userID && household.HouseholdID.String() == householdID {
+			return household, true, nil
+		}
+	}
+
+	return apphousehold.UserHouseholdModel{}, false, nil
+}
+
+func (m memoryHouseholds) GetRoom(ctx context.Context, userID, householdID, roomID string) (apphousehold.UserHouseholdRoomModel, bool, error) {
+	household, ok, _ := m.GetUserHousehold(ctx, userID, householdID)
+	if !ok {
+		return apphousehold.UserHouseholdRoomModel{}, false, nil
+	}
+
+	for _, room := range household.Rooms {
+		if room.RoomID.String() == roomID {
+			return room, true, nil
+		}
+	}
+
+	return apphousehold.UserHouseholdRoomModel{}, false, nil
+}
+
+var (
+	householdID = mustUUID("8a1c2f6e-3b4d-4e5f-9a6b-7c8d9e0f1a2b")
+	roomID      = mustUUID("1f2e3d4c-5b6a-4978-8a6b-5c4d3e2f1a0b")
+)
+
+func mustUUID(id string) gocql.UUID {
+	uuid, err := gocql.ParseUUID(id)
+	if err != nil {
+		panic(err)
+	}
+
+	return uuid
+}
+
+func households() memoryHouseholds {
+	return memoryHouseholds{{
+		UserID:      "alice",
+		HouseholdID: householdID,
+		Name:        "Home",
+		Location:    "Zagreb",
+		Rooms:       []apphousehold.UserHouseholdRoomModel{{HouseholdID: householdID, RoomID: roomID, Name: "Kitchen"}},
+	}}
+}
+
+func Test_Code(t *testing.T) {
+	code := label.Code(shared.LabelEntityRoom, roomID.String(), 0)
+	assert.Regexp(t, "^[a-z2-7]{8}$", code, "codes should be eight lowercase base32 characters")
+	assert.Equal(t, code, label.Code(shared.LabelEntityRoom, roomID.String(), 0), "codes should be derived from the entity")
+	assert.NotEqual(t, code, label.Code(shared.LabelEntityRoom, roomID.String(), 1), "every attempt should yield another code")
+	assert.NotEqual(t, code, label.Code(shared.LabelEntityHousehold, roomID.String(), 0), "the entity type should be part of the code")
+}
+
+func Test_LabelService_LabelSheet(t *testing.T) {
+	ctx := context.Background()
+	householdCode := label.Code(shared.LabelEntityHousehold, householdID.String(), 0)
+	roomCode := label.Code(shared.LabelEntityRoom, roomID.String(), 0)
+
+	// The first code of the room belongs to a label of another user already
+	codes := memoryCodes{roomCode: {Code: roomCode, UserID: "bob", EntityType: shared.LabelEntityHousehold}}
+	service := label.NewLabelService(households(), codes)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.LabelSheet(ctx, shared.LabelSheetQueryData{UserID: "alice", Layout: label.DefaultLayout, BaseURL: "https://inventory.example/"}, &buf))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")), "output should be a PDF document")
+	assert.Len(t, codes, 3)
+	assert.Equal(t, "alice", codes[householdCode].UserID)
+	assert.Equal(t, "bob", codes[roomCode].UserID, "a taken code should not be taken over")
+
+	nextRoomCode := label.Code(shared.LabelEntityRoom, roomID.String(), 1)
+	assert.Equal(t, label.LabelCodeModel{Code: nextRoomCode, UserID: "alice", EntityType: shared.LabelEntityRoom, HouseholdID: householdID, RoomID: roomID}, codes[nextRoomCode])
+
+	require.NoError(t, service.LabelSheet(ctx, shared.LabelSheetQueryData{UserID: "alice", Layout: label.DefaultLayout, BaseURL: "https://inventory.example"}, &buf))
+	assert.Len(t, codes, 3, "printing the labels again should reuse their codes")
+
+	err := service.LabelSheet(ctx, shared.LabelSheetQueryData{UserID: "alice", Layout: "unknown"}, &buf)
+	assert.True(t, errors.HasType(err, errors.TypeInputBody))
+}
+
+func Test_LabelService_ResolveLabel(t *testing.T) {
+	ctx := context.Background()
+	codes := memoryCodes{}
+	service := label.NewLabelService(households(), codes)
+	require.NoError(t, service.LabelSheet(ctx, shared.LabelSheetQueryData{UserID: "alice", Layout: label.DefaultLayout, BaseURL: "https://inventory.example"}, &bytes.Buffer{}))
+
+	householdCode := label.Code(shared.LabelEntityHousehold, householdID.String(), 0)
+	target, err := service.ResolveLabel(ctx, "alice", householdCode)
+	require.NoError(t, err)
+	assert.Equal(t, shared.LabelTarget{Code: householdCode, UserID: "alice", EntityType: shared.LabelEntityHousehold, HouseholdID: householdID.String()}, target)
+
+	roomCode := label.Code(shared.LabelEntityRoom, roomID.String(), 0)
+	target, err = service.ResolveLabel(ctx, "alice", roomCode)
+	require.NoError(t, err)
+	assert.Equal(t, shared.LabelEntityRoom, target.EntityType)
+	assert.Equal(t, roomID.String(), target.RoomID)
+
+	_, err = service.ResolveLabel(ctx, "alice", strings.ToUpper(roomCode))
+	assert.NoError(t, err, "codes typed in upper case should resolve")
+
+	_, err = service.ResolveLabel(ctx, "alice", "abcdefgh")
+	assert.True(t, errors.HasType(err, errors.TypeNotFound), "unknown codes should not be found")
+
+	_, err = service.ResolveLabel(ctx, "bob", householdCode)
+	assert.True(t, errors.HasType(err, errors.TypeNotFound), "codes of other users should not be found")
+
+	_, err = label.NewLabelService(memoryHouseholds{}, codes).ResolveLabel(ctx, "alice", roomCode)
+	assert.True(t, errors.HasType(err, errors.TypeNotFound), "codes of deleted entities should not be found")
+}
@@ -0,0 +1,34 @@
+package label
+
+import "github.com/cybre/home-inventory/services/inventory/shared"
+
+// Layout describes a sheet of labels. All dimensions are in millimetres.
+type Layout struct {
+	PageSize    string
+	Columns     int
+	Rows        int
+	LabelWidth  float64
+	LabelHeight float64
+	MarginTop   float64
+	MarginLeft  float64
+	GapX        float64
+	GapY        float64
+}
+
+var layouts = map[string]Layout{
+	shared.LabelLayoutAveryL7160: {PageSize: "A4", Columns: 3, Rows: 7, LabelWidth: 63.5, LabelHeight: 38.1, MarginTop: 15.15, MarginLeft: 7.25, GapX: 2.5},
+	shared.LabelLayoutAveryL7163: {PageSize: "A4", Columns: 2, Rows: 7, LabelWidth: 99.1, LabelHeight: 38.1, MarginTop: 15.15, MarginLeft: 4.65, GapX: 2.5},
+	shared.LabelLayoutAvery5160:  {PageSize: "Letter", Columns: 3, Rows: 10, LabelWidth: 66.7, LabelHeight: 25.4, MarginTop: 12.7, MarginLeft: 4.8, GapX: 3.2},
+}
+
+const DefaultLayout = shared.LabelLayoutAveryL7160
+
+func GetLayout(name string) (Layout, bool) {
+	if name == "" {
+		name = DefaultLayout
+	}
+
+	layout, ok := layouts[name]
+
+	return layout, ok
+}
@@ -0,0 +1,16 @@
+package label
+
+import "github.com/gocql/gocql"
+
+type LabelCodeModel struct {
+	Code        string
+	UserID      string
+	EntityType  string
+	HouseholdID gocql.UUID
+	RoomID      gocql.UUID
+}
+
+// labels tells whether the code is registered for the same entity as the other one.
+func (m LabelCodeModel) labels(other LabelCodeModel) bool {
+	return m.UserID == other.UserID && m.EntityType == other.EntityType && m.HouseholdID == other.HouseholdID && m.RoomID == other.RoomID
+}
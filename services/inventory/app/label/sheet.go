@@ -0,0 +1,79 @@
+package label
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf"
+	"github.com/go-pdf/fpdf/contrib/barcode"
+)
+
+const labelPadding = 2.0
+
+// Label is a single printed label. The QR code encodes URL, while the title, subtitle and code are printed next to it.
+type Label struct {
+	Title    string
+	Subtitle string
+	Code     string
+	URL      string
+}
+
+// WriteLabelSheet writes the labels as a PDF, filling the sheets of the given layout row by row.
+func WriteLabelSheet(w io.Writer, layout Layout, labels []Label) error {
+	pdf := fpdf.New("P", "mm", layout.PageSize, "")
+	pdf.SetTitle("Home Inventory Labels", true)
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+
+	// Core fonts only cover cp1252, so user provided text needs to be translated from UTF-8
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	perPage := layout.Columns * layout.Rows
+	qrSize := layout.LabelHeight - 2*labelPadding
+	textWidth := layout.LabelWidth - qrSize - 3*labelPadding
+
+	for i, label := range labels {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+
+		column := i % layout.Columns
+		row := (i % perPage) / layout.Columns
+		x := layout.MarginLeft + float64(column)*(layout.LabelWidth+layout.GapX)
+		y := layout.MarginTop + float64(row)*(layout.LabelHeight+layout.GapY)
+
+		key := barcode.RegisterQR(pdf, label.URL, qr.M, qr.Auto)
+		barcode.Barcode(pdf, key, x+labelPadding, y+labelPadding, qrSize, qrSize, false)
+
+		textX := x + qrSize + 2*labelPadding
+		pdf.SetXY(textX, y+labelPadding)
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(textWidth, 5, fit(pdf, tr(label.Title), textWidth), "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(textWidth, 4, fit(pdf, tr(label.Subtitle), textWidth), "", 2, "L", false, 0, "")
+
+		pdf.SetXY(textX, y+layout.LabelHeight-labelPadding-4)
+		pdf.SetFont("Courier", "", 8)
+		pdf.CellFormat(textWidth, 4, label.Code, "", 0, "L", false, 0, "")
+	}
+
+	if len(labels) == 0 {
+		pdf.AddPage()
+	}
+
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("failed to write label sheet: %w", err)
+	}
+
+	return nil
+}
+
+// fit cuts already translated single byte text down to what fits on one line of the label.
+func fit(pdf *fpdf.Fpdf, text string, width float64) string {
+	for len(text) > 0 && pdf.GetStringWidth(text) > width {
+		text = text[:len(text)-1]
+	}
+
+	return text
+}
@@ -0,0 +1,94 @@
+package label
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+// SQLiteLabelCodeRepository stores label codes in SQLite for single-node deployments.
+type SQLiteLabelCodeRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteLabelCodeRepository(db *sql.DB) *SQLiteLabelCodeRepository {
+	return &SQLiteLabelCodeRepository{db: db}
+}
+
+func (r SQLiteLabelCodeRepository) InsertLabelCode(ctx context.Context, model LabelCodeModel) (bool, error) {
+	var roomID interface{}
+	if model.RoomID != (gocql.UUID{}) {
+		roomID = model.RoomID.String()
+	}
+
+	result, err := r.db.ExecContext(ctx, "INSERT OR IGNORE INTO label_codes (code, user_id, entity_type, household_id, room_id) VALUES (?, ?, ?, ?, ?)", model.Code, model.UserID, model.EntityType, model.HouseholdID.String(), roomID)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert label code: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to insert label code: %w", err)
+	}
+
+	return inserted > 0, nil
+}
+
+func (r SQLiteLabelCodeRepository) GetLabelCode(ctx context.Context, code string) (LabelCodeModel, bool, error) {
+	var householdId string
+	var roomId sql.NullString
+	model := LabelCodeModel{Code: code}
+	if err := r.db.QueryRowContext(ctx, "SELECT user_id, entity_type, household_id, room_id FROM label_codes WHERE code = ?", code).Scan(&model.UserID, &model.EntityType, &householdId, &roomId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LabelCodeModel{}, false, nil
+		}
+
+		return LabelCodeModel{}, false, fmt.Errorf("failed to get label code: %w", err)
+	}
+
+	var err error
+	if model.HouseholdID, err = gocql.ParseUUID(householdId); err != nil {
+		return LabelCodeModel{}, false, fmt.Errorf("invalid household ID: %s", householdId)
+	}
+
+	if roomId.Valid {
+		if model.RoomID, err = gocql.ParseUUID(roomId.String); err != nil {
+			return LabelCodeModel{}, false, fmt.Errorf("invalid room ID: %s", roomId.String)
+		}
+	}
+
+	return model, true, nil
+}
+
+func (r SQLiteLabelCodeRepository) GetUserLabelCodes(ctx context.Context, userId string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT code FROM label_codes WHERE user_id = ?", userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user label codes: %w", err)
+	}
+	defer rows.Close()
+
+	codes := make([]string, 0)
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, fmt.Errorf("failed to scan label code: %w", err)
+		}
+
+		codes = append(codes, code)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get user label codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+func (r SQLiteLabelCodeRepository) DeleteUserLabelCodes(ctx context.Context, userId string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM label_codes WHERE user_id = ?", userId)
+
+	return err
+}
@@ -0,0 +1,33 @@
+package taxonomy
+
+import "github.com/gocql/gocql"
+
+type UserCategoryModel struct {
+	UserID     string
+	CategoryID gocql.UUID
+	ParentID   gocql.UUID
+	Name       string
+	Timestamp  int64
+	// Version is the taxonomy aggregate version last projected onto the row
+	Version uint
+}
+
+type UserTagModel struct {
+	UserID    string
+	TagID     gocql.UUID
+	Name      string
+	Timestamp int64
+	// Version is the taxonomy aggregate version last projected onto the row
+	Version uint
+}
+
+type UserCategoryFieldModel struct {
+	UserID     string
+	CategoryID gocql.UUID
+	FieldID    gocql.UUID
+	Name       string
+	Type       string
+	Options    []string
+	Required   bool
+	Timestamp  int64
+}
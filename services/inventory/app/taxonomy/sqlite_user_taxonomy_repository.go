@@ -0,0 +1,279 @@
+package taxonomy
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+// SQLiteUserTaxonomyRepository stores the category and tag read models in SQLite for single-node deployments.
+type SQLiteUserTaxonomyRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteUserTaxonomyRepository(db *sql.DB) *SQLiteUserTaxonomyRepository {
+	return &SQLiteUserTaxonomyRepository{db: db}
+}
+
+func (r SQLiteUserTaxonomyRepository) InsertCategory(ctx context.Context, model UserCategoryModel) error {
+	var parentID interface{}
+	if model.ParentID != (gocql.UUID{}) {
+		parentID = model.ParentID.String()
+	}
+
+	_, err := r.db.ExecContext(ctx, "INSERT OR REPLACE INTO user_categories (user_id, category_id, parent_id, name, tstamp, version) VALUES (?, ?, ?, ?, ?, ?)", model.UserID, model.CategoryID.String(), parentID, model.Name, model.Timestamp, model.Version)
+
+	return err
+}
+
+func (r SQLiteUserTaxonomyRepository) GetCategoryVersion(ctx context.Context, userId string, categoryId string) (uint, bool, error) {
+	categoryUUID, err := gocql.ParseUUID(categoryId)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid category ID: %s", categoryId)
+	}
+
+	return r.getVersion(ctx, "SELECT version FROM user_categories WHERE user_id = ? AND category_id = ?", userId, categoryUUID.String())
+}
+
+func (r SQLiteUserTaxonomyRepository) RenameCategory(ctx context.Context, userId string, categoryId string, name string, timestamp int64, version uint) error {
+	categoryUUID, err := gocql.ParseUUID(categoryId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", categoryId)
+	}
+
+	_, err = r.db.ExecContext(ctx, "UPDATE user_categories SET name = ?, tstamp = ?, version = ? WHERE user_id = ? AND category_id = ?", name, timestamp, version, userId, categoryUUID.String())
+
+	return err
+}
+
+// ReparentCategories moves all direct children of a category under a new parent.
+func (r SQLiteUserTaxonomyRepository) ReparentCategories(ctx context.Context, userId string, parentId string, newParentId string, version uint) error {
+	parentUUID, err := gocql.ParseUUID(parentId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", parentId)
+	}
+
+	newParentUUID, err := gocql.ParseUUID(newParentId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", newParentId)
+	}
+
+	_, err = r.db.ExecContext(ctx, "UPDATE user_categories SET parent_id = ?, version = ? WHERE user_id = ? AND parent_id = ?", newParentUUID.String(), version, userId, parentUUID.String())
+
+	return err
+}
+
+func (r SQLiteUserTaxonomyRepository) GetUserCategories(ctx context.Context, userId string) ([]UserCategoryModel, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT category_id, parent_id, name, tstamp FROM user_categories WHERE user_id = ?", userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := make([]UserCategoryModel, 0)
+	for rows.Next() {
+		var categoryId string
+		var parentId sql.NullString
+		category := UserCategoryModel{UserID: userId}
+		if err := rows.Scan(&categoryId, &parentId, &category.Name, &category.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan user category: %w", err)
+		}
+
+		if category.CategoryID, err = gocql.ParseUUID(categoryId); err != nil {
+			return nil, fmt.Errorf("invalid category ID: %s", categoryId)
+		}
+
+		if parentId.Valid {
+			if category.ParentID, err = gocql.ParseUUID(parentId.String); err != nil {
+				return nil, fmt.Errorf("invalid category ID: %s", parentId.String)
+			}
+		}
+
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get user categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (r SQLiteUserTaxonomyRepository) DeleteCategory(ctx context.Context, userId string, categoryId string) error {
+	categoryUUID, err := gocql.ParseUUID(categoryId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", categoryId)
+	}
+
+	_, err = r.db.ExecContext(ctx, "DELETE FROM user_categories WHERE user_id = ? AND category_id = ?", userId, categoryUUID.String())
+
+	return err
+}
+
+func (r SQLiteUserTaxonomyRepository) InsertCategoryField(ctx context.Context, model UserCategoryFieldModel) error {
+	options, err := json.Marshal(model.Options)
+	if err != nil {
+		return fmt.Errorf("failed to encode field options: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, "INSERT OR REPLACE INTO user_category_fields (user_id, category_id, field_id, name, field_type, options, required, tstamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", model.UserID, model.CategoryID.String(), model.FieldID.String(), model.Name, model.Type, string(options), model.Required, model.Timestamp)
+
+	return err
+}
+
+func (r SQLiteUserTaxonomyRepository) GetUserCategoryFields(ctx context.Context, userId string) ([]UserCategoryFieldModel, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT category_id, field_id, name, field_type, options, required, tstamp FROM user_category_fields WHERE user_id = ?", userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user category fields: %w", err)
+	}
+	defer rows.Close()
+
+	fields := make([]UserCategoryFieldModel, 0)
+	for rows.Next() {
+		var categoryId, fieldId, options string
+		field := UserCategoryFieldModel{UserID: userId}
+		if err := rows.Scan(&categoryId, &fieldId, &field.Name, &field.Type, &options, &field.Required, &field.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan user category field: %w", err)
+		}
+
+		if field.CategoryID, err = gocql.ParseUUID(categoryId); err != nil {
+			return nil, fmt.Errorf("invalid category ID: %s", categoryId)
+		}
+
+		if field.FieldID, err = gocql.ParseUUID(fieldId); err != nil {
+			return nil, fmt.Errorf("invalid field ID: %s", fieldId)
+		}
+
+		if err := json.Unmarshal([]byte(options), &field.Options); err != nil {
+			return nil, fmt.Errorf("failed to decode field options: %w", err)
+		}
+
+		fields = append(fields, field)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get user category fields: %w", err)
+	}
+
+	return fields, nil
+}
+
+func (r SQLiteUserTaxonomyRepository) DeleteCategoryField(ctx context.Context, userId string, categoryId string, fieldId string) error {
+	categoryUUID, err := gocql.ParseUUID(categoryId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", categoryId)
+	}
+
+	fieldUUID, err := gocql.ParseUUID(fieldId)
+	if err != nil {
+		return fmt.Errorf("invalid field ID: %s", fieldId)
+	}
+
+	_, err = r.db.ExecContext(ctx, "DELETE FROM user_category_fields WHERE user_id = ? AND category_id = ? AND field_id = ?", userId, categoryUUID.String(), fieldUUID.String())
+
+	return err
+}
+
+func (r SQLiteUserTaxonomyRepository) DeleteCategoryFields(ctx context.Context, userId string, categoryId string) error {
+	categoryUUID, err := gocql.ParseUUID(categoryId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", categoryId)
+	}
+
+	_, err = r.db.ExecContext(ctx, "DELETE FROM user_category_fields WHERE user_id = ? AND category_id = ?", userId, categoryUUID.String())
+
+	return err
+}
+
+func (r SQLiteUserTaxonomyRepository) InsertTag(ctx context.Context, model UserTagModel) error {
+	_, err := r.db.ExecContext(ctx, "INSERT OR REPLACE INTO user_tags (user_id, tag_id, name, tstamp, version) VALUES (?, ?, ?, ?, ?)", model.UserID, model.TagID.String(), model.Name, model.Timestamp, model.Version)
+
+	return err
+}
+
+func (r SQLiteUserTaxonomyRepository) GetTagVersion(ctx context.Context, userId string, tagId string) (uint, bool, error) {
+	tagUUID, err := gocql.ParseUUID(tagId)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid tag ID: %s", tagId)
+	}
+
+	return r.getVersion(ctx, "SELECT version FROM user_tags WHERE user_id = ? AND tag_id = ?", userId, tagUUID.String())
+}
+
+func (r SQLiteUserTaxonomyRepository) RenameTag(ctx context.Context, userId string, tagId string, name string, timestamp int64, version uint) error {
+	tagUUID, err := gocql.ParseUUID(tagId)
+	if err != nil {
+		return fmt.Errorf("invalid tag ID: %s", tagId)
+	}
+
+	_, err = r.db.ExecContext(ctx, "UPDATE user_tags SET name = ?, tstamp = ?, version = ? WHERE user_id = ? AND tag_id = ?", name, timestamp, version, userId, tagUUID.String())
+
+	return err
+}
+
+func (r SQLiteUserTaxonomyRepository) GetUserTags(ctx context.Context, userId string) ([]UserTagModel, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT tag_id, name, tstamp FROM user_tags WHERE user_id = ?", userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]UserTagModel, 0)
+	for rows.Next() {
+		var tagId string
+		tag := UserTagModel{UserID: userId}
+		if err := rows.Scan(&tagId, &tag.Name, &tag.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan user tag: %w", err)
+		}
+
+		if tag.TagID, err = gocql.ParseUUID(tagId); err != nil {
+			return nil, fmt.Errorf("invalid tag ID: %s", tagId)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get user tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+func (r SQLiteUserTaxonomyRepository) DeleteTag(ctx context.Context, userId string, tagId string) error {
+	tagUUID, err := gocql.ParseUUID(tagId)
+	if err != nil {
+		return fmt.Errorf("invalid tag ID: %s", tagId)
+	}
+
+	_, err = r.db.ExecContext(ctx, "DELETE FROM user_tags WHERE user_id = ? AND tag_id = ?", userId, tagUUID.String())
+
+	return err
+}
+
+func (r SQLiteUserTaxonomyRepository) DeleteUserTaxonomy(ctx context.Context, userId string) error {
+	for _, table := range []string{"user_categories", "user_category_fields", "user_tags"} {
+		if _, err := r.db.ExecContext(ctx, "DELETE FROM "+table+" WHERE user_id = ?", userId); err != nil {
+			return fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+func (r SQLiteUserTaxonomyRepository) getVersion(ctx context.Context, query string, args ...interface{}) (uint, bool, error) {
+	var version uint
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("failed to get version: %w", err)
+	}
+
+	return version, true, nil
+}
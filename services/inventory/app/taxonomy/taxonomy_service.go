@@ -0,0 +1,210 @@
+package taxonomy
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/bnkamalesh/errors"
+	"github.com/cybre/home-inventory/services/inventory/app/common"
+	"github.com/cybre/home-inventory/services/inventory/domain/taxonomy"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/gocql/gocql"
+)
+
+type UserTaxonomyRepo interface {
+	GetUserCategories(ctx context.Context, userID string) ([]UserCategoryModel, error)
+	GetUserCategoryFields(ctx context.Context, userID string) ([]UserCategoryFieldModel, error)
+	GetUserTags(ctx context.Context, userID string) ([]UserTagModel, error)
+}
+
+type TaxonomyService struct {
+	commandBus common.CommandBus
+	repository UserTaxonomyRepo
+}
+
+func NewTaxonomyService(commandBus common.CommandBus, repository UserTaxonomyRepo) *TaxonomyService {
+	return &TaxonomyService{
+		commandBus: commandBus,
+		repository: repository,
+	}
+}
+
+func (s TaxonomyService) CreateCategory(ctx context.Context, data shared.CreateCategoryCommandData) error {
+	return s.commandBus.Dispatch(ctx, taxonomy.CreateCategoryCommand{
+		UserID:     data.UserID,
+		CategoryID: data.CategoryID,
+		ParentID:   data.ParentID,
+		Name:       data.Name,
+	})
+}
+
+func (s TaxonomyService) RenameCategory(ctx context.Context, data shared.RenameCategoryCommandData) error {
+	return s.commandBus.Dispatch(ctx, taxonomy.RenameCategoryCommand{
+		UserID:     data.UserID,
+		CategoryID: data.CategoryID,
+		Name:       data.Name,
+	})
+}
+
+func (s TaxonomyService) MergeCategory(ctx context.Context, data shared.MergeCategoryCommandData) error {
+	return s.commandBus.Dispatch(ctx, taxonomy.MergeCategoryCommand{
+		UserID:           data.UserID,
+		CategoryID:       data.CategoryID,
+		TargetCategoryID: data.TargetCategoryID,
+	})
+}
+
+func (s TaxonomyService) DeleteCategory(ctx context.Context, data shared.DeleteCategoryCommandData) error {
+	return s.commandBus.Dispatch(ctx, taxonomy.DeleteCategoryCommand{
+		UserID:     data.UserID,
+		CategoryID: data.CategoryID,
+	})
+}
+
+func (s TaxonomyService) AddCategoryField(ctx context.Context, data shared.AddCategoryFieldCommandData) error {
+	return s.commandBus.Dispatch(ctx, taxonomy.AddCategoryFieldCommand{
+		UserID:     data.UserID,
+		CategoryID: data.CategoryID,
+		FieldID:    data.FieldID,
+		Name:       data.Name,
+		Type:       data.Type,
+		Options:    data.Options,
+		Required:   data.Required,
+	})
+}
+
+func (s TaxonomyService) RemoveCategoryField(ctx context.Context, data shared.RemoveCategoryFieldCommandData) error {
+	return s.commandBus.Dispatch(ctx, taxonomy.RemoveCategoryFieldCommand{
+		UserID:     data.UserID,
+		CategoryID: data.CategoryID,
+		FieldID:    data.FieldID,
+	})
+}
+
+func (s TaxonomyService) CreateTag(ctx context.Context, data shared.CreateTagCommandData) error {
+	return s.commandBus.Dispatch(ctx, taxonomy.CreateTagCommand{
+		UserID: data.UserID,
+		TagID:  data.TagID,
+		Name:   data.Name,
+	})
+}
+
+func (s TaxonomyService) RenameTag(ctx context.Context, data shared.RenameTagCommandData) error {
+	return s.commandBus.Dispatch(ctx, taxonomy.RenameTagCommand{
+		UserID: data.UserID,
+		TagID:  data.TagID,
+		Name:   data.Name,
+	})
+}
+
+func (s TaxonomyService) MergeTag(ctx context.Context, data shared.MergeTagCommandData) error {
+	return s.commandBus.Dispatch(ctx, taxonomy.MergeTagCommand{
+		UserID:      data.UserID,
+		TagID:       data.TagID,
+		TargetTagID: data.TargetTagID,
+	})
+}
+
+func (s TaxonomyService) DeleteTag(ctx context.Context, data shared.DeleteTagCommandData) error {
+	return s.commandBus.Dispatch(ctx, taxonomy.DeleteTagCommand{
+		UserID: data.UserID,
+		TagID:  data.TagID,
+	})
+}
+
+// GetUserCategories returns the categories of a user ordered depth-first, so that every category
+// directly follows its parent and siblings are sorted by name. Fields are listed in the order they were added.
+func (s TaxonomyService) GetUserCategories(ctx context.Context, userID string) ([]shared.UserCategory, error) {
+	categories, err := s.repository.GetUserCategories(ctx, userID)
+	if err != nil {
+		return nil, errors.InternalErr(err, "failed to get user categories")
+	}
+
+	categoryFields, err := s.repository.GetUserCategoryFields(ctx, userID)
+	if err != nil {
+		return nil, errors.InternalErr(err, "failed to get user category fields")
+	}
+
+	slices.SortStableFunc(categoryFields, func(a, b UserCategoryFieldModel) int {
+		return cmp.Compare(a.Timestamp, b.Timestamp)
+	})
+
+	fields := map[gocql.UUID][]shared.UserCategoryField{}
+	for _, field := range categoryFields {
+		fields[field.CategoryID] = append(fields[field.CategoryID], shared.UserCategoryField{
+			FieldID:   field.FieldID.String(),
+			Name:      field.Name,
+			Type:      field.Type,
+			Options:   field.Options,
+			Required:  field.Required,
+			Timestamp: field.Timestamp,
+		})
+	}
+
+	children := map[gocql.UUID][]UserCategoryModel{}
+	for _, category := range categories {
+		children[category.ParentID] = append(children[category.ParentID], category)
+	}
+
+	for _, siblings := range children {
+		slices.SortFunc(siblings, func(a, b UserCategoryModel) int {
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+	}
+
+	sharedCategories := make([]shared.UserCategory, 0, len(categories))
+	var walk func(parentID gocql.UUID, depth uint)
+	walk = func(parentID gocql.UUID, depth uint) {
+		for _, category := range children[parentID] {
+			sharedCategory := toSharedUserCategory(category, depth)
+			if categoryFields, ok := fields[category.CategoryID]; ok {
+				sharedCategory.Fields = categoryFields
+			}
+			sharedCategories = append(sharedCategories, sharedCategory)
+			walk(category.CategoryID, depth+1)
+		}
+	}
+	walk(gocql.UUID{}, 0)
+
+	return sharedCategories, nil
+}
+
+func (s TaxonomyService) GetUserTags(ctx context.Context, userID string) ([]shared.UserTag, error) {
+	tags, err := s.repository.GetUserTags(ctx, userID)
+	if err != nil {
+		return nil, errors.InternalErr(err, "failed to get user tags")
+	}
+
+	slices.SortFunc(tags, func(a, b UserTagModel) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	sharedTags := make([]shared.UserTag, len(tags))
+	for i, tag := range tags {
+		sharedTags[i] = shared.UserTag{
+			TagID:     tag.TagID.String(),
+			Name:      tag.Name,
+			Timestamp: tag.Timestamp,
+		}
+	}
+
+	return sharedTags, nil
+}
+
+func toSharedUserCategory(category UserCategoryModel, depth uint) shared.UserCategory {
+	var parentID string
+	if category.ParentID != (gocql.UUID{}) {
+		parentID = category.ParentID.String()
+	}
+
+	return shared.UserCategory{
+		CategoryID: category.CategoryID.String(),
+		ParentID:   parentID,
+		Name:       category.Name,
+		Depth:      depth,
+		Timestamp:  category.Timestamp,
+		Fields:     []shared.UserCategoryField{},
+	}
+}
@@ -0,0 +1,311 @@
+package taxonomy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/services/inventory/domain/taxonomy"
+	"github.com/gocql/gocql"
+)
+
+type TaxonomyRepo interface {
+	InsertCategory(ctx context.Context, model UserCategoryModel) error
+	GetCategoryVersion(ctx context.Context, userId string, categoryId string) (uint, bool, error)
+	RenameCategory(ctx context.Context, userId string, categoryId string, name string, timestamp int64, version uint) error
+	ReparentCategories(ctx context.Context, userId string, parentId string, newParentId string, version uint) error
+	DeleteCategory(ctx context.Context, userId string, categoryId string) error
+
+	InsertCategoryField(ctx context.Context, model UserCategoryFieldModel) error
+	DeleteCategoryField(ctx context.Context, userId string, categoryId string, fieldId string) error
+	DeleteCategoryFields(ctx context.Context, userId string, categoryId string) error
+
+	InsertTag(ctx context.Context, model UserTagModel) error
+	GetTagVersion(ctx context.Context, userId string, tagId string) (uint, bool, error)
+	RenameTag(ctx context.Context, userId string, tagId string, name string, timestamp int64, version uint) error
+	DeleteTag(ctx context.Context, userId string, tagId string) error
+
+	DeleteUserTaxonomy(ctx context.Context, userId string) error
+}
+
+// UserTaxonomyProjector keeps the category and tag read models. Category and tag rows record the taxonomy
+// version they last applied, so events delivered again are skipped instead of rolling the rows back.
+type UserTaxonomyProjector struct {
+	repository TaxonomyRepo
+}
+
+func NewUserTaxonomyProjector(repository TaxonomyRepo) *UserTaxonomyProjector {
+	return &UserTaxonomyProjector{
+		repository: repository,
+	}
+}
+
+func (p UserTaxonomyProjector) HandleEvent(ctx context.Context, event es.Event) error {
+	switch e := event.Data.(type) {
+	case taxonomy.CategoryCreatedEvent:
+		return p.handleCategoryCreatedEvent(ctx, e, event.Version)
+	case taxonomy.CategoryRenamedEvent:
+		return p.handleCategoryRenamedEvent(ctx, e, event.Version)
+	case taxonomy.CategoryMergedEvent:
+		return p.handleCategoryMergedEvent(ctx, e, event.Version)
+	case taxonomy.CategoryDeletedEvent:
+		return p.handleCategoryDeletedEvent(ctx, e, event.Version)
+	case taxonomy.CategoryFieldAddedEvent:
+		return p.handleCategoryFieldAddedEvent(ctx, e)
+	case taxonomy.CategoryFieldRemovedEvent:
+		return p.handleCategoryFieldRemovedEvent(ctx, e)
+	case taxonomy.TagCreatedEvent:
+		return p.handleTagCreatedEvent(ctx, e, event.Version)
+	case taxonomy.TagRenamedEvent:
+		return p.handleTagRenamedEvent(ctx, e, event.Version)
+	case taxonomy.TagMergedEvent:
+		return p.handleTagMergedEvent(ctx, e, event.Version)
+	case taxonomy.TagDeletedEvent:
+		return p.handleTagDeletedEvent(ctx, e, event.Version)
+	case taxonomy.TaxonomyClearedEvent:
+		return p.handleTaxonomyClearedEvent(ctx, e)
+	default:
+		return es.ErrUnknownEvent
+	}
+}
+
+func (p UserTaxonomyProjector) Events() []es.EventType {
+	return []es.EventType{
+		taxonomy.EventTypeCategoryCreated,
+		taxonomy.EventTypeCategoryRenamed,
+		taxonomy.EventTypeCategoryMerged,
+		taxonomy.EventTypeCategoryDeleted,
+		taxonomy.EventTypeCategoryFieldAdded,
+		taxonomy.EventTypeCategoryFieldRemoved,
+		taxonomy.EventTypeTagCreated,
+		taxonomy.EventTypeTagRenamed,
+		taxonomy.EventTypeTagMerged,
+		taxonomy.EventTypeTagDeleted,
+		taxonomy.EventTypeTaxonomyCleared,
+	}
+}
+
+func (p UserTaxonomyProjector) Name() string {
+	return "taxonomy.UserTaxonomyProjector"
+}
+
+func (p UserTaxonomyProjector) handleCategoryCreatedEvent(ctx context.Context, e taxonomy.CategoryCreatedEvent, version uint) error {
+	if apply, err := p.categoryApplies(ctx, e.UserID, e.CategoryID, version, true); err != nil || !apply {
+		return err
+	}
+
+	categoryUUID, err := gocql.ParseUUID(e.CategoryID)
+	if err != nil {
+		return fmt.Errorf("failed to parse category ID: %w", err)
+	}
+
+	var parentUUID gocql.UUID
+	if e.ParentID != "" {
+		if parentUUID, err = gocql.ParseUUID(e.ParentID); err != nil {
+			return fmt.Errorf("failed to parse parent category ID: %w", err)
+		}
+	}
+
+	if err := p.repository.InsertCategory(ctx, UserCategoryModel{
+		UserID:     e.UserID,
+		CategoryID: categoryUUID,
+		ParentID:   parentUUID,
+		Name:       e.Name,
+		Timestamp:  e.Timestamp,
+		Version:    version,
+	}); err != nil {
+		return fmt.Errorf("failed to insert category: %w", err)
+	}
+
+	return nil
+}
+
+func (p UserTaxonomyProjector) handleCategoryRenamedEvent(ctx context.Context, e taxonomy.CategoryRenamedEvent, version uint) error {
+	if apply, err := p.categoryApplies(ctx, e.UserID, e.CategoryID, version, false); err != nil || !apply {
+		return err
+	}
+
+	if err := p.repository.RenameCategory(ctx, e.UserID, e.CategoryID, e.Name, e.Timestamp, version); err != nil {
+		return fmt.Errorf("failed to rename category: %w", err)
+	}
+
+	return nil
+}
+
+func (p UserTaxonomyProjector) handleCategoryMergedEvent(ctx context.Context, e taxonomy.CategoryMergedEvent, version uint) error {
+	if apply, err := p.categoryApplies(ctx, e.UserID, e.CategoryID, version, false); err != nil || !apply {
+		return err
+	}
+
+	if err := p.repository.ReparentCategories(ctx, e.UserID, e.CategoryID, e.TargetCategoryID, version); err != nil {
+		return fmt.Errorf("failed to move merged category children: %w", err)
+	}
+
+	if err := p.repository.DeleteCategoryFields(ctx, e.UserID, e.CategoryID); err != nil {
+		return fmt.Errorf("failed to delete merged category fields: %w", err)
+	}
+
+	if err := p.repository.DeleteCategory(ctx, e.UserID, e.CategoryID); err != nil {
+		return fmt.Errorf("failed to delete merged category: %w", err)
+	}
+
+	return nil
+}
+
+func (p UserTaxonomyProjector) handleCategoryDeletedEvent(ctx context.Context, e taxonomy.CategoryDeletedEvent, version uint) error {
+	if apply, err := p.categoryApplies(ctx, e.UserID, e.CategoryID, version, false); err != nil || !apply {
+		return err
+	}
+
+	if err := p.repository.DeleteCategoryFields(ctx, e.UserID, e.CategoryID); err != nil {
+		return fmt.Errorf("failed to delete category fields: %w", err)
+	}
+
+	if err := p.repository.DeleteCategory(ctx, e.UserID, e.CategoryID); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	return nil
+}
+
+func (p UserTaxonomyProjector) handleCategoryFieldAddedEvent(ctx context.Context, e taxonomy.CategoryFieldAddedEvent) error {
+	// Fields of a category deleted by a later event are not brought back
+	if _, found, err := p.repository.GetCategoryVersion(ctx, e.UserID, e.CategoryID); err != nil || !found {
+		return err
+	}
+
+	categoryUUID, err := gocql.ParseUUID(e.CategoryID)
+	if err != nil {
+		return fmt.Errorf("failed to parse category ID: %w", err)
+	}
+
+	fieldUUID, err := gocql.ParseUUID(e.FieldID)
+	if err != nil {
+		return fmt.Errorf("failed to parse field ID: %w", err)
+	}
+
+	var options []string
+	if e.Options != "" {
+		options = strings.Split(e.Options, "\n")
+	}
+
+	if err := p.repository.InsertCategoryField(ctx, UserCategoryFieldModel{
+		UserID:     e.UserID,
+		CategoryID: categoryUUID,
+		FieldID:    fieldUUID,
+		Name:       e.Name,
+		Type:       e.Type,
+		Options:    options,
+		Required:   e.Required,
+		Timestamp:  e.Timestamp,
+	}); err != nil {
+		return fmt.Errorf("failed to insert category field: %w", err)
+	}
+
+	return nil
+}
+
+func (p UserTaxonomyProjector) handleCategoryFieldRemovedEvent(ctx context.Context, e taxonomy.CategoryFieldRemovedEvent) error {
+	if err := p.repository.DeleteCategoryField(ctx, e.UserID, e.CategoryID, e.FieldID); err != nil {
+		return fmt.Errorf("failed to delete category field: %w", err)
+	}
+
+	return nil
+}
+
+func (p UserTaxonomyProjector) handleTagCreatedEvent(ctx context.Context, e taxonomy.TagCreatedEvent, version uint) error {
+	if apply, err := p.tagApplies(ctx, e.UserID, e.TagID, version, true); err != nil || !apply {
+		return err
+	}
+
+	tagUUID, err := gocql.ParseUUID(e.TagID)
+	if err != nil {
+		return fmt.Errorf("failed to parse tag ID: %w", err)
+	}
+
+	if err := p.repository.InsertTag(ctx, UserTagModel{
+		UserID:    e.UserID,
+		TagID:     tagUUID,
+		Name:      e.Name,
+		Timestamp: e.Timestamp,
+		Version:   version,
+	}); err != nil {
+		return fmt.Errorf("failed to insert tag: %w", err)
+	}
+
+	return nil
+}
+
+func (p UserTaxonomyProjector) handleTagRenamedEvent(ctx context.Context, e taxonomy.TagRenamedEvent, version uint) error {
+	if apply, err := p.tagApplies(ctx, e.UserID, e.TagID, version, false); err != nil || !apply {
+		return err
+	}
+
+	if err := p.repository.RenameTag(ctx, e.UserID, e.TagID, e.Name, e.Timestamp, version); err != nil {
+		return fmt.Errorf("failed to rename tag: %w", err)
+	}
+
+	return nil
+}
+
+func (p UserTaxonomyProjector) handleTagMergedEvent(ctx context.Context, e taxonomy.TagMergedEvent, version uint) error {
+	if apply, err := p.tagApplies(ctx, e.UserID, e.TagID, version, false); err != nil || !apply {
+		return err
+	}
+
+	if err := p.repository.DeleteTag(ctx, e.UserID, e.TagID); err != nil {
+		return fmt.Errorf("failed to delete merged tag: %w", err)
+	}
+
+	return nil
+}
+
+func (p UserTaxonomyProjector) handleTagDeletedEvent(ctx context.Context, e taxonomy.TagDeletedEvent, version uint) error {
+	if apply, err := p.tagApplies(ctx, e.UserID, e.TagID, version, false); err != nil || !apply {
+		return err
+	}
+
+	if err := p.repository.DeleteTag(ctx, e.UserID, e.TagID); err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return nil
+}
+
+func (p UserTaxonomyProjector) handleTaxonomyClearedEvent(ctx context.Context, e taxonomy.TaxonomyClearedEvent) error {
+	if err := p.repository.DeleteUserTaxonomy(ctx, e.UserID); err != nil {
+		return fmt.Errorf("failed to delete user taxonomy: %w", err)
+	}
+
+	return nil
+}
+
+// categoryApplies reports whether an event at the given taxonomy version still has to be projected onto a category.
+// The row must not have seen that version yet, and has to exist unless the event creates it.
+func (p UserTaxonomyProjector) categoryApplies(ctx context.Context, userID, categoryID string, version uint, creates bool) (bool, error) {
+	appliedVersion, found, err := p.repository.GetCategoryVersion(ctx, userID, categoryID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get category version: %w", err)
+	}
+
+	if !found {
+		return creates, nil
+	}
+
+	return version > appliedVersion, nil
+}
+
+// tagApplies is categoryApplies for tags.
+func (p UserTaxonomyProjector) tagApplies(ctx context.Context, userID, tagID string, version uint, creates bool) (bool, error) {
+	appliedVersion, found, err := p.repository.GetTagVersion(ctx, userID, tagID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get tag version: %w", err)
+	}
+
+	if !found {
+		return creates, nil
+	}
+
+	return version > appliedVersion, nil
+}
@@ -0,0 +1,252 @@
+package taxonomy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gocql/gocql"
+)
+
+type UserTaxonomyRepository struct {
+	db *gocql.Session
+}
+
+func NewUserTaxonomyRepository(db *gocql.Session) *UserTaxonomyRepository {
+	return &UserTaxonomyRepository{db: db}
+}
+
+func (r UserTaxonomyRepository) InsertCategory(ctx context.Context, model UserCategoryModel) error {
+	var parentID interface{}
+	if model.ParentID != (gocql.UUID{}) {
+		parentID = model.ParentID
+	}
+
+	return r.db.Query("INSERT INTO user_categories (user_id, category_id, parent_id, name, tstamp, version) VALUES (?, ?, ?, ?, ?, ?)", model.UserID, model.CategoryID, parentID, model.Name, model.Timestamp, model.Version).WithContext(ctx).Exec()
+}
+
+// GetCategoryVersion returns the taxonomy version last projected onto the category row.
+// Rows projected before versions were recorded report version 0.
+func (r UserTaxonomyRepository) GetCategoryVersion(ctx context.Context, userId string, categoryId string) (uint, bool, error) {
+	categoryUUID, err := gocql.ParseUUID(categoryId)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid category ID: %s", categoryId)
+	}
+
+	var version uint
+	if err := r.db.Query("SELECT version FROM user_categories WHERE user_id = ? AND category_id = ?", userId, categoryUUID).WithContext(ctx).Scan(&version); err != nil {
+		if err == gocql.ErrNotFound {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("failed to get category version: %w", err)
+	}
+
+	return version, true, nil
+}
+
+func (r UserTaxonomyRepository) RenameCategory(ctx context.Context, userId string, categoryId string, name string, timestamp int64, version uint) error {
+	categoryUUID, err := gocql.ParseUUID(categoryId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", categoryId)
+	}
+
+	return r.db.Query("UPDATE user_categories SET name = ?, tstamp = ?, version = ? WHERE user_id = ? AND category_id = ?", name, timestamp, version, userId, categoryUUID).WithContext(ctx).Exec()
+}
+
+// ReparentCategories moves all direct children of a category under a new parent.
+func (r UserTaxonomyRepository) ReparentCategories(ctx context.Context, userId string, parentId string, newParentId string, version uint) error {
+	parentUUID, err := gocql.ParseUUID(parentId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", parentId)
+	}
+
+	newParentUUID, err := gocql.ParseUUID(newParentId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", newParentId)
+	}
+
+	categories, err := r.GetUserCategories(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, category := range categories {
+		if category.ParentID != parentUUID {
+			continue
+		}
+
+		if err := r.db.Query("UPDATE user_categories SET parent_id = ?, version = ? WHERE user_id = ? AND category_id = ?", newParentUUID, version, userId, category.CategoryID).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("failed to reparent category %s: %w", category.CategoryID, err)
+		}
+	}
+
+	return nil
+}
+
+func (r UserTaxonomyRepository) GetUserCategories(ctx context.Context, userId string) ([]UserCategoryModel, error) {
+	var categoryId, parentId gocql.UUID
+	var name string
+	var timestamp int64
+	iter := r.db.Query("SELECT category_id, parent_id, name, tstamp FROM user_categories WHERE user_id = ?", userId).WithContext(ctx).Iter()
+	defer iter.Close()
+
+	categories := make([]UserCategoryModel, 0)
+	for iter.Scan(&categoryId, &parentId, &name, &timestamp) {
+		categories = append(categories, UserCategoryModel{
+			UserID:     userId,
+			CategoryID: categoryId,
+			ParentID:   parentId,
+			Name:       name,
+			Timestamp:  timestamp,
+		})
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get user categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (r UserTaxonomyRepository) DeleteCategory(ctx context.Context, userId string, categoryId string) error {
+	categoryUUID, err := gocql.ParseUUID(categoryId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", categoryId)
+	}
+
+	return r.db.Query("DELETE FROM user_categories WHERE user_id = ? AND category_id = ?", userId, categoryUUID).WithContext(ctx).Exec()
+}
+
+func (r UserTaxonomyRepository) InsertCategoryField(ctx context.Context, model UserCategoryFieldModel) error {
+	return r.db.Query("INSERT INTO user_category_fields (user_id, category_id, field_id, name, field_type, options, required, tstamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", model.UserID, model.CategoryID, model.FieldID, model.Name, model.Type, model.Options, model.Required, model.Timestamp).WithContext(ctx).Exec()
+}
+
+func (r UserTaxonomyRepository) GetUserCategoryFields(ctx context.Context, userId string) ([]UserCategoryFieldModel, error) {
+	var categoryId, fieldId gocql.UUID
+	var name, fieldType string
+	var options []string
+	var required bool
+	var timestamp int64
+	iter := r.db.Query("SELECT category_id, field_id, name, field_type, options, required, tstamp FROM user_category_fields WHERE user_id = ?", userId).WithContext(ctx).Iter()
+	defer iter.Close()
+
+	fields := make([]UserCategoryFieldModel, 0)
+	for iter.Scan(&categoryId, &fieldId, &name, &fieldType, &options, &required, &timestamp) {
+		fields = append(fields, UserCategoryFieldModel{
+			UserID:     userId,
+			CategoryID: categoryId,
+			FieldID:    fieldId,
+			Name:       name,
+			Type:       fieldType,
+			Options:    options,
+			Required:   required,
+			Timestamp:  timestamp,
+		})
+		options = nil
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get user category fields: %w", err)
+	}
+
+	return fields, nil
+}
+
+func (r UserTaxonomyRepository) DeleteCategoryField(ctx context.Context, userId string, categoryId string, fieldId string) error {
+	categoryUUID, err := gocql.ParseUUID(categoryId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", categoryId)
+	}
+
+	fieldUUID, err := gocql.ParseUUID(fieldId)
+	if err != nil {
+		return fmt.Errorf("invalid field ID: %s", fieldId)
+	}
+
+	return r.db.Query("DELETE FROM user_category_fields WHERE user_id = ? AND category_id = ? AND field_id = ?", userId, categoryUUID, fieldUUID).WithContext(ctx).Exec()
+}
+
+func (r UserTaxonomyRepository) DeleteCategoryFields(ctx context.Context, userId string, categoryId string) error {
+	categoryUUID, err := gocql.ParseUUID(categoryId)
+	if err != nil {
+		return fmt.Errorf("invalid category ID: %s", categoryId)
+	}
+
+	return r.db.Query("DELETE FROM user_category_fields WHERE user_id = ? AND category_id = ?", userId, categoryUUID).WithContext(ctx).Exec()
+}
+
+func (r UserTaxonomyRepository) InsertTag(ctx context.Context, model UserTagModel) error {
+	return r.db.Query("INSERT INTO user_tags (user_id, tag_id, name, tstamp, version) VALUES (?, ?, ?, ?, ?)", model.UserID, model.TagID, model.Name, model.Timestamp, model.Version).WithContext(ctx).Exec()
+}
+
+// GetTagVersion returns the taxonomy version last projected onto the tag row.
+// Rows projected before versions were recorded report version 0.
+func (r UserTaxonomyRepository) GetTagVersion(ctx context.Context, userId string, tagId string) (uint, bool, error) {
+	tagUUID, err := gocql.ParseUUID(tagId)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid tag ID: %s", tagId)
+	}
+
+	var version uint
+	if err := r.db.Query("SELECT version FROM user_tags WHERE user_id = ? AND tag_id = ?", userId, tagUUID).WithContext(ctx).Scan(&version); err != nil {
+		if err == gocql.ErrNotFound {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("failed to get tag version: %w", err)
+	}
+
+	return version, true, nil
+}
+
+func (r UserTaxonomyRepository) RenameTag(ctx context.Context, userId string, tagId string, name string, timestamp int64, version uint) error {
+	tagUUID, err := gocql.ParseUUID(tagId)
+	if err != nil {
+		return fmt.Errorf("invalid tag ID: %s", tagId)
+	}
+
+	return r.db.Query("UPDATE user_tags SET name = ?, tstamp = ?, version = ? WHERE user_id = ? AND tag_id = ?", name, timestamp, version, userId, tagUUID).WithContext(ctx).Exec()
+}
+
+func (r UserTaxonomyRepository) GetUserTags(ctx context.Context, userId string) ([]UserTagModel, error) {
+	var tagId gocql.UUID
+	var name string
+	var timestamp int64
+	iter := r.db.Query("SELECT tag_id, name, tstamp FROM user_tags WHERE user_id = ?", userId).WithContext(ctx).Iter()
+	defer iter.Close()
+
+	tags := make([]UserTagModel, 0)
+	for iter.Scan(&tagId, &name, &timestamp) {
+		tags = append(tags, UserTagModel{
+			UserID:    userId,
+			TagID:     tagId,
+			Name:      name,
+			Timestamp: timestamp,
+		})
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to get user tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+func (r UserTaxonomyRepository) DeleteTag(ctx context.Context, userId string, tagId string) error {
+	tagUUID, err := gocql.ParseUUID(tagId)
+	if err != nil {
+		return fmt.Errorf("invalid tag ID: %s", tagId)
+	}
+
+	return r.db.Query("DELETE FROM user_tags WHERE user_id = ? AND tag_id = ?", userId, tagUUID).WithContext(ctx).Exec()
+}
+
+func (r UserTaxonomyRepository) DeleteUserTaxonomy(ctx context.Context, userId string) error {
+	for _, table := range []string{"user_categories", "user_category_fields", "user_tags"} {
+		if err := r.db.Query("DELETE FROM "+table+" WHERE user_id = ?", userId).WithContext(ctx).Exec(); err != nil {
+			return fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
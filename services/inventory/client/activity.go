@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/cybre/home-inventory/internal/requestbuilder"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+)
+
+func (c InventoryClient) GetHouseholdActivity(ctx context.Context, userID, householdID, cursor string) (shared.HouseholdActivityPage, error) {
+	request := requestbuilder.
+		New(http.MethodGet, c.address+shared.UserHouseholdActivityRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, userID).
+		WithPathParam(shared.UserHouseholdsHouseholdIDParam, householdID).
+		WithHeader("Accept", "application/json")
+	if cursor != "" {
+		request = request.WithQueryParam("cursor", cursor)
+	}
+
+	resp, err := request.WithRetry().Do(ctx)
+	if err != nil {
+		return shared.HouseholdActivityPage{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return shared.HouseholdActivityPage{}, propagateError(resp)
+	}
+
+	defer resp.Body.Close()
+
+	var page shared.HouseholdActivityPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return shared.HouseholdActivityPage{}, err
+	}
+
+	return page, nil
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/cybre/home-inventory/internal/requestbuilder"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+)
+
+func (c InventoryClient) LookupProduct(ctx context.Context, code string) (shared.CatalogProduct, error) {
+	resp, err := requestbuilder.
+		New(http.MethodGet, c.address+shared.CatalogProductRoute).
+		WithPathParam(shared.CatalogProductCodeParam, code).
+		WithHeader("Accept", "application/json").
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return shared.CatalogProduct{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return shared.CatalogProduct{}, propagateError(resp)
+	}
+
+	defer resp.Body.Close()
+
+	var product shared.CatalogProduct
+	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
+		return shared.CatalogProduct{}, err
+	}
+
+	return product, nil
+}
+
+type AddCatalogProductRequest struct {
+	Code     string `json:"-"`
+	Name     string `json:"name"`
+	Brand    string `json:"brand"`
+	Category string `json:"category"`
+}
+
+func (c InventoryClient) AddCatalogProduct(ctx context.Context, product AddCatalogProductRequest) error {
+	resp, err := requestbuilder.New(http.MethodPut, c.address+shared.CatalogProductRoute).
+		WithPathParam(shared.CatalogProductCodeParam, product.Code).
+		WithBody(product).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return propagateError(resp)
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/cybre/home-inventory/internal/cache"
+	"github.com/cybre/home-inventory/internal/health"
 	"github.com/cybre/home-inventory/internal/requestbuilder"
 	"github.com/cybre/home-inventory/services/inventory/shared"
 	"github.com/labstack/echo/v4"
@@ -329,6 +330,121 @@ func (c InventoryClient) GetUserHouseholdRoom(ctx context.Context, userID, house
 	return room, nil
 }
 
+type ExportedInventory struct {
+	ContentType        string
+	ContentDisposition string
+	Body               []byte
+}
+
+func (c InventoryClient) ExportInventory(ctx context.Context, userID, format string) (ExportedInventory, error) {
+	resp, err := requestbuilder.
+		New(http.MethodGet, c.address+shared.UserExportRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, userID).
+		WithQueryParam(shared.UserExportFormatQueryParam, format).
+		WithTimeout(time.Minute).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return ExportedInventory{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return ExportedInventory{}, propagateError(resp)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ExportedInventory{}, err
+	}
+
+	return ExportedInventory{
+		ContentType:        resp.Header.Get("Content-Type"),
+		ContentDisposition: resp.Header.Get("Content-Disposition"),
+		Body:               body,
+	}, nil
+}
+
+func (c InventoryClient) ImportInventory(ctx context.Context, userID string, archive json.RawMessage) error {
+	resp, err := requestbuilder.New(http.MethodPost, c.address+shared.UserImportRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, userID).
+		WithBody(archive).
+		WithInvalidateCache(
+			c.cache,
+			fmt.Sprintf(GetUserHouseholdsCacheKeyFormat, userID),
+		).
+		WithTimeout(time.Minute).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return propagateError(resp)
+	}
+
+	return nil
+}
+
+// ForgetUser erases all of the user's data and purges every cached response that could still contain it.
+func (c InventoryClient) ForgetUser(ctx context.Context, userID string) (shared.ErasureReport, error) {
+	cacheKeys := []string{fmt.Sprintf(GetUserHouseholdsCacheKeyFormat, userID)}
+
+	// Rooms are not part of the erasure report, so look them up beforehand to know which cache keys to purge
+	households, err := c.GetUserHouseholds(ctx, userID)
+	if err != nil {
+		return shared.ErasureReport{}, err
+	}
+
+	for _, household := range households {
+		for _, room := range household.Rooms {
+			cacheKeys = append(cacheKeys, fmt.Sprintf(GetUserHouseholdRoomCacheKeyFormat, userID, household.HouseholdID, room.RoomID))
+		}
+	}
+
+	resp, err := requestbuilder.New(http.MethodDelete, c.address+shared.UserRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, userID).
+		WithTimeout(time.Minute).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return shared.ErasureReport{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return shared.ErasureReport{}, propagateError(resp)
+	}
+
+	defer resp.Body.Close()
+
+	var report shared.ErasureReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return shared.ErasureReport{}, err
+	}
+
+	for _, householdID := range report.HouseholdIDs {
+		cacheKeys = append(cacheKeys, fmt.Sprintf(GetUserHouseholdCacheKeyFormat, userID, householdID))
+	}
+
+	check := shared.ErasureCheck{Name: "cached responses purged", Passed: true}
+	for _, key := range cacheKeys {
+		if err := c.cache.Delete(ctx, key); err != nil {
+			check.Passed = false
+			check.Detail = err.Error()
+			continue
+		}
+
+		report.CacheKeysPurged++
+	}
+
+	report.Checks = append(report.Checks, check)
+	report.Verified = report.Verified && check.Passed
+
+	return report, nil
+}
+
 func propagateError(resp *http.Response) error {
 	defer resp.Body.Close()
 
@@ -339,3 +455,19 @@ func propagateError(resp *http.Response) error {
 
 	return echo.NewHTTPError(resp.StatusCode, string(messageBytes))
 }
+
+// CheckHealth fails if the inventory service is not running.
+func (c InventoryClient) CheckHealth(ctx context.Context) error {
+	resp, err := requestbuilder.New(http.MethodGet, c.address+health.LivenessPath).Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("inventory service is unhealthy, status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cybre/home-inventory/internal/requestbuilder"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+)
+
+type LabelSheetRequest struct {
+	UserID      string
+	HouseholdID string
+	Layout      string
+	BaseURL     string
+}
+
+type LabelSheet struct {
+	ContentDisposition string
+	Body               []byte
+}
+
+func (c InventoryClient) GetLabelSheet(ctx context.Context, request LabelSheetRequest) (LabelSheet, error) {
+	resp, err := requestbuilder.
+		New(http.MethodGet, c.address+shared.UserLabelsRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, request.UserID).
+		WithQueryParam("householdId", request.HouseholdID).
+		WithQueryParam("layout", request.Layout).
+		WithQueryParam("baseUrl", request.BaseURL).
+		WithTimeout(time.Minute).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return LabelSheet{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return LabelSheet{}, propagateError(resp)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return LabelSheet{}, err
+	}
+
+	return LabelSheet{
+		ContentDisposition: resp.Header.Get("Content-Disposition"),
+		Body:               body,
+	}, nil
+}
+
+func (c InventoryClient) ResolveLabel(ctx context.Context, userID, code string) (shared.LabelTarget, error) {
+	resp, err := requestbuilder.
+		New(http.MethodGet, c.address+shared.UserLabelRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, userID).
+		WithPathParam(shared.UserLabelCodeParam, code).
+		WithHeader("Accept", "application/json").
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return shared.LabelTarget{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return shared.LabelTarget{}, propagateError(resp)
+	}
+
+	defer resp.Body.Close()
+
+	var target shared.LabelTarget
+	if err := json.NewDecoder(resp.Body).Decode(&target); err != nil {
+		return shared.LabelTarget{}, err
+	}
+
+	return target, nil
+}
@@ -0,0 +1,283 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/cybre/home-inventory/internal/requestbuilder"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+)
+
+func (c InventoryClient) GetUserCategories(ctx context.Context, userID string) ([]shared.UserCategory, error) {
+	resp, err := requestbuilder.
+		New(http.MethodGet, c.address+shared.UserCategoriesRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, userID).
+		WithHeader("Accept", "application/json").
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, propagateError(resp)
+	}
+
+	defer resp.Body.Close()
+
+	var categories []shared.UserCategory
+	if err := json.NewDecoder(resp.Body).Decode(&categories); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+type CreateCategoryRequest struct {
+	UserID     string `json:"-"`
+	CategoryID string `json:"categoryId"`
+	ParentID   string `json:"parentId"`
+	Name       string `json:"name"`
+}
+
+func (c InventoryClient) CreateCategory(ctx context.Context, category CreateCategoryRequest) error {
+	resp, err := requestbuilder.New(http.MethodPost, c.address+shared.UserCategoriesRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, category.UserID).
+		WithBody(category).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return propagateError(resp)
+	}
+
+	return nil
+}
+
+type RenameCategoryRequest struct {
+	UserID     string `json:"-"`
+	CategoryID string `json:"-"`
+	Name       string `json:"name"`
+}
+
+func (c InventoryClient) RenameCategory(ctx context.Context, category RenameCategoryRequest) error {
+	resp, err := requestbuilder.New(http.MethodPut, c.address+shared.UserCategoryRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, category.UserID).
+		WithPathParam(shared.UserCategoryIDParam, category.CategoryID).
+		WithBody(category).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return propagateError(resp)
+	}
+
+	return nil
+}
+
+type MergeCategoryRequest struct {
+	UserID           string `json:"-"`
+	CategoryID       string `json:"-"`
+	TargetCategoryID string `json:"targetCategoryId"`
+}
+
+func (c InventoryClient) MergeCategory(ctx context.Context, category MergeCategoryRequest) error {
+	resp, err := requestbuilder.New(http.MethodPost, c.address+shared.UserCategoryMergeRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, category.UserID).
+		WithPathParam(shared.UserCategoryIDParam, category.CategoryID).
+		WithBody(category).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return propagateError(resp)
+	}
+
+	return nil
+}
+
+func (c InventoryClient) DeleteCategory(ctx context.Context, userID, categoryID string) error {
+	resp, err := requestbuilder.New(http.MethodDelete, c.address+shared.UserCategoryRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, userID).
+		WithPathParam(shared.UserCategoryIDParam, categoryID).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return propagateError(resp)
+	}
+
+	return nil
+}
+
+type AddCategoryFieldRequest struct {
+	UserID     string   `json:"-"`
+	CategoryID string   `json:"-"`
+	FieldID    string   `json:"fieldId"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Options    []string `json:"options"`
+	Required   bool     `json:"required"`
+}
+
+func (c InventoryClient) AddCategoryField(ctx context.Context, field AddCategoryFieldRequest) error {
+	resp, err := requestbuilder.New(http.MethodPost, c.address+shared.UserCategoryFieldsRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, field.UserID).
+		WithPathParam(shared.UserCategoryIDParam, field.CategoryID).
+		WithBody(field).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return propagateError(resp)
+	}
+
+	return nil
+}
+
+func (c InventoryClient) RemoveCategoryField(ctx context.Context, userID, categoryID, fieldID string) error {
+	resp, err := requestbuilder.New(http.MethodDelete, c.address+shared.UserCategoryFieldRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, userID).
+		WithPathParam(shared.UserCategoryIDParam, categoryID).
+		WithPathParam(shared.UserCategoryFieldIDParam, fieldID).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return propagateError(resp)
+	}
+
+	return nil
+}
+
+func (c InventoryClient) GetUserTags(ctx context.Context, userID string) ([]shared.UserTag, error) {
+	resp, err := requestbuilder.
+		New(http.MethodGet, c.address+shared.UserTagsRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, userID).
+		WithHeader("Accept", "application/json").
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, propagateError(resp)
+	}
+
+	defer resp.Body.Close()
+
+	var tags []shared.UserTag
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+type CreateTagRequest struct {
+	UserID string `json:"-"`
+	TagID  string `json:"tagId"`
+	Name   string `json:"name"`
+}
+
+func (c InventoryClient) CreateTag(ctx context.Context, tag CreateTagRequest) error {
+	resp, err := requestbuilder.New(http.MethodPost, c.address+shared.UserTagsRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, tag.UserID).
+		WithBody(tag).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return propagateError(resp)
+	}
+
+	return nil
+}
+
+type RenameTagRequest struct {
+	UserID string `json:"-"`
+	TagID  string `json:"-"`
+	Name   string `json:"name"`
+}
+
+func (c InventoryClient) RenameTag(ctx context.Context, tag RenameTagRequest) error {
+	resp, err := requestbuilder.New(http.MethodPut, c.address+shared.UserTagRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, tag.UserID).
+		WithPathParam(shared.UserTagIDParam, tag.TagID).
+		WithBody(tag).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return propagateError(resp)
+	}
+
+	return nil
+}
+
+type MergeTagRequest struct {
+	UserID      string `json:"-"`
+	TagID       string `json:"-"`
+	TargetTagID string `json:"targetTagId"`
+}
+
+func (c InventoryClient) MergeTag(ctx context.Context, tag MergeTagRequest) error {
+	resp, err := requestbuilder.New(http.MethodPost, c.address+shared.UserTagMergeRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, tag.UserID).
+		WithPathParam(shared.UserTagIDParam, tag.TagID).
+		WithBody(tag).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return propagateError(resp)
+	}
+
+	return nil
+}
+
+func (c InventoryClient) DeleteTag(ctx context.Context, userID, tagID string) error {
+	resp, err := requestbuilder.New(http.MethodDelete, c.address+shared.UserTagRoute).
+		WithPathParam(shared.UserHouseholdsUserIDParam, userID).
+		WithPathParam(shared.UserTagIDParam, tagID).
+		WithRetry().
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return propagateError(resp)
+	}
+
+	return nil
+}
@@ -142,6 +142,7 @@ func (a *HouseholdAgregate) handleDeleteHouseholdCommand(ctx context.Context, co
 	return c.Events(HouseholdDeletedEvent{
 		HouseholdID: a.AggregateID().String(),
 		UserID:      a.UserID.String(),
+		Timestamp:   time.Now().UnixMilli(),
 	})
 }
 
@@ -201,10 +202,14 @@ func (a *HouseholdAgregate) handleDeleteRoomCommand(ctx context.Context, command
 		return nil, err
 	}
 
+	room, _ := a.Rooms.Get(roomID)
+
 	return c.Events(RoomDeletedEvent{
 		HouseholdID: a.AggregateID().String(),
 		UserID:      a.UserID.String(),
 		RoomID:      roomID.String(),
+		Name:        room.Name.String(),
+		Timestamp:   time.Now().UnixMilli(),
 	})
 }
 
@@ -3,8 +3,8 @@ package household
 import es "github.com/cybre/home-inventory/internal/eventsourcing"
 
 type CreateHouseholdCommand struct {
-	HouseholdID string
-	UserID      string
+	HouseholdID string `validate:"required,uuid4"`
+	UserID      string `validate:"required"`
 	Name        string
 	Location    string
 	Description string
@@ -20,8 +20,8 @@ func (c CreateHouseholdCommand) AggregateID() es.AggregateID {
 }
 
 type UpdateHouseholdCommand struct {
-	HouseholdID string
-	UserID      string
+	HouseholdID string `validate:"required,uuid4"`
+	UserID      string `validate:"required"`
 	Name        string
 	Location    string
 	Description string
@@ -36,8 +36,8 @@ func (c UpdateHouseholdCommand) AggregateID() es.AggregateID {
 }
 
 type DeleteHouseholdCommand struct {
-	HouseholdID string
-	UserID      string
+	HouseholdID string `validate:"required,uuid4"`
+	UserID      string `validate:"required"`
 }
 
 func (c DeleteHouseholdCommand) AggregateType() es.AggregateType {
@@ -49,9 +49,9 @@ func (c DeleteHouseholdCommand) AggregateID() es.AggregateID {
 }
 
 type AddRoomCommand struct {
-	HouseholdID string
-	UserID      string
-	RoomID      string
+	HouseholdID string `validate:"required,uuid4"`
+	UserID      string `validate:"required"`
+	RoomID      string `validate:"required,uuid4"`
 	Name        string
 }
 
@@ -64,9 +64,9 @@ func (c AddRoomCommand) AggregateID() es.AggregateID {
 }
 
 type UpdateRoomCommand struct {
-	HouseholdID string
-	UserID      string
-	RoomID      string
+	HouseholdID string `validate:"required,uuid4"`
+	UserID      string `validate:"required"`
+	RoomID      string `validate:"required,uuid4"`
 	Name        string
 }
 
@@ -79,9 +79,9 @@ func (c UpdateRoomCommand) AggregateID() es.AggregateID {
 }
 
 type DeleteRoomCommand struct {
-	HouseholdID string
-	UserID      string
-	RoomID      string
+	HouseholdID string `validate:"required,uuid4"`
+	UserID      string `validate:"required"`
+	RoomID      string `validate:"required,uuid4"`
 }
 
 func (c DeleteRoomCommand) AggregateType() es.AggregateType {
@@ -14,10 +14,10 @@ const (
 
 type HouseholdCreatedEvent struct {
 	HouseholdID string `json:"householdId"`
-	UserID      string `json:"userId"`
-	Name        string `json:"name"`
-	Location    string `json:"location"`
-	Description string `json:"description"`
+	UserID      string `json:"userId" personal:"subject"`
+	Name        string `json:"name" personal:"true"`
+	Location    string `json:"location" personal:"true"`
+	Description string `json:"description" personal:"true"`
 	Order       uint   `json:"order"`
 	Timestamp   int64  `json:"timestamp"`
 }
@@ -28,10 +28,10 @@ func (e HouseholdCreatedEvent) EventType() es.EventType {
 
 type HouseholdUpdatedEvent struct {
 	HouseholdID string `json:"householdId"`
-	UserID      string `json:"userId"`
-	Name        string `json:"name"`
-	Location    string `json:"location"`
-	Description string `json:"description"`
+	UserID      string `json:"userId" personal:"subject"`
+	Name        string `json:"name" personal:"true"`
+	Location    string `json:"location" personal:"true"`
+	Description string `json:"description" personal:"true"`
 	Timestamp   int64  `json:"timestamp"`
 }
 
@@ -41,7 +41,8 @@ func (e HouseholdUpdatedEvent) EventType() es.EventType {
 
 type HouseholdDeletedEvent struct {
 	HouseholdID string `json:"householdId"`
-	UserID      string `json:"userId"`
+	UserID      string `json:"userId" personal:"subject"`
+	Timestamp   int64  `json:"timestamp"`
 }
 
 func (e HouseholdDeletedEvent) EventType() es.EventType {
@@ -50,9 +51,9 @@ func (e HouseholdDeletedEvent) EventType() es.EventType {
 
 type RoomAddedEvent struct {
 	HouseholdID string `json:"householdId"`
-	UserID      string `json:"userId"`
+	UserID      string `json:"userId" personal:"subject"`
 	RoomID      string `json:"roomId"`
-	Name        string `json:"name"`
+	Name        string `json:"name" personal:"true"`
 	Order       uint   `json:"order"`
 	Timestamp   int64  `json:"timestamp"`
 }
@@ -63,9 +64,9 @@ func (e RoomAddedEvent) EventType() es.EventType {
 
 type RoomUpdatedEvent struct {
 	HouseholdID string `json:"householdId"`
-	UserID      string `json:"userId"`
+	UserID      string `json:"userId" personal:"subject"`
 	RoomID      string `json:"roomId"`
-	Name        string `json:"name"`
+	Name        string `json:"name" personal:"true"`
 	Order       uint   `json:"order"`
 	Timestamp   int64  `json:"timestamp"`
 }
@@ -76,8 +77,10 @@ func (e RoomUpdatedEvent) EventType() es.EventType {
 
 type RoomDeletedEvent struct {
 	HouseholdID string `json:"householdId"`
-	UserID      string `json:"userId"`
+	UserID      string `json:"userId" personal:"subject"`
 	RoomID      string `json:"roomId"`
+	Name        string `json:"name" personal:"true"`
+	Timestamp   int64  `json:"timestamp"`
 }
 
 func (e RoomDeletedEvent) EventType() es.EventType {
@@ -0,0 +1,427 @@
+package taxonomy
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	c "github.com/cybre/home-inventory/services/inventory/domain/common"
+)
+
+const TaxonomyAggregateType es.AggregateType = "TaxonomyAggregate"
+
+// TaxonomyAggregate holds the categories and tags a user classifies their things with.
+// Every user has exactly one, identified by UserTaxonomyID, which comes into existence with its first event.
+type TaxonomyAggregate struct {
+	es.AggregateContext
+
+	UserID     c.UserID
+	Categories Categories
+	Tags       Tags
+}
+
+func NewTaxonomyAggregate(aggregateContext es.AggregateContext) es.AggregateRoot {
+	return &TaxonomyAggregate{
+		AggregateContext: aggregateContext,
+		Categories:       NewCategories(),
+		Tags:             NewTags(),
+	}
+}
+
+func (a *TaxonomyAggregate) ApplyEvent(event es.EventData) {
+	switch e := event.(type) {
+	case CategoryCreatedEvent:
+		a.applyCategoryCreatedEvent(e)
+	case CategoryRenamedEvent:
+		a.applyCategoryRenamedEvent(e)
+	case CategoryMergedEvent:
+		a.applyCategoryMergedEvent(e)
+	case CategoryDeletedEvent:
+		a.applyCategoryDeletedEvent(e)
+	case CategoryFieldAddedEvent:
+		a.applyCategoryFieldAddedEvent(e)
+	case CategoryFieldRemovedEvent:
+		a.applyCategoryFieldRemovedEvent(e)
+	case TagCreatedEvent:
+		a.applyTagCreatedEvent(e)
+	case TagRenamedEvent:
+		a.applyTagRenamedEvent(e)
+	case TagMergedEvent:
+		a.applyTagMergedEvent(e)
+	case TagDeletedEvent:
+		a.applyTagDeletedEvent(e)
+	case TaxonomyClearedEvent:
+		a.applyTaxonomyClearedEvent(e)
+	default:
+		panic("unknown event type")
+	}
+}
+
+func (a *TaxonomyAggregate) HandleCommand(ctx context.Context, command es.Command) ([]es.EventData, error) {
+	switch c := command.(type) {
+	case CreateCategoryCommand:
+		return a.handleCreateCategoryCommand(ctx, c)
+	case RenameCategoryCommand:
+		return a.handleRenameCategoryCommand(ctx, c)
+	case MergeCategoryCommand:
+		return a.handleMergeCategoryCommand(ctx, c)
+	case DeleteCategoryCommand:
+		return a.handleDeleteCategoryCommand(ctx, c)
+	case AddCategoryFieldCommand:
+		return a.handleAddCategoryFieldCommand(ctx, c)
+	case RemoveCategoryFieldCommand:
+		return a.handleRemoveCategoryFieldCommand(ctx, c)
+	case CreateTagCommand:
+		return a.handleCreateTagCommand(ctx, c)
+	case RenameTagCommand:
+		return a.handleRenameTagCommand(ctx, c)
+	case MergeTagCommand:
+		return a.handleMergeTagCommand(ctx, c)
+	case DeleteTagCommand:
+		return a.handleDeleteTagCommand(ctx, c)
+	case ClearTaxonomyCommand:
+		return a.handleClearTaxonomyCommand(ctx, c)
+	default:
+		return nil, es.ErrUnknownCommand
+	}
+}
+
+func (a *TaxonomyAggregate) handleCreateCategoryCommand(ctx context.Context, command CreateCategoryCommand) ([]es.EventData, error) {
+	userID, err := c.NewUserID(command.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryID, err := NewCategoryID(command.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var parentID CategoryID
+	if command.ParentID != "" {
+		if parentID, err = NewCategoryID(command.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	name, err := NewCategoryName(command.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Categories.Add(Category{ID: categoryID, ParentID: parentID, Name: name}); err != nil {
+		return nil, err
+	}
+
+	return c.Events(CategoryCreatedEvent{
+		UserID:     userID.String(),
+		CategoryID: categoryID.String(),
+		ParentID:   parentID.String(),
+		Name:       name.String(),
+		Timestamp:  time.Now().UnixMilli(),
+	})
+}
+
+func (a *TaxonomyAggregate) handleRenameCategoryCommand(ctx context.Context, command RenameCategoryCommand) ([]es.EventData, error) {
+	categoryID, err := NewCategoryID(command.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := NewCategoryName(command.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Categories.Rename(categoryID, name); err != nil {
+		return nil, err
+	}
+
+	return c.Events(CategoryRenamedEvent{
+		UserID:     a.UserID.String(),
+		CategoryID: categoryID.String(),
+		Name:       name.String(),
+		Timestamp:  time.Now().UnixMilli(),
+	})
+}
+
+func (a *TaxonomyAggregate) handleMergeCategoryCommand(ctx context.Context, command MergeCategoryCommand) ([]es.EventData, error) {
+	categoryID, err := NewCategoryID(command.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	targetCategoryID, err := NewCategoryID(command.TargetCategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Categories.Merge(categoryID, targetCategoryID); err != nil {
+		return nil, err
+	}
+
+	return c.Events(CategoryMergedEvent{
+		UserID:           a.UserID.String(),
+		CategoryID:       categoryID.String(),
+		TargetCategoryID: targetCategoryID.String(),
+		Timestamp:        time.Now().UnixMilli(),
+	})
+}
+
+func (a *TaxonomyAggregate) handleDeleteCategoryCommand(ctx context.Context, command DeleteCategoryCommand) ([]es.EventData, error) {
+	categoryID, err := NewCategoryID(command.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Categories.Remove(categoryID); err != nil {
+		return nil, err
+	}
+
+	return c.Events(CategoryDeletedEvent{
+		UserID:     a.UserID.String(),
+		CategoryID: categoryID.String(),
+	})
+}
+
+func (a *TaxonomyAggregate) handleAddCategoryFieldCommand(ctx context.Context, command AddCategoryFieldCommand) ([]es.EventData, error) {
+	categoryID, err := NewCategoryID(command.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldID, err := NewFieldID(command.FieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := NewFieldName(command.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldType, err := NewFieldType(command.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := NewFieldOptions(fieldType, command.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Categories.AddField(categoryID, CategoryField{
+		ID:       fieldID,
+		Name:     name,
+		Type:     fieldType,
+		Options:  options,
+		Required: command.Required,
+	}); err != nil {
+		return nil, err
+	}
+
+	return c.Events(CategoryFieldAddedEvent{
+		UserID:     a.UserID.String(),
+		CategoryID: categoryID.String(),
+		FieldID:    fieldID.String(),
+		Name:       name.String(),
+		Type:       fieldType.String(),
+		Options:    strings.Join(options, "\n"),
+		Required:   command.Required,
+		Timestamp:  time.Now().UnixMilli(),
+	})
+}
+
+func (a *TaxonomyAggregate) handleRemoveCategoryFieldCommand(ctx context.Context, command RemoveCategoryFieldCommand) ([]es.EventData, error) {
+	categoryID, err := NewCategoryID(command.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldID, err := NewFieldID(command.FieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Categories.RemoveField(categoryID, fieldID); err != nil {
+		return nil, err
+	}
+
+	return c.Events(CategoryFieldRemovedEvent{
+		UserID:     a.UserID.String(),
+		CategoryID: categoryID.String(),
+		FieldID:    fieldID.String(),
+	})
+}
+
+func (a *TaxonomyAggregate) handleCreateTagCommand(ctx context.Context, command CreateTagCommand) ([]es.EventData, error) {
+	userID, err := c.NewUserID(command.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	tagID, err := NewTagID(command.TagID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := NewTagName(command.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Tags.Add(Tag{ID: tagID, Name: name}); err != nil {
+		return nil, err
+	}
+
+	return c.Events(TagCreatedEvent{
+		UserID:    userID.String(),
+		TagID:     tagID.String(),
+		Name:      name.String(),
+		Timestamp: time.Now().UnixMilli(),
+	})
+}
+
+func (a *TaxonomyAggregate) handleRenameTagCommand(ctx context.Context, command RenameTagCommand) ([]es.EventData, error) {
+	tagID, err := NewTagID(command.TagID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := NewTagName(command.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Tags.Rename(tagID, name); err != nil {
+		return nil, err
+	}
+
+	return c.Events(TagRenamedEvent{
+		UserID:    a.UserID.String(),
+		TagID:     tagID.String(),
+		Name:      name.String(),
+		Timestamp: time.Now().UnixMilli(),
+	})
+}
+
+func (a *TaxonomyAggregate) handleMergeTagCommand(ctx context.Context, command MergeTagCommand) ([]es.EventData, error) {
+	tagID, err := NewTagID(command.TagID)
+	if err != nil {
+		return nil, err
+	}
+
+	targetTagID, err := NewTagID(command.TargetTagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Tags.Merge(tagID, targetTagID); err != nil {
+		return nil, err
+	}
+
+	return c.Events(TagMergedEvent{
+		UserID:      a.UserID.String(),
+		TagID:       tagID.String(),
+		TargetTagID: targetTagID.String(),
+		Timestamp:   time.Now().UnixMilli(),
+	})
+}
+
+func (a *TaxonomyAggregate) handleDeleteTagCommand(ctx context.Context, command DeleteTagCommand) ([]es.EventData, error) {
+	tagID, err := NewTagID(command.TagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Tags.Remove(tagID); err != nil {
+		return nil, err
+	}
+
+	return c.Events(TagDeletedEvent{
+		UserID: a.UserID.String(),
+		TagID:  tagID.String(),
+	})
+}
+
+func (a *TaxonomyAggregate) handleClearTaxonomyCommand(ctx context.Context, command ClearTaxonomyCommand) ([]es.EventData, error) {
+	userID, err := c.NewUserID(command.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Events(TaxonomyClearedEvent{
+		UserID: userID.String(),
+	})
+}
+
+func (a *TaxonomyAggregate) applyCategoryCreatedEvent(event CategoryCreatedEvent) {
+	a.UserID, _ = c.NewUserID(event.UserID)
+	name, _ := NewCategoryName(event.Name)
+	a.Categories[CategoryID(event.CategoryID)] = Category{
+		ID:       CategoryID(event.CategoryID),
+		ParentID: CategoryID(event.ParentID),
+		Name:     name,
+	}
+}
+
+func (a *TaxonomyAggregate) applyCategoryRenamedEvent(event CategoryRenamedEvent) {
+	name, _ := NewCategoryName(event.Name)
+	a.Categories.Rename(CategoryID(event.CategoryID), name)
+}
+
+func (a *TaxonomyAggregate) applyCategoryMergedEvent(event CategoryMergedEvent) {
+	a.Categories.Merge(CategoryID(event.CategoryID), CategoryID(event.TargetCategoryID))
+}
+
+func (a *TaxonomyAggregate) applyCategoryDeletedEvent(event CategoryDeletedEvent) {
+	a.Categories.Remove(CategoryID(event.CategoryID))
+}
+
+func (a *TaxonomyAggregate) applyCategoryFieldAddedEvent(event CategoryFieldAddedEvent) {
+	var options FieldOptions
+	if event.Options != "" {
+		options = strings.Split(event.Options, "\n")
+	}
+
+	name, _ := NewFieldName(event.Name)
+	a.Categories.AddField(CategoryID(event.CategoryID), CategoryField{
+		ID:       FieldID(event.FieldID),
+		Name:     name,
+		Type:     FieldType(event.Type),
+		Options:  options,
+		Required: event.Required,
+	})
+}
+
+func (a *TaxonomyAggregate) applyCategoryFieldRemovedEvent(event CategoryFieldRemovedEvent) {
+	a.Categories.RemoveField(CategoryID(event.CategoryID), FieldID(event.FieldID))
+}
+
+func (a *TaxonomyAggregate) applyTagCreatedEvent(event TagCreatedEvent) {
+	a.UserID, _ = c.NewUserID(event.UserID)
+	name, _ := NewTagName(event.Name)
+	a.Tags[TagID(event.TagID)] = Tag{
+		ID:   TagID(event.TagID),
+		Name: name,
+	}
+}
+
+func (a *TaxonomyAggregate) applyTagRenamedEvent(event TagRenamedEvent) {
+	name, _ := NewTagName(event.Name)
+	a.Tags.Rename(TagID(event.TagID), name)
+}
+
+func (a *TaxonomyAggregate) applyTagMergedEvent(event TagMergedEvent) {
+	a.Tags.Merge(TagID(event.TagID), TagID(event.TargetTagID))
+}
+
+func (a *TaxonomyAggregate) applyTagDeletedEvent(event TagDeletedEvent) {
+	a.Tags.Remove(TagID(event.TagID))
+}
+
+func (a *TaxonomyAggregate) applyTaxonomyClearedEvent(event TaxonomyClearedEvent) {
+	a.Categories = NewCategories()
+	a.Tags = NewTags()
+}
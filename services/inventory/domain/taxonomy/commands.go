@@ -0,0 +1,158 @@
+package taxonomy
+
+import es "github.com/cybre/home-inventory/internal/eventsourcing"
+
+type CreateCategoryCommand struct {
+	UserID     string `validate:"required"`
+	CategoryID string `validate:"required,uuid4"`
+	ParentID   string `validate:"omitempty,uuid4"`
+	Name       string
+}
+
+func (c CreateCategoryCommand) AggregateType() es.AggregateType {
+	return TaxonomyAggregateType
+}
+
+func (c CreateCategoryCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(UserTaxonomyID(c.UserID))
+}
+
+type RenameCategoryCommand struct {
+	UserID     string `validate:"required"`
+	CategoryID string `validate:"required,uuid4"`
+	Name       string
+}
+
+func (c RenameCategoryCommand) AggregateType() es.AggregateType {
+	return TaxonomyAggregateType
+}
+
+func (c RenameCategoryCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(UserTaxonomyID(c.UserID))
+}
+
+type MergeCategoryCommand struct {
+	UserID           string `validate:"required"`
+	CategoryID       string `validate:"required,uuid4"`
+	TargetCategoryID string `validate:"required,uuid4"`
+}
+
+func (c MergeCategoryCommand) AggregateType() es.AggregateType {
+	return TaxonomyAggregateType
+}
+
+func (c MergeCategoryCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(UserTaxonomyID(c.UserID))
+}
+
+type DeleteCategoryCommand struct {
+	UserID     string `validate:"required"`
+	CategoryID string `validate:"required,uuid4"`
+}
+
+func (c DeleteCategoryCommand) AggregateType() es.AggregateType {
+	return TaxonomyAggregateType
+}
+
+func (c DeleteCategoryCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(UserTaxonomyID(c.UserID))
+}
+
+type AddCategoryFieldCommand struct {
+	UserID     string `validate:"required"`
+	CategoryID string `validate:"required,uuid4"`
+	FieldID    string `validate:"required,uuid4"`
+	Name       string
+	Type       string
+	Options    []string
+	Required   bool
+}
+
+func (c AddCategoryFieldCommand) AggregateType() es.AggregateType {
+	return TaxonomyAggregateType
+}
+
+func (c AddCategoryFieldCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(UserTaxonomyID(c.UserID))
+}
+
+type RemoveCategoryFieldCommand struct {
+	UserID     string `validate:"required"`
+	CategoryID string `validate:"required,uuid4"`
+	FieldID    string `validate:"required,uuid4"`
+}
+
+func (c RemoveCategoryFieldCommand) AggregateType() es.AggregateType {
+	return TaxonomyAggregateType
+}
+
+func (c RemoveCategoryFieldCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(UserTaxonomyID(c.UserID))
+}
+
+type CreateTagCommand struct {
+	UserID string `validate:"required"`
+	TagID  string `validate:"required,uuid4"`
+	Name   string
+}
+
+func (c CreateTagCommand) AggregateType() es.AggregateType {
+	return TaxonomyAggregateType
+}
+
+func (c CreateTagCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(UserTaxonomyID(c.UserID))
+}
+
+type RenameTagCommand struct {
+	UserID string `validate:"required"`
+	TagID  string `validate:"required,uuid4"`
+	Name   string
+}
+
+func (c RenameTagCommand) AggregateType() es.AggregateType {
+	return TaxonomyAggregateType
+}
+
+func (c RenameTagCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(UserTaxonomyID(c.UserID))
+}
+
+type MergeTagCommand struct {
+	UserID      string `validate:"required"`
+	TagID       string `validate:"required,uuid4"`
+	TargetTagID string `validate:"required,uuid4"`
+}
+
+func (c MergeTagCommand) AggregateType() es.AggregateType {
+	return TaxonomyAggregateType
+}
+
+func (c MergeTagCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(UserTaxonomyID(c.UserID))
+}
+
+type DeleteTagCommand struct {
+	UserID string `validate:"required"`
+	TagID  string `validate:"required,uuid4"`
+}
+
+func (c DeleteTagCommand) AggregateType() es.AggregateType {
+	return TaxonomyAggregateType
+}
+
+func (c DeleteTagCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(UserTaxonomyID(c.UserID))
+}
+
+type ClearTaxonomyCommand struct {
+	UserID string `validate:"required"`
+}
+
+func (c ClearTaxonomyCommand) AggregateType() es.AggregateType {
+	return TaxonomyAggregateType
+}
+
+func (c ClearTaxonomyCommand) AggregateID() es.AggregateID {
+	return es.AggregateID(UserTaxonomyID(c.UserID))
+}
@@ -0,0 +1,140 @@
+package taxonomy
+
+import es "github.com/cybre/home-inventory/internal/eventsourcing"
+
+const (
+	EventTypeCategoryCreated es.EventType = "CategoryCreatedEvent"
+	EventTypeCategoryRenamed es.EventType = "CategoryRenamedEvent"
+	EventTypeCategoryMerged  es.EventType = "CategoryMergedEvent"
+	EventTypeCategoryDeleted es.EventType = "CategoryDeletedEvent"
+
+	EventTypeCategoryFieldAdded   es.EventType = "CategoryFieldAddedEvent"
+	EventTypeCategoryFieldRemoved es.EventType = "CategoryFieldRemovedEvent"
+
+	EventTypeTagCreated es.EventType = "TagCreatedEvent"
+	EventTypeTagRenamed es.EventType = "TagRenamedEvent"
+	EventTypeTagMerged  es.EventType = "TagMergedEvent"
+	EventTypeTagDeleted es.EventType = "TagDeletedEvent"
+
+	EventTypeTaxonomyCleared es.EventType = "TaxonomyClearedEvent"
+)
+
+type CategoryCreatedEvent struct {
+	UserID     string `json:"userId" personal:"subject"`
+	CategoryID string `json:"categoryId"`
+	ParentID   string `json:"parentId"`
+	Name       string `json:"name" personal:"true"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+func (e CategoryCreatedEvent) EventType() es.EventType {
+	return EventTypeCategoryCreated
+}
+
+type CategoryRenamedEvent struct {
+	UserID     string `json:"userId" personal:"subject"`
+	CategoryID string `json:"categoryId"`
+	Name       string `json:"name" personal:"true"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+func (e CategoryRenamedEvent) EventType() es.EventType {
+	return EventTypeCategoryRenamed
+}
+
+type CategoryMergedEvent struct {
+	UserID           string `json:"userId" personal:"subject"`
+	CategoryID       string `json:"categoryId"`
+	TargetCategoryID string `json:"targetCategoryId"`
+	Timestamp        int64  `json:"timestamp"`
+}
+
+func (e CategoryMergedEvent) EventType() es.EventType {
+	return EventTypeCategoryMerged
+}
+
+type CategoryDeletedEvent struct {
+	UserID     string `json:"userId" personal:"subject"`
+	CategoryID string `json:"categoryId"`
+}
+
+func (e CategoryDeletedEvent) EventType() es.EventType {
+	return EventTypeCategoryDeleted
+}
+
+// CategoryFieldAddedEvent carries the options of enum fields joined by newlines,
+// since only string fields can hold personal data.
+type CategoryFieldAddedEvent struct {
+	UserID     string `json:"userId" personal:"subject"`
+	CategoryID string `json:"categoryId"`
+	FieldID    string `json:"fieldId"`
+	Name       string `json:"name" personal:"true"`
+	Type       string `json:"type"`
+	Options    string `json:"options" personal:"true"`
+	Required   bool   `json:"required"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+func (e CategoryFieldAddedEvent) EventType() es.EventType {
+	return EventTypeCategoryFieldAdded
+}
+
+type CategoryFieldRemovedEvent struct {
+	UserID     string `json:"userId" personal:"subject"`
+	CategoryID string `json:"categoryId"`
+	FieldID    string `json:"fieldId"`
+}
+
+func (e CategoryFieldRemovedEvent) EventType() es.EventType {
+	return EventTypeCategoryFieldRemoved
+}
+
+type TagCreatedEvent struct {
+	UserID    string `json:"userId" personal:"subject"`
+	TagID     string `json:"tagId"`
+	Name      string `json:"name" personal:"true"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (e TagCreatedEvent) EventType() es.EventType {
+	return EventTypeTagCreated
+}
+
+type TagRenamedEvent struct {
+	UserID    string `json:"userId" personal:"subject"`
+	TagID     string `json:"tagId"`
+	Name      string `json:"name" personal:"true"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (e TagRenamedEvent) EventType() es.EventType {
+	return EventTypeTagRenamed
+}
+
+type TagMergedEvent struct {
+	UserID      string `json:"userId" personal:"subject"`
+	TagID       string `json:"tagId"`
+	TargetTagID string `json:"targetTagId"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+func (e TagMergedEvent) EventType() es.EventType {
+	return EventTypeTagMerged
+}
+
+type TagDeletedEvent struct {
+	UserID string `json:"userId" personal:"subject"`
+	TagID  string `json:"tagId"`
+}
+
+func (e TagDeletedEvent) EventType() es.EventType {
+	return EventTypeTagDeleted
+}
+
+type TaxonomyClearedEvent struct {
+	UserID string `json:"userId" personal:"subject"`
+}
+
+func (e TaxonomyClearedEvent) EventType() es.EventType {
+	return EventTypeTaxonomyCleared
+}
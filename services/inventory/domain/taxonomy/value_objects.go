@@ -0,0 +1,542 @@
+package taxonomy
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bnkamalesh/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	MinCategoryNameLength = 2
+	MaxCategoryNameLength = 50
+
+	MinTagNameLength = 2
+	MaxTagNameLength = 30
+
+	MinFieldNameLength   = 1
+	MaxFieldNameLength   = 40
+	MaxCategoryFields    = 20
+	MaxFieldOptions      = 50
+	MaxFieldOptionLength = 50
+	MaxTextFieldLength   = 500
+
+	FieldDateLayout = "2006-01-02"
+)
+
+type FieldType string
+
+const (
+	FieldTypeText   FieldType = "text"
+	FieldTypeNumber FieldType = "number"
+	FieldTypeDate   FieldType = "date"
+	FieldTypeEnum   FieldType = "enum"
+	FieldTypeMoney  FieldType = "money"
+)
+
+// moneyPattern matches an amount with at most two decimals, optionally followed by an ISO 4217 currency code.
+var moneyPattern = regexp.MustCompile(`^-?\d+(\.\d{1,2})?( [A-Z]{3})?$`)
+
+// taxonomyNamespace is used to derive a stable taxonomy aggregate ID from a user ID.
+var taxonomyNamespace = uuid.MustParse("3f0b6c8e-5f3e-4b8a-9a59-2f7a4c1d6e21")
+
+// UserTaxonomyID returns the ID of the taxonomy aggregate holding the categories and tags of a user.
+func UserTaxonomyID(userID string) string {
+	return uuid.NewSHA1(taxonomyNamespace, []byte(userID)).String()
+}
+
+type CategoryID string
+
+func NewCategoryID(id string) (CategoryID, error) {
+	uuid, err := uuid.Parse(id)
+	if err != nil {
+		return "", errors.InputBodyf("invalid category ID. must be valid UUID: %s", id)
+	}
+
+	return CategoryID(uuid.String()), nil
+}
+
+func (id CategoryID) String() string {
+	return string(id)
+}
+
+type CategoryName string
+
+func NewCategoryName(name string) (CategoryName, error) {
+	name = strings.TrimSpace(name)
+
+	if len(name) < MinCategoryNameLength || len(name) > MaxCategoryNameLength {
+		return "", errors.InputBodyf("category name must be between %d and %d characters", MinCategoryNameLength, MaxCategoryNameLength)
+	}
+
+	return CategoryName(name), nil
+}
+
+func (n CategoryName) String() string {
+	return string(n)
+}
+
+func (n CategoryName) Equals(other CategoryName) bool {
+	return strings.EqualFold(n.String(), other.String())
+}
+
+type Category struct {
+	ID       CategoryID
+	ParentID CategoryID
+	Name     CategoryName
+	Fields   []CategoryField
+}
+
+func (c Category) Field(id FieldID) (CategoryField, bool) {
+	for _, field := range c.Fields {
+		if field.ID == id {
+			return field, true
+		}
+	}
+
+	return CategoryField{}, false
+}
+
+// ValidateFieldValues checks values keyed by field ID against the fields of the category.
+// Every required field must have a value and values for unknown fields are rejected.
+func (c Category) ValidateFieldValues(values map[FieldID]string) error {
+	for id := range values {
+		if _, ok := c.Field(id); !ok {
+			return errors.InputBodyf("category %s has no field with ID %s", c.Name, id)
+		}
+	}
+
+	for _, field := range c.Fields {
+		value, ok := values[field.ID]
+		if !ok || strings.TrimSpace(value) == "" {
+			if field.Required {
+				return errors.InputBodyf("field %s is required", field.Name)
+			}
+
+			continue
+		}
+
+		if err := field.ValidateValue(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type Categories map[CategoryID]Category
+
+func NewCategories() Categories {
+	return make(Categories)
+}
+
+func (c Categories) Get(id CategoryID) (Category, bool) {
+	category, ok := c[id]
+
+	return category, ok
+}
+
+func (c Categories) Add(category Category) error {
+	if _, ok := c.Get(category.ID); ok {
+		return errors.Duplicatef("category with ID %s already exists", category.ID)
+	}
+
+	if category.ParentID != "" {
+		if _, ok := c.Get(category.ParentID); !ok {
+			return errors.NotFoundf("parent category with ID %s does not exist", category.ParentID)
+		}
+	}
+
+	if _, ok := c.FindSibling(category.ParentID, category.Name); ok {
+		return errors.Duplicatef("category with name %s already exists", category.Name)
+	}
+
+	c[category.ID] = category
+
+	return nil
+}
+
+func (c Categories) Rename(id CategoryID, name CategoryName) error {
+	category, ok := c.Get(id)
+	if !ok {
+		return errors.NotFoundf("category with ID %s does not exist", id)
+	}
+
+	if sibling, ok := c.FindSibling(category.ParentID, name); ok && sibling.ID != id {
+		return errors.Duplicatef("category with name %s already exists", name)
+	}
+
+	category.Name = name
+	c[id] = category
+
+	return nil
+}
+
+// Merge folds the source category into the target, moving all of its subcategories under the target.
+func (c Categories) Merge(sourceID, targetID CategoryID) error {
+	if sourceID == targetID {
+		return errors.Validation("category cannot be merged into itself")
+	}
+
+	if _, ok := c.Get(sourceID); !ok {
+		return errors.NotFoundf("category with ID %s does not exist", sourceID)
+	}
+
+	if _, ok := c.Get(targetID); !ok {
+		return errors.NotFoundf("category with ID %s does not exist", targetID)
+	}
+
+	if c.IsDescendant(targetID, sourceID) {
+		return errors.Validation("category cannot be merged into one of its subcategories")
+	}
+
+	targetChildren := c.Children(targetID)
+	for _, child := range c.Children(sourceID) {
+		for _, targetChild := range targetChildren {
+			if targetChild.Name.Equals(child.Name) {
+				return errors.Duplicatef("category %s already has a subcategory named %s", targetID, child.Name)
+			}
+		}
+	}
+
+	for _, child := range c.Children(sourceID) {
+		child.ParentID = targetID
+		c[child.ID] = child
+	}
+
+	delete(c, sourceID)
+
+	return nil
+}
+
+func (c Categories) Remove(id CategoryID) error {
+	if _, ok := c.Get(id); !ok {
+		return errors.NotFoundf("category with ID %s does not exist", id)
+	}
+
+	if len(c.Children(id)) > 0 {
+		return errors.Validation("category with subcategories cannot be deleted")
+	}
+
+	delete(c, id)
+
+	return nil
+}
+
+func (c Categories) AddField(id CategoryID, field CategoryField) error {
+	category, ok := c.Get(id)
+	if !ok {
+		return errors.NotFoundf("category with ID %s does not exist", id)
+	}
+
+	if len(category.Fields) >= MaxCategoryFields {
+		return errors.Validationf("category cannot have more than %d fields", MaxCategoryFields)
+	}
+
+	for _, existing := range category.Fields {
+		if existing.ID == field.ID {
+			return errors.Duplicatef("field with ID %s already exists", field.ID)
+		}
+
+		if existing.Name.Equals(field.Name) {
+			return errors.Duplicatef("field with name %s already exists", field.Name)
+		}
+	}
+
+	category.Fields = append(slices.Clip(category.Fields), field)
+	c[id] = category
+
+	return nil
+}
+
+func (c Categories) RemoveField(id CategoryID, fieldID FieldID) error {
+	category, ok := c.Get(id)
+	if !ok {
+		return errors.NotFoundf("category with ID %s does not exist", id)
+	}
+
+	if _, ok := category.Field(fieldID); !ok {
+		return errors.NotFoundf("field with ID %s does not exist", fieldID)
+	}
+
+	category.Fields = slices.DeleteFunc(slices.Clone(category.Fields), func(field CategoryField) bool {
+		return field.ID == fieldID
+	})
+	c[id] = category
+
+	return nil
+}
+
+func (c Categories) Children(id CategoryID) []Category {
+	children := []Category{}
+	for _, category := range c {
+		if category.ParentID == id {
+			children = append(children, category)
+		}
+	}
+
+	return children
+}
+
+func (c Categories) FindSibling(parentID CategoryID, name CategoryName) (Category, bool) {
+	for _, category := range c.Children(parentID) {
+		if category.Name.Equals(name) {
+			return category, true
+		}
+	}
+
+	return Category{}, false
+}
+
+// IsDescendant reports whether the category is nested anywhere below the ancestor.
+func (c Categories) IsDescendant(id, ancestorID CategoryID) bool {
+	for category, ok := c.Get(id); ok && category.ParentID != ""; category, ok = c.Get(category.ParentID) {
+		if category.ParentID == ancestorID {
+			return true
+		}
+	}
+
+	return false
+}
+
+type TagID string
+
+func NewTagID(id string) (TagID, error) {
+	uuid, err := uuid.Parse(id)
+	if err != nil {
+		return "", errors.InputBodyf("invalid tag ID. must be valid UUID: %s", id)
+	}
+
+	return TagID(uuid.String()), nil
+}
+
+func (id TagID) String() string {
+	return string(id)
+}
+
+type TagName string
+
+func NewTagName(name string) (TagName, error) {
+	name = strings.TrimSpace(name)
+
+	if len(name) < MinTagNameLength || len(name) > MaxTagNameLength {
+		return "", errors.InputBodyf("tag name must be between %d and %d characters", MinTagNameLength, MaxTagNameLength)
+	}
+
+	return TagName(name), nil
+}
+
+func (n TagName) String() string {
+	return string(n)
+}
+
+func (n TagName) Equals(other TagName) bool {
+	return strings.EqualFold(n.String(), other.String())
+}
+
+type Tag struct {
+	ID   TagID
+	Name TagName
+}
+
+type Tags map[TagID]Tag
+
+func NewTags() Tags {
+	return make(Tags)
+}
+
+func (t Tags) Get(id TagID) (Tag, bool) {
+	tag, ok := t[id]
+
+	return tag, ok
+}
+
+func (t Tags) FindByName(name TagName) (Tag, bool) {
+	for _, tag := range t {
+		if tag.Name.Equals(name) {
+			return tag, true
+		}
+	}
+
+	return Tag{}, false
+}
+
+func (t Tags) Add(tag Tag) error {
+	if _, ok := t.Get(tag.ID); ok {
+		return errors.Duplicatef("tag with ID %s already exists", tag.ID)
+	}
+
+	if _, ok := t.FindByName(tag.Name); ok {
+		return errors.Duplicatef("tag with name %s already exists", tag.Name)
+	}
+
+	t[tag.ID] = tag
+
+	return nil
+}
+
+func (t Tags) Rename(id TagID, name TagName) error {
+	tag, ok := t.Get(id)
+	if !ok {
+		return errors.NotFoundf("tag with ID %s does not exist", id)
+	}
+
+	if existing, ok := t.FindByName(name); ok && existing.ID != id {
+		return errors.Duplicatef("tag with name %s already exists", name)
+	}
+
+	tag.Name = name
+	t[id] = tag
+
+	return nil
+}
+
+func (t Tags) Merge(sourceID, targetID TagID) error {
+	if sourceID == targetID {
+		return errors.Validation("tag cannot be merged into itself")
+	}
+
+	if _, ok := t.Get(sourceID); !ok {
+		return errors.NotFoundf("tag with ID %s does not exist", sourceID)
+	}
+
+	if _, ok := t.Get(targetID); !ok {
+		return errors.NotFoundf("tag with ID %s does not exist", targetID)
+	}
+
+	delete(t, sourceID)
+
+	return nil
+}
+
+func (t Tags) Remove(id TagID) error {
+	if _, ok := t.Get(id); !ok {
+		return errors.NotFoundf("tag with ID %s does not exist", id)
+	}
+
+	delete(t, id)
+
+	return nil
+}
+
+type FieldID string
+
+func NewFieldID(id string) (FieldID, error) {
+	uuid, err := uuid.Parse(id)
+	if err != nil {
+		return "", errors.InputBodyf("invalid field ID. must be valid UUID: %s", id)
+	}
+
+	return FieldID(uuid.String()), nil
+}
+
+func (id FieldID) String() string {
+	return string(id)
+}
+
+type FieldName string
+
+func NewFieldName(name string) (FieldName, error) {
+	name = strings.TrimSpace(name)
+
+	if len(name) < MinFieldNameLength || len(name) > MaxFieldNameLength {
+		return "", errors.InputBodyf("field name must be between %d and %d characters", MinFieldNameLength, MaxFieldNameLength)
+	}
+
+	return FieldName(name), nil
+}
+
+func (n FieldName) String() string {
+	return string(n)
+}
+
+func (n FieldName) Equals(other FieldName) bool {
+	return strings.EqualFold(n.String(), other.String())
+}
+
+func NewFieldType(fieldType string) (FieldType, error) {
+	switch t := FieldType(fieldType); t {
+	case FieldTypeText, FieldTypeNumber, FieldTypeDate, FieldTypeEnum, FieldTypeMoney:
+		return t, nil
+	default:
+		return "", errors.InputBodyf("invalid field type: %s", fieldType)
+	}
+}
+
+func (t FieldType) String() string {
+	return string(t)
+}
+
+// FieldOptions are the allowed values of an enum field.
+type FieldOptions []string
+
+func NewFieldOptions(fieldType FieldType, options []string) (FieldOptions, error) {
+	if fieldType != FieldTypeEnum {
+		if len(options) > 0 {
+			return nil, errors.InputBodyf("only enum fields can have options")
+		}
+
+		return nil, nil
+	}
+
+	if len(options) == 0 || len(options) > MaxFieldOptions {
+		return nil, errors.InputBodyf("enum fields must have between 1 and %d options", MaxFieldOptions)
+	}
+
+	fieldOptions := make(FieldOptions, 0, len(options))
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" || len(option) > MaxFieldOptionLength || strings.Contains(option, "\n") {
+			return nil, errors.InputBodyf("enum options must be single lines of between 1 and %d characters", MaxFieldOptionLength)
+		}
+
+		if slices.Contains(fieldOptions, option) {
+			return nil, errors.InputBodyf("duplicate enum option: %s", option)
+		}
+
+		fieldOptions = append(fieldOptions, option)
+	}
+
+	return fieldOptions, nil
+}
+
+// CategoryField describes an attribute that things in a category can have, such as the serial number of a laptop.
+type CategoryField struct {
+	ID       FieldID
+	Name     FieldName
+	Type     FieldType
+	Options  FieldOptions
+	Required bool
+}
+
+// ValidateValue checks that a value is well-formed for the type of the field.
+func (f CategoryField) ValidateValue(value string) error {
+	switch f.Type {
+	case FieldTypeText:
+		if len(value) > MaxTextFieldLength {
+			return errors.InputBodyf("field %s must be at most %d characters", f.Name, MaxTextFieldLength)
+		}
+	case FieldTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.InputBodyf("field %s must be a number", f.Name)
+		}
+	case FieldTypeDate:
+		if _, err := time.Parse(FieldDateLayout, value); err != nil {
+			return errors.InputBodyf("field %s must be a date in the format YYYY-MM-DD", f.Name)
+		}
+	case FieldTypeEnum:
+		if !slices.Contains(f.Options, value) {
+			return errors.InputBodyf("field %s must be one of: %s", f.Name, strings.Join(f.Options, ", "))
+		}
+	case FieldTypeMoney:
+		if !moneyPattern.MatchString(value) {
+			return errors.InputBodyf("field %s must be an amount such as 12.50 or 12.50 EUR", f.Name)
+		}
+	}
+
+	return nil
+}
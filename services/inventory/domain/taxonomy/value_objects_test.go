@@ -0,0 +1,61 @@
+package taxonomy_test
+
+import (
+	"testing"
+
+	"github.com/cybre/home-inventory/services/inventory/domain/taxonomy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryValidateFieldValues(t *testing.T) {
+	category := taxonomy.Category{
+		Name: "Clothing",
+		Fields: []taxonomy.CategoryField{
+			{ID: "size", Name: "Size", Type: taxonomy.FieldTypeEnum, Options: taxonomy.FieldOptions{"S", "M", "L"}, Required: true},
+			{ID: "price", Name: "Price", Type: taxonomy.FieldTypeMoney},
+			{ID: "bought", Name: "Bought", Type: taxonomy.FieldTypeDate},
+			{ID: "weight", Name: "Weight", Type: taxonomy.FieldTypeNumber},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		values map[taxonomy.FieldID]string
+		valid  bool
+	}{
+		{"all valid", map[taxonomy.FieldID]string{"size": "M", "price": "12.50 EUR", "bought": "2024-04-05", "weight": "0.4"}, true},
+		{"optional omitted", map[taxonomy.FieldID]string{"size": "L"}, true},
+		{"required missing", map[taxonomy.FieldID]string{"price": "3"}, false},
+		{"unknown option", map[taxonomy.FieldID]string{"size": "XL"}, false},
+		{"malformed money", map[taxonomy.FieldID]string{"size": "S", "price": "12.505"}, false},
+		{"malformed date", map[taxonomy.FieldID]string{"size": "S", "bought": "05/04/2024"}, false},
+		{"malformed number", map[taxonomy.FieldID]string{"size": "S", "weight": "heavy"}, false},
+		{"unknown field", map[taxonomy.FieldID]string{"size": "S", "color": "red"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := category.ValidateFieldValues(tt.values)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewFieldOptions(t *testing.T) {
+	options, err := taxonomy.NewFieldOptions(taxonomy.FieldTypeEnum, []string{" S ", "M"})
+	assert.NoError(t, err)
+	assert.Equal(t, taxonomy.FieldOptions{"S", "M"}, options)
+
+	_, err = taxonomy.NewFieldOptions(taxonomy.FieldTypeEnum, nil)
+	assert.Error(t, err)
+
+	_, err = taxonomy.NewFieldOptions(taxonomy.FieldTypeEnum, []string{"S", "S"})
+	assert.Error(t, err)
+
+	_, err = taxonomy.NewFieldOptions(taxonomy.FieldTypeText, []string{"S"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,5 @@
+package shared
+
+type ForgetUserCommandData struct {
+	UserID string `param:"userId" validate:"required"`
+}
@@ -0,0 +1,18 @@
+package shared
+
+type ErasureCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type ErasureReport struct {
+	UserID                 string         `json:"userId"`
+	RequestedAt            int64          `json:"requestedAt"`
+	CompletedAt            int64          `json:"completedAt"`
+	HouseholdIDs           []string       `json:"householdIds"`
+	EncryptionKeyDestroyed bool           `json:"encryptionKeyDestroyed"`
+	CacheKeysPurged        int            `json:"cacheKeysPurged"`
+	Checks                 []ErasureCheck `json:"checks"`
+	Verified               bool           `json:"verified"`
+}
@@ -0,0 +1,8 @@
+package shared
+
+type HouseholdActivityQueryData struct {
+	UserID      string `param:"userId" validate:"required"`
+	HouseholdID string `param:"householdId" validate:"required,uuid4"`
+	Cursor      string `query:"cursor" validate:"omitempty,uuid"`
+	Limit       int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
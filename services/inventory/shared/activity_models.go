@@ -0,0 +1,31 @@
+package shared
+
+const (
+	ActivityHouseholdCreated = "household_created"
+	ActivityHouseholdUpdated = "household_updated"
+	ActivityRoomAdded        = "room_added"
+	ActivityRoomUpdated      = "room_updated"
+	ActivityRoomDeleted      = "room_deleted"
+
+	ActivitySubjectHousehold = "household"
+	ActivitySubjectRoom      = "room"
+
+	DefaultActivityPageSize = 20
+)
+
+type HouseholdActivity struct {
+	ActivityID  string `json:"activityId"`
+	HouseholdID string `json:"householdId"`
+	ActorID     string `json:"actorId"`
+	Action      string `json:"action"`
+	SubjectType string `json:"subjectType"`
+	SubjectID   string `json:"subjectId"`
+	SubjectName string `json:"subjectName"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+// HouseholdActivityPage is one page of a household feed, newest first. NextCursor is empty on the last page.
+type HouseholdActivityPage struct {
+	Activities []HouseholdActivity `json:"activities"`
+	NextCursor string              `json:"nextCursor"`
+}
@@ -0,0 +1,8 @@
+package shared
+
+type AddCatalogProductCommandData struct {
+	Code     string `param:"code" validate:"required,numeric,min=8,max=13"`
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	Brand    string `json:"brand" validate:"max=50"`
+	Category string `json:"category" validate:"max=50"`
+}
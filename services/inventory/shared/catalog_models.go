@@ -0,0 +1,9 @@
+package shared
+
+type CatalogProduct struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Brand    string `json:"brand"`
+	Category string `json:"category"`
+	Source   string `json:"source"`
+}
@@ -0,0 +1,12 @@
+package shared
+
+type ImportInventoryCommandData struct {
+	UserID     string          `param:"userId" json:"-" validate:"required"`
+	Version    int             `json:"version" validate:"required,eq=1"`
+	Households []UserHousehold `json:"households" validate:"required,dive"`
+}
+
+type ExportInventoryQueryData struct {
+	UserID string `param:"userId" validate:"required"`
+	Format string `query:"format" validate:"omitempty,oneof=json csv pdf"`
+}
@@ -0,0 +1,16 @@
+package shared
+
+const InventoryArchiveVersion = 1
+
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+	ExportFormatPDF  = "pdf"
+)
+
+type InventoryArchive struct {
+	Version    int             `json:"version"`
+	UserID     string          `json:"userId"`
+	ExportedAt int64           `json:"exportedAt"`
+	Households []UserHousehold `json:"households"`
+}
@@ -2,19 +2,19 @@ package shared
 
 type UserHouseholdRoom struct {
 	HouseholdID string `json:"householdId"`
-	RoomID      string `json:"roomId"`
-	Name        string `json:"name"`
+	RoomID      string `json:"roomId" validate:"required,uuid4"`
+	Name        string `json:"name" validate:"required,min=3,max=50"`
 	Order       uint   `json:"order"`
 	Timestamp   int64  `json:"timestamp"`
 }
 
 type UserHousehold struct {
 	UserID      string              `json:"userId"`
-	HouseholdID string              `json:"householdId"`
-	Name        string              `json:"name"`
-	Location    string              `json:"location"`
-	Description string              `json:"description"`
-	Rooms       []UserHouseholdRoom `json:"rooms"`
+	HouseholdID string              `json:"householdId" validate:"required,uuid4"`
+	Name        string              `json:"name" validate:"required,min=3,max=50"`
+	Location    string              `json:"location" validate:"required,min=3,max=50"`
+	Description string              `json:"description" validate:"max=200"`
+	Rooms       []UserHouseholdRoom `json:"rooms" validate:"dive"`
 	Timestamp   int64               `json:"timestamp"`
 	Order       uint                `json:"order"`
 }
@@ -6,12 +6,42 @@ const (
 	UserHouseholdsUserIDParam      = "userId"
 	UserHouseholdsHouseholdIDParam = "householdId"
 	UserHouseholdsRoomIDParam      = "roomId"
+	UserCategoryIDParam            = "categoryId"
+	UserCategoryFieldIDParam       = "fieldId"
+	UserTagIDParam                 = "tagId"
+	UserLabelCodeParam             = "code"
+	CatalogProductCodeParam        = "code"
+
+	UserExportFormatQueryParam = "format"
 )
 
 var (
+	UserRoute = fmt.Sprintf("/user/:%s", UserHouseholdsUserIDParam)
+
 	UserHouseholdsRoute = fmt.Sprintf("/user/:%s/households", UserHouseholdsUserIDParam)
 	UserHouseholdRoute  = fmt.Sprintf("/user/:%s/households/:%s", UserHouseholdsUserIDParam, UserHouseholdsHouseholdIDParam)
 
 	UserHouseholdRoomsRoute = fmt.Sprintf("/user/:%s/households/:%s/rooms", UserHouseholdsUserIDParam, UserHouseholdsHouseholdIDParam)
 	UserHouseholdRoomRoute  = fmt.Sprintf("/user/:%s/households/:%s/rooms/:%s", UserHouseholdsUserIDParam, UserHouseholdsHouseholdIDParam, UserHouseholdsRoomIDParam)
+
+	UserHouseholdActivityRoute = fmt.Sprintf("/user/:%s/households/:%s/activity", UserHouseholdsUserIDParam, UserHouseholdsHouseholdIDParam)
+
+	UserExportRoute = fmt.Sprintf("/user/:%s/export", UserHouseholdsUserIDParam)
+	UserImportRoute = fmt.Sprintf("/user/:%s/import", UserHouseholdsUserIDParam)
+
+	UserCategoriesRoute    = fmt.Sprintf("/user/:%s/categories", UserHouseholdsUserIDParam)
+	UserCategoryRoute      = fmt.Sprintf("/user/:%s/categories/:%s", UserHouseholdsUserIDParam, UserCategoryIDParam)
+	UserCategoryMergeRoute = fmt.Sprintf("/user/:%s/categories/:%s/merge", UserHouseholdsUserIDParam, UserCategoryIDParam)
+
+	UserCategoryFieldsRoute = fmt.Sprintf("/user/:%s/categories/:%s/fields", UserHouseholdsUserIDParam, UserCategoryIDParam)
+	UserCategoryFieldRoute  = fmt.Sprintf("/user/:%s/categories/:%s/fields/:%s", UserHouseholdsUserIDParam, UserCategoryIDParam, UserCategoryFieldIDParam)
+
+	UserTagsRoute     = fmt.Sprintf("/user/:%s/tags", UserHouseholdsUserIDParam)
+	UserTagRoute      = fmt.Sprintf("/user/:%s/tags/:%s", UserHouseholdsUserIDParam, UserTagIDParam)
+	UserTagMergeRoute = fmt.Sprintf("/user/:%s/tags/:%s/merge", UserHouseholdsUserIDParam, UserTagIDParam)
+
+	UserLabelsRoute = fmt.Sprintf("/user/:%s/labels", UserHouseholdsUserIDParam)
+	UserLabelRoute  = fmt.Sprintf("/user/:%s/labels/:%s", UserHouseholdsUserIDParam, UserLabelCodeParam)
+
+	CatalogProductRoute = fmt.Sprintf("/catalog/products/:%s", CatalogProductCodeParam)
 )
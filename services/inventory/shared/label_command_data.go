@@ -0,0 +1,8 @@
+package shared
+
+type LabelSheetQueryData struct {
+	UserID      string `param:"userId" validate:"required"`
+	HouseholdID string `query:"householdId" validate:"omitempty,uuid4"`
+	Layout      string `query:"layout" validate:"omitempty,oneof=avery-l7160 avery-l7163 avery-5160"`
+	BaseURL     string `query:"baseUrl" validate:"required,http_url"`
+}
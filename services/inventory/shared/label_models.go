@@ -0,0 +1,19 @@
+package shared
+
+const (
+	LabelEntityHousehold = "household"
+	LabelEntityRoom      = "room"
+
+	LabelLayoutAveryL7160 = "avery-l7160"
+	LabelLayoutAveryL7163 = "avery-l7163"
+	LabelLayoutAvery5160  = "avery-5160"
+)
+
+// LabelTarget is the entity a printed label code points to.
+type LabelTarget struct {
+	Code        string `json:"code"`
+	UserID      string `json:"userId"`
+	EntityType  string `json:"entityType"`
+	HouseholdID string `json:"householdId"`
+	RoomID      string `json:"roomId,omitempty"`
+}
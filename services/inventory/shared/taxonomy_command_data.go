@@ -0,0 +1,64 @@
+package shared
+
+type CreateCategoryCommandData struct {
+	UserID     string `param:"userId" validate:"required"`
+	CategoryID string `json:"categoryId" validate:"required,uuid4"`
+	ParentID   string `json:"parentId" validate:"omitempty,uuid4"`
+	Name       string `json:"name" validate:"required,min=2,max=50"`
+}
+
+type RenameCategoryCommandData struct {
+	UserID     string `param:"userId" validate:"required"`
+	CategoryID string `param:"categoryId" validate:"required,uuid4"`
+	Name       string `json:"name" validate:"required,min=2,max=50"`
+}
+
+type MergeCategoryCommandData struct {
+	UserID           string `param:"userId" validate:"required"`
+	CategoryID       string `param:"categoryId" validate:"required,uuid4"`
+	TargetCategoryID string `json:"targetCategoryId" validate:"required,uuid4,nefield=CategoryID"`
+}
+
+type DeleteCategoryCommandData struct {
+	UserID     string `param:"userId" validate:"required"`
+	CategoryID string `param:"categoryId" validate:"required,uuid4"`
+}
+
+type AddCategoryFieldCommandData struct {
+	UserID     string   `param:"userId" validate:"required"`
+	CategoryID string   `param:"categoryId" validate:"required,uuid4"`
+	FieldID    string   `json:"fieldId" validate:"required,uuid4"`
+	Name       string   `json:"name" validate:"required,min=1,max=40"`
+	Type       string   `json:"type" validate:"required,oneof=text number date enum money"`
+	Options    []string `json:"options" validate:"max=50,dive,required,max=50"`
+	Required   bool     `json:"required"`
+}
+
+type RemoveCategoryFieldCommandData struct {
+	UserID     string `param:"userId" validate:"required"`
+	CategoryID string `param:"categoryId" validate:"required,uuid4"`
+	FieldID    string `param:"fieldId" validate:"required,uuid4"`
+}
+
+type CreateTagCommandData struct {
+	UserID string `param:"userId" validate:"required"`
+	TagID  string `json:"tagId" validate:"required,uuid4"`
+	Name   string `json:"name" validate:"required,min=2,max=30"`
+}
+
+type RenameTagCommandData struct {
+	UserID string `param:"userId" validate:"required"`
+	TagID  string `param:"tagId" validate:"required,uuid4"`
+	Name   string `json:"name" validate:"required,min=2,max=30"`
+}
+
+type MergeTagCommandData struct {
+	UserID      string `param:"userId" validate:"required"`
+	TagID       string `param:"tagId" validate:"required,uuid4"`
+	TargetTagID string `json:"targetTagId" validate:"required,uuid4,nefield=TagID"`
+}
+
+type DeleteTagCommandData struct {
+	UserID string `param:"userId" validate:"required"`
+	TagID  string `param:"tagId" validate:"required,uuid4"`
+}
@@ -0,0 +1,29 @@
+package shared
+
+// CategoryFieldTypes lists the kinds of values a category field can hold.
+var CategoryFieldTypes = []string{"text", "number", "date", "enum", "money"}
+
+type UserCategory struct {
+	CategoryID string `json:"categoryId"`
+	ParentID   string `json:"parentId"`
+	Name       string `json:"name"`
+	Depth      uint   `json:"depth"`
+	Timestamp  int64  `json:"timestamp"`
+
+	Fields []UserCategoryField `json:"fields"`
+}
+
+type UserCategoryField struct {
+	FieldID   string   `json:"fieldId"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Options   []string `json:"options"`
+	Required  bool     `json:"required"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+type UserTag struct {
+	TagID     string `json:"tagId"`
+	Name      string `json:"name"`
+	Timestamp int64  `json:"timestamp"`
+}
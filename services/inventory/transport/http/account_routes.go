@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net/http"
+
+	eh "github.com/cybre/home-inventory/internal/handler"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func buildAccountRoutes(e *echo.Echo, accountService AccountService, validate *validator.Validate) {
+	e.DELETE(shared.UserRoute, eh.NewValidateHandler(forgetUserHandler(accountService), validate))
+}
+
+func forgetUserHandler(accountService AccountService) eh.Handler[shared.ForgetUserCommandData] {
+	return func(c echo.Context, data shared.ForgetUserCommandData) error {
+		report, err := accountService.ForgetUser(c.Request().Context(), data)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net/http"
+
+	eh "github.com/cybre/home-inventory/internal/handler"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func buildActivityRoutes(e *echo.Echo, activityService ActivityService, validate *validator.Validate) {
+	e.GET(shared.UserHouseholdActivityRoute, eh.NewValidateHandler(getHouseholdActivityHandler(activityService), validate))
+}
+
+func getHouseholdActivityHandler(activityService ActivityService) eh.Handler[shared.HouseholdActivityQueryData] {
+	return func(c echo.Context, data shared.HouseholdActivityQueryData) error {
+		page, err := activityService.GetHouseholdActivity(c.Request().Context(), data)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, page)
+	}
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"net/http"
+
+	eh "github.com/cybre/home-inventory/internal/handler"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func buildCatalogRoutes(e *echo.Echo, catalogService CatalogService, validate *validator.Validate) {
+	e.GET(shared.CatalogProductRoute, lookupProductHandler(catalogService))
+	e.PUT(shared.CatalogProductRoute, eh.NewValidateHandler(addProductHandler(catalogService), validate))
+}
+
+func lookupProductHandler(catalogService CatalogService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		product, err := catalogService.LookupProduct(c.Request().Context(), c.Param(shared.CatalogProductCodeParam))
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, product)
+	}
+}
+
+func addProductHandler(catalogService CatalogService) eh.Handler[shared.AddCatalogProductCommandData] {
+	return func(c echo.Context, data shared.AddCatalogProductCommandData) error {
+		if err := catalogService.AddProduct(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bnkamalesh/errors"
+	eh "github.com/cybre/home-inventory/internal/handler"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func buildExportRoutes(e *echo.Echo, exportService ExportService, validate *validator.Validate) {
+	e.GET(shared.UserExportRoute, eh.NewValidateHandler(exportHandler(exportService), validate))
+	e.POST(shared.UserImportRoute, eh.NewValidateHandler(importHandler(exportService), validate))
+}
+
+func exportHandler(exportService ExportService) eh.Handler[shared.ExportInventoryQueryData] {
+	return func(c echo.Context, data shared.ExportInventoryQueryData) error {
+		userId := data.UserID
+		fileName := fmt.Sprintf("home-inventory-%s", time.Now().Format("2006-01-02"))
+
+		switch format := data.Format; format {
+		case "", shared.ExportFormatJSON:
+			archive, err := exportService.Export(c.Request().Context(), userId)
+			if err != nil {
+				return err
+			}
+
+			setAttachment(c, fileName+".json")
+			return c.JSON(http.StatusOK, archive)
+		case shared.ExportFormatCSV:
+			var buf bytes.Buffer
+			if err := exportService.ExportCSV(c.Request().Context(), userId, &buf); err != nil {
+				return err
+			}
+
+			setAttachment(c, fileName+".zip")
+			return c.Blob(http.StatusOK, "application/zip", buf.Bytes())
+		case shared.ExportFormatPDF:
+			var buf bytes.Buffer
+			if err := exportService.ExportPDF(c.Request().Context(), userId, &buf); err != nil {
+				return err
+			}
+
+			setAttachment(c, fileName+".pdf")
+			return c.Blob(http.StatusOK, "application/pdf", buf.Bytes())
+		default:
+			return errors.InputBodyf("unsupported export format: %s", format)
+		}
+	}
+}
+
+func importHandler(exportService ExportService) eh.Handler[shared.ImportInventoryCommandData] {
+	return func(c echo.Context, data shared.ImportInventoryCommandData) error {
+		if err := exportService.Import(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func setAttachment(c echo.Context, fileName string) {
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"reflect"
@@ -10,12 +11,16 @@ import (
 	"time"
 
 	"github.com/bnkamalesh/errors"
+	es "github.com/cybre/home-inventory/internal/eventsourcing"
+	"github.com/cybre/home-inventory/internal/health"
 	"github.com/cybre/home-inventory/internal/logging"
+	"github.com/cybre/home-inventory/internal/metrics"
 	"github.com/cybre/home-inventory/internal/middleware"
 	"github.com/cybre/home-inventory/services/inventory/shared"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
 type HouseholdService interface {
@@ -33,7 +38,60 @@ type HouseholdService interface {
 	GetUserHouseholdRoom(context.Context, string, string, string) (shared.UserHouseholdRoom, error)
 }
 
-func NewHTTPTransport(ctx context.Context, serverAddress string, householdService HouseholdService) error {
+type ExportService interface {
+	Export(context.Context, string) (shared.InventoryArchive, error)
+	ExportCSV(context.Context, string, io.Writer) error
+	ExportPDF(context.Context, string, io.Writer) error
+	Import(context.Context, shared.ImportInventoryCommandData) error
+}
+
+type AccountService interface {
+	ForgetUser(context.Context, shared.ForgetUserCommandData) (shared.ErasureReport, error)
+}
+
+type TaxonomyService interface {
+	CreateCategory(context.Context, shared.CreateCategoryCommandData) error
+	RenameCategory(context.Context, shared.RenameCategoryCommandData) error
+	MergeCategory(context.Context, shared.MergeCategoryCommandData) error
+	DeleteCategory(context.Context, shared.DeleteCategoryCommandData) error
+	AddCategoryField(context.Context, shared.AddCategoryFieldCommandData) error
+	RemoveCategoryField(context.Context, shared.RemoveCategoryFieldCommandData) error
+
+	CreateTag(context.Context, shared.CreateTagCommandData) error
+	RenameTag(context.Context, shared.RenameTagCommandData) error
+	MergeTag(context.Context, shared.MergeTagCommandData) error
+	DeleteTag(context.Context, shared.DeleteTagCommandData) error
+
+	GetUserCategories(context.Context, string) ([]shared.UserCategory, error)
+	GetUserTags(context.Context, string) ([]shared.UserTag, error)
+}
+
+type LabelService interface {
+	LabelSheet(context.Context, shared.LabelSheetQueryData, io.Writer) error
+	ResolveLabel(context.Context, string, string) (shared.LabelTarget, error)
+}
+
+type CatalogService interface {
+	LookupProduct(context.Context, string) (shared.CatalogProduct, error)
+	AddProduct(context.Context, shared.AddCatalogProductCommandData) error
+}
+
+type ActivityService interface {
+	GetHouseholdActivity(context.Context, shared.HouseholdActivityQueryData) (shared.HouseholdActivityPage, error)
+}
+
+// actorMiddleware records the user a request acts for, so the events it causes carry them in their metadata.
+func actorMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if userID := c.Param(shared.UserHouseholdsUserIDParam); userID != "" {
+			c.SetRequest(c.Request().WithContext(es.WithActor(c.Request().Context(), userID)))
+		}
+
+		return next(c)
+	}
+}
+
+func NewHTTPTransport(ctx context.Context, serverAddress string, healthChecker *health.Checker, householdService HouseholdService, exportService ExportService, accountService AccountService, taxonomyService TaxonomyService, labelService LabelService, catalogService CatalogService, activityService ActivityService) error {
 	e := echo.New()
 
 	e.HTTPErrorHandler = func(err error, c echo.Context) {
@@ -68,6 +126,8 @@ func NewHTTPTransport(ctx context.Context, serverAddress string, householdServic
 		return fld.Name
 	})
 
+	e.Use(otelecho.Middleware("inventory"))
+	e.Use(metrics.Middleware())
 	e.Use(middleware.RequestAndCorrelationIDLogging(logger))
 	e.Use(echomiddleware.RequestLoggerWithConfig(echomiddleware.RequestLoggerConfig{
 		LogStatus:       true,
@@ -99,8 +159,18 @@ func NewHTTPTransport(ctx context.Context, serverAddress string, householdServic
 	}))
 
 	e.Use(echomiddleware.Recover())
+	e.Use(actorMiddleware)
 
 	buildHouseholdRoutes(e, householdService, validate)
+	buildExportRoutes(e, exportService, validate)
+	buildAccountRoutes(e, accountService, validate)
+	buildTaxonomyRoutes(e, taxonomyService, validate)
+	buildLabelRoutes(e, labelService, validate)
+	buildCatalogRoutes(e, catalogService, validate)
+	buildActivityRoutes(e, activityService, validate)
+
+	e.GET(metrics.Path, metrics.Handler())
+	healthChecker.RegisterRoutes(e)
 
 	go func() {
 		if err := e.Start(serverAddress); err != nil {
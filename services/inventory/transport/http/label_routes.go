@@ -0,0 +1,39 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+
+	eh "github.com/cybre/home-inventory/internal/handler"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func buildLabelRoutes(e *echo.Echo, labelService LabelService, validate *validator.Validate) {
+	e.GET(shared.UserLabelsRoute, eh.NewValidateHandler(labelSheetHandler(labelService), validate))
+	e.GET(shared.UserLabelRoute, resolveLabelHandler(labelService))
+}
+
+func labelSheetHandler(labelService LabelService) eh.Handler[shared.LabelSheetQueryData] {
+	return func(c echo.Context, data shared.LabelSheetQueryData) error {
+		var buf bytes.Buffer
+		if err := labelService.LabelSheet(c.Request().Context(), data, &buf); err != nil {
+			return err
+		}
+
+		setAttachment(c, "home-inventory-labels.pdf")
+		return c.Blob(http.StatusOK, "application/pdf", buf.Bytes())
+	}
+}
+
+func resolveLabelHandler(labelService LabelService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		target, err := labelService.ResolveLabel(c.Request().Context(), c.Param(shared.UserHouseholdsUserIDParam), c.Param(shared.UserLabelCodeParam))
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, target)
+	}
+}
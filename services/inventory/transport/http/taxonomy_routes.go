@@ -0,0 +1,148 @@
+package http
+
+import (
+	"net/http"
+
+	eh "github.com/cybre/home-inventory/internal/handler"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+func buildTaxonomyRoutes(e *echo.Echo, taxonomyService TaxonomyService, validate *validator.Validate) {
+	e.GET(shared.UserCategoriesRoute, getUserCategoriesHandler(taxonomyService))
+	e.POST(shared.UserCategoriesRoute, eh.NewValidateHandler(createCategoryHandler(taxonomyService), validate))
+	e.PUT(shared.UserCategoryRoute, eh.NewValidateHandler(renameCategoryHandler(taxonomyService), validate))
+	e.DELETE(shared.UserCategoryRoute, eh.NewValidateHandler(deleteCategoryHandler(taxonomyService), validate))
+	e.POST(shared.UserCategoryMergeRoute, eh.NewValidateHandler(mergeCategoryHandler(taxonomyService), validate))
+	e.POST(shared.UserCategoryFieldsRoute, eh.NewValidateHandler(addCategoryFieldHandler(taxonomyService), validate))
+	e.DELETE(shared.UserCategoryFieldRoute, eh.NewValidateHandler(removeCategoryFieldHandler(taxonomyService), validate))
+
+	e.GET(shared.UserTagsRoute, getUserTagsHandler(taxonomyService))
+	e.POST(shared.UserTagsRoute, eh.NewValidateHandler(createTagHandler(taxonomyService), validate))
+	e.PUT(shared.UserTagRoute, eh.NewValidateHandler(renameTagHandler(taxonomyService), validate))
+	e.DELETE(shared.UserTagRoute, eh.NewValidateHandler(deleteTagHandler(taxonomyService), validate))
+	e.POST(shared.UserTagMergeRoute, eh.NewValidateHandler(mergeTagHandler(taxonomyService), validate))
+}
+
+func getUserCategoriesHandler(taxonomyService TaxonomyService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		categories, err := taxonomyService.GetUserCategories(c.Request().Context(), c.Param(shared.UserHouseholdsUserIDParam))
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, categories)
+	}
+}
+
+func createCategoryHandler(taxonomyService TaxonomyService) eh.Handler[shared.CreateCategoryCommandData] {
+	return func(c echo.Context, data shared.CreateCategoryCommandData) error {
+		if err := taxonomyService.CreateCategory(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusCreated)
+	}
+}
+
+func renameCategoryHandler(taxonomyService TaxonomyService) eh.Handler[shared.RenameCategoryCommandData] {
+	return func(c echo.Context, data shared.RenameCategoryCommandData) error {
+		if err := taxonomyService.RenameCategory(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func mergeCategoryHandler(taxonomyService TaxonomyService) eh.Handler[shared.MergeCategoryCommandData] {
+	return func(c echo.Context, data shared.MergeCategoryCommandData) error {
+		if err := taxonomyService.MergeCategory(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func deleteCategoryHandler(taxonomyService TaxonomyService) eh.Handler[shared.DeleteCategoryCommandData] {
+	return func(c echo.Context, data shared.DeleteCategoryCommandData) error {
+		if err := taxonomyService.DeleteCategory(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func addCategoryFieldHandler(taxonomyService TaxonomyService) eh.Handler[shared.AddCategoryFieldCommandData] {
+	return func(c echo.Context, data shared.AddCategoryFieldCommandData) error {
+		if err := taxonomyService.AddCategoryField(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusCreated)
+	}
+}
+
+func removeCategoryFieldHandler(taxonomyService TaxonomyService) eh.Handler[shared.RemoveCategoryFieldCommandData] {
+	return func(c echo.Context, data shared.RemoveCategoryFieldCommandData) error {
+		if err := taxonomyService.RemoveCategoryField(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func getUserTagsHandler(taxonomyService TaxonomyService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tags, err := taxonomyService.GetUserTags(c.Request().Context(), c.Param(shared.UserHouseholdsUserIDParam))
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, tags)
+	}
+}
+
+func createTagHandler(taxonomyService TaxonomyService) eh.Handler[shared.CreateTagCommandData] {
+	return func(c echo.Context, data shared.CreateTagCommandData) error {
+		if err := taxonomyService.CreateTag(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusCreated)
+	}
+}
+
+func renameTagHandler(taxonomyService TaxonomyService) eh.Handler[shared.RenameTagCommandData] {
+	return func(c echo.Context, data shared.RenameTagCommandData) error {
+		if err := taxonomyService.RenameTag(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func mergeTagHandler(taxonomyService TaxonomyService) eh.Handler[shared.MergeTagCommandData] {
+	return func(c echo.Context, data shared.MergeTagCommandData) error {
+		if err := taxonomyService.MergeTag(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func deleteTagHandler(taxonomyService TaxonomyService) eh.Handler[shared.DeleteTagCommandData] {
+	return func(c echo.Context, data shared.DeleteTagCommandData) error {
+		if err := taxonomyService.DeleteTag(c.Request().Context(), data); err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
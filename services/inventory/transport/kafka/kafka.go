@@ -4,11 +4,26 @@ import (
 	"context"
 
 	"github.com/cybre/home-inventory/internal/infrastructure"
+	"github.com/cybre/home-inventory/services/inventory/app/activity"
 	"github.com/cybre/home-inventory/services/inventory/app/household"
+	"github.com/cybre/home-inventory/services/inventory/app/taxonomy"
 )
 
-func NewKafkaTransport(ctx context.Context, eventMessaging *infrastructure.KafkaEventMessaging, userHouseholdRepository *household.UserHouseholdRepository) error {
-	if err := eventMessaging.ConsumeEvents(ctx, household.NewUserHouseholdProjector(userHouseholdRepository)); err != nil {
+// EventConsumer delivers events to handlers, either from Kafka or, in single-node mode, in process.
+type EventConsumer interface {
+	ConsumeEvents(ctx context.Context, handler infrastructure.EventHandler) error
+}
+
+func NewKafkaTransport(ctx context.Context, eventConsumer EventConsumer, userHouseholdRepository household.HouseholdRepo, userTaxonomyRepository taxonomy.TaxonomyRepo, householdActivityRepository activity.ActivityRepo) error {
+	if err := eventConsumer.ConsumeEvents(ctx, household.NewUserHouseholdProjector(userHouseholdRepository)); err != nil {
+		panic(err)
+	}
+
+	if err := eventConsumer.ConsumeEvents(ctx, taxonomy.NewUserTaxonomyProjector(userTaxonomyRepository)); err != nil {
+		panic(err)
+	}
+
+	if err := eventConsumer.ConsumeEvents(ctx, activity.NewHouseholdActivityProjector(householdActivityRepository)); err != nil {
 		panic(err)
 	}
 
@@ -6,11 +6,14 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/cybre/home-inventory/internal/authenticator"
 	internalcache "github.com/cybre/home-inventory/internal/cache"
+	"github.com/cybre/home-inventory/internal/health"
 	"github.com/cybre/home-inventory/internal/logging"
+	"github.com/cybre/home-inventory/internal/metrics"
 	"github.com/cybre/home-inventory/internal/middleware"
 	"github.com/cybre/home-inventory/internal/utils"
 	inventoryclient "github.com/cybre/home-inventory/services/inventory/client"
@@ -24,9 +27,17 @@ import (
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
-func New(ctx context.Context, serverAddress string, logger *slog.Logger) error {
+// New serves the web app on serverAddress until ctx is done. Metrics are served on metricsAddress instead, which is
+// meant to be reachable only from inside the deployment; without one they are not served. publicBaseURL is the address
+// the web app is reached at, which printed labels link to.
+func New(ctx context.Context, serverAddress, metricsAddress, publicBaseURL string, logger *slog.Logger) error {
+	if publicBaseURL == "" {
+		return fmt.Errorf("public base URL is not configured")
+	}
+
 	e := echo.New()
 	e.HTTPErrorHandler = func(err error, c echo.Context) {
 		te, ok := err.(toast.Toast)
@@ -58,13 +69,24 @@ func New(ctx context.Context, serverAddress string, logger *slog.Logger) error {
 		return fmt.Errorf("failed to create authenticator: %w", err)
 	}
 
-	redisStore := redis_store.NewRedis(redis.NewClient(&redis.Options{
+	redisClient := redis.NewClient(&redis.Options{
 		Addr: os.Getenv("REDIS_CLIENT_CACHE_ADDRRESS"),
-	}))
+	})
+	redisStore := redis_store.NewRedis(redisClient)
 	cacheManager := cache.New[string](redisStore)
 	cache := internalcache.New(cacheManager, 2*time.Minute)
 	inventoryClient := inventoryclient.New(os.Getenv("INVENTORY_API"), cache)
 
+	healthChecker := health.NewChecker()
+	healthChecker.AddReadinessCheck("redis", func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	})
+	healthChecker.AddReadinessCheck("inventory_api", inventoryClient.CheckHealth)
+
+	e.Use(otelecho.Middleware("web", otelecho.WithSkipper(func(c echo.Context) bool {
+		return strings.HasPrefix(c.Path(), "/static")
+	})))
+	e.Use(metrics.Middleware())
 	e.Use(middleware.RequestAndCorrelationIDLogging(logger))
 	e.Use(echomiddleware.RequestLoggerWithConfig(echomiddleware.RequestLoggerConfig{
 		LogStatus:   true,
@@ -101,26 +123,40 @@ func New(ctx context.Context, serverAddress string, logger *slog.Logger) error {
 	e.Use(session.Middleware(sessions.NewCookieStore([]byte("secret"))))
 
 	e.Static("/static", "static")
+	healthChecker.RegisterRoutes(e)
 
-	routes.Initialize(e, authenticator, inventoryClient)
+	routes.Initialize(e, authenticator, inventoryClient, publicBaseURL)
 
-	go func() {
-		if err := e.Start(serverAddress); err != nil {
-			if err == http.ErrServerClosed {
-				return
-			}
+	servers := []*echo.Echo{e}
+	go start(e, serverAddress)
 
-			panic(err)
-		}
-	}()
+	if metricsAddress != "" {
+		metricsServer := echo.New()
+		metricsServer.HideBanner = true
+		metricsServer.GET(metrics.Path, metrics.Handler())
+		servers = append(servers, metricsServer)
+		go start(metricsServer, metricsAddress)
+	}
 
 	<-ctx.Done()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	if err := e.Shutdown(ctx); err != nil {
-		return fmt.Errorf("failed to shutdown server: %w", err)
+	for _, server := range servers {
+		if err := server.Shutdown(ctx); err != nil {
+			return fmt.Errorf("failed to shutdown server: %w", err)
+		}
 	}
 
 	return nil
 }
+
+func start(e *echo.Echo, address string) {
+	if err := e.Start(address); err != nil {
+		if err == http.ErrServerClosed {
+			return
+		}
+
+		panic(err)
+	}
+}
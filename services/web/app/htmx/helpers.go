@@ -10,6 +10,10 @@ func ReplaceUrl(e echo.Context, url string) {
 	e.Response().Header().Add("HX-Replace-Url", url)
 }
 
+func Location(e echo.Context, url string) {
+	e.Response().Header().Set("HX-Location", url)
+}
+
 func IsHTMXHistoryRestoreRequest(e echo.Context) bool {
 	return e.Request().Header.Get("HX-History-Restore-Request") == "true"
 }
@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/cybre/home-inventory/services/web/app/auth"
+	"github.com/cybre/home-inventory/services/web/app/helpers"
+	"github.com/cybre/home-inventory/services/web/app/htmx"
+	"github.com/cybre/home-inventory/services/web/app/toast"
+	"github.com/labstack/echo-contrib/session"
+	"github.com/labstack/echo/v4"
+)
+
+func deleteAccountViewHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.Render(http.StatusOK, "account_delete", map[string]interface{}{
+			"Title":            "Delete Account",
+			"InvalidDeleteKey": c.QueryParam("invalidKey") == "true",
+		})
+	}
+}
+
+type UserForgetter interface {
+	ForgetUser(ctx context.Context, userID string) (shared.ErasureReport, error)
+}
+
+func deleteAccountHandler(userForgetter UserForgetter) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, ok := helpers.GetUser(c)
+		if !ok {
+			return fmt.Errorf("user not found")
+		}
+
+		deleteKey := c.FormValue("delete")
+		if deleteKey != "delete" {
+			if htmx.IsHTMXRequest(c) {
+				return toast.Error("Please enter the text exactly as shown to confirm")
+			}
+
+			return c.Redirect(http.StatusFound, "/account/delete?invalidKey=true")
+		}
+
+		report, err := userForgetter.ForgetUser(c.Request().Context(), user.ID)
+		if err != nil {
+			return err
+		}
+
+		sess, err := session.Get(auth.AuthSessionCookieName, c)
+		if err != nil {
+			return fmt.Errorf("failed to get auth session: %w", err)
+		}
+
+		delete(sess.Values, auth.AuthSessionProfileKey)
+		delete(sess.Values, auth.AuthSessionAccessTokenKey)
+		if err := sess.Save(c.Request(), c.Response()); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal erasure report: %w", err)
+		}
+
+		return c.Render(http.StatusOK, "erasure_report", map[string]interface{}{
+			"Title":      "Account Deleted",
+			"Report":     report,
+			"ReportJSON": string(reportJSON),
+		})
+	}
+}
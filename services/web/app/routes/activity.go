@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/cybre/home-inventory/services/web/app/helpers"
+	"github.com/cybre/home-inventory/services/web/app/htmx"
+	"github.com/labstack/echo/v4"
+)
+
+type HouseholdActivityGetter interface {
+	HouseholdGetter
+	GetHouseholdActivity(ctx context.Context, userID, householdID, cursor string) (shared.HouseholdActivityPage, error)
+}
+
+// householdActivityHandler renders the household feed. Further pages are requested by htmx as the end
+// of the feed scrolls into view and are rendered on their own.
+func householdActivityHandler(activityGetter HouseholdActivityGetter) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, ok := helpers.GetUser(c)
+		if !ok {
+			return fmt.Errorf("user not found")
+		}
+
+		householdID := c.Param("householdId")
+		page, err := activityGetter.GetHouseholdActivity(c.Request().Context(), user.ID, householdID, c.QueryParam("cursor"))
+		if err != nil {
+			return err
+		}
+
+		feed := map[string]interface{}{
+			"UserID":      user.ID,
+			"HouseholdID": householdID,
+			"Page":        page,
+		}
+
+		if htmx.ShouldReturnPartial(c) {
+			return c.Render(http.StatusOK, "activity_items", feed)
+		}
+
+		household, err := activityGetter.GetUserHousehold(c.Request().Context(), user.ID, householdID)
+		if err != nil {
+			return err
+		}
+
+		return c.Render(http.StatusOK, "activity", map[string]interface{}{
+			"Title":     "Activity",
+			"Household": household,
+			"Feed":      feed,
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/cybre/home-inventory/services/inventory/client"
+	"github.com/cybre/home-inventory/services/web/app/helpers"
+	"github.com/cybre/home-inventory/services/web/app/htmx"
+	"github.com/cybre/home-inventory/services/web/app/toast"
+	"github.com/labstack/echo/v4"
+)
+
+const maxImportArchiveSize = 10 << 20
+
+type InventoryExporter interface {
+	ExportInventory(ctx context.Context, userID, format string) (client.ExportedInventory, error)
+}
+
+func exportHandler(inventoryExporter InventoryExporter) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, ok := helpers.GetUser(c)
+		if !ok {
+			return fmt.Errorf("user not found")
+		}
+
+		exported, err := inventoryExporter.ExportInventory(c.Request().Context(), user.ID, c.QueryParam("format"))
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, exported.ContentDisposition)
+
+		return c.Blob(http.StatusOK, exported.ContentType, exported.Body)
+	}
+}
+
+func importViewHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.Render(http.StatusOK, "import", map[string]interface{}{"Title": "Import"})
+	}
+}
+
+type InventoryImporter interface {
+	ImportInventory(ctx context.Context, userID string, archive json.RawMessage) error
+}
+
+func importHandler(inventoryImporter InventoryImporter) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, ok := helpers.GetUser(c)
+		if !ok {
+			return fmt.Errorf("user not found")
+		}
+
+		fileHeader, err := c.FormFile("archive")
+		if err != nil {
+			return toast.Error("Please select an export file to import")
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open uploaded archive: %w", err)
+		}
+		defer file.Close()
+
+		archive, err := io.ReadAll(io.LimitReader(file, maxImportArchiveSize))
+		if err != nil {
+			return fmt.Errorf("failed to read uploaded archive: %w", err)
+		}
+
+		if !json.Valid(archive) {
+			return toast.Error("The selected file is not a valid JSON export")
+		}
+
+		if err := inventoryImporter.ImportInventory(c.Request().Context(), user.ID, archive); err != nil {
+			return err
+		}
+
+		if htmx.IsHTMXRequest(c) {
+			toast.Success(c, "Inventory has been imported successfully")
+			htmx.Location(c, "/")
+			return c.NoContent(http.StatusOK)
+		}
+
+		return c.Redirect(http.StatusFound, "/")
+	}
+}
@@ -0,0 +1,78 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cybre/home-inventory/services/inventory/client"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/cybre/home-inventory/services/web/app/helpers"
+	"github.com/labstack/echo/v4"
+)
+
+func labelsViewHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.Render(http.StatusOK, "labels", map[string]interface{}{
+			"Title": "Print Labels",
+			"Layouts": []string{
+				shared.LabelLayoutAveryL7160,
+				shared.LabelLayoutAveryL7163,
+				shared.LabelLayoutAvery5160,
+			},
+		})
+	}
+}
+
+type LabelSheetGetter interface {
+	GetLabelSheet(ctx context.Context, request client.LabelSheetRequest) (client.LabelSheet, error)
+}
+
+// labelSheetHandler serves the label PDF. Printed links lead to baseURL rather than the Host header, which the client controls.
+func labelSheetHandler(labelSheetGetter LabelSheetGetter, baseURL string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, ok := helpers.GetUser(c)
+		if !ok {
+			return fmt.Errorf("user not found")
+		}
+
+		sheet, err := labelSheetGetter.GetLabelSheet(c.Request().Context(), client.LabelSheetRequest{
+			UserID:      user.ID,
+			HouseholdID: c.QueryParam("householdId"),
+			Layout:      c.QueryParam("layout"),
+			BaseURL:     baseURL,
+		})
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, sheet.ContentDisposition)
+
+		return c.Blob(http.StatusOK, "application/pdf", sheet.Body)
+	}
+}
+
+type LabelResolver interface {
+	ResolveLabel(ctx context.Context, userID, code string) (shared.LabelTarget, error)
+}
+
+// scanHandler is where scanned label QR codes lead. It redirects to the labelled household or room.
+func scanHandler(labelResolver LabelResolver) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, ok := helpers.GetUser(c)
+		if !ok {
+			return fmt.Errorf("user not found")
+		}
+
+		target, err := labelResolver.ResolveLabel(c.Request().Context(), user.ID, c.Param("code"))
+		if err != nil {
+			return err
+		}
+
+		if target.EntityType == shared.LabelEntityRoom {
+			return c.Redirect(http.StatusFound, "/#room-"+target.RoomID)
+		}
+
+		return c.Redirect(http.StatusFound, "/#household-"+target.HouseholdID)
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func Initialize(e *echo.Echo, authenticator *authenticator.Authenticator, inventoryClient *inventoryclient.InventoryClient) {
+func Initialize(e *echo.Echo, authenticator *authenticator.Authenticator, inventoryClient *inventoryclient.InventoryClient, publicBaseURL string) {
 	e.GET("/", homeHandler(), mustHaveHousehold(inventoryClient))
 	e.GET("/households", homeHandler(), mustHaveHousehold(inventoryClient))
 	e.GET("/login", loginHandler(authenticator))
@@ -25,9 +25,33 @@ func Initialize(e *echo.Echo, authenticator *authenticator.Authenticator, invent
 		return c.Render(http.StatusOK, "onboarding_create_household", map[string]interface{}{"Title": "Onboarding"})
 	}, auth.IsAuthenticated, mustNotHaveHousehold(inventoryClient))
 
+	e.GET("/export", exportHandler(inventoryClient), auth.IsAuthenticated)
+	e.GET("/import", importViewHandler(), auth.IsAuthenticated)
+	e.POST("/import", importHandler(inventoryClient), auth.IsAuthenticated)
+
+	e.GET("/account/delete", deleteAccountViewHandler(), auth.IsAuthenticated)
+	e.POST("/account/delete", deleteAccountHandler(inventoryClient), auth.IsAuthenticated)
+
+	e.GET("/labels", labelsViewHandler(), auth.IsAuthenticated, mustHaveHousehold(inventoryClient))
+	e.GET("/labels/sheet", labelSheetHandler(inventoryClient, publicBaseURL), auth.IsAuthenticated)
+	e.GET("/scan/:code", scanHandler(inventoryClient), auth.IsAuthenticated)
+
+	e.GET("/categories", taxonomyViewHandler(inventoryClient), auth.IsAuthenticated)
+	e.POST("/categories", createCategoryHandler(inventoryClient), auth.IsAuthenticated)
+	e.POST("/categories/:categoryId/rename", renameCategoryHandler(inventoryClient), auth.IsAuthenticated)
+	e.POST("/categories/:categoryId/merge", mergeCategoryHandler(inventoryClient), auth.IsAuthenticated)
+	e.POST("/categories/:categoryId/delete", deleteCategoryHandler(inventoryClient), auth.IsAuthenticated)
+	e.POST("/categories/:categoryId/fields", addCategoryFieldHandler(inventoryClient), auth.IsAuthenticated)
+	e.POST("/categories/:categoryId/fields/:fieldId/delete", removeCategoryFieldHandler(inventoryClient), auth.IsAuthenticated)
+	e.POST("/tags", createTagHandler(inventoryClient), auth.IsAuthenticated)
+	e.POST("/tags/:tagId/rename", renameTagHandler(inventoryClient), auth.IsAuthenticated)
+	e.POST("/tags/:tagId/merge", mergeTagHandler(inventoryClient), auth.IsAuthenticated)
+	e.POST("/tags/:tagId/delete", deleteTagHandler(inventoryClient), auth.IsAuthenticated)
+
 	e.GET("/households/create", createHouseholdViewHandler(), auth.IsAuthenticated, mustHaveHousehold(inventoryClient))
 	e.POST("/households/create", createHouseholdHandler(inventoryClient), auth.IsAuthenticated)
 	e.GET("/households/:householdId", getHouseholdHandler(inventoryClient), auth.IsAuthenticated, mustHaveHousehold(inventoryClient))
+	e.GET("/households/:householdId/activity", householdActivityHandler(inventoryClient), auth.IsAuthenticated, mustHaveHousehold(inventoryClient))
 	e.GET("/households/:householdId/edit", editHouseholdViewHandler(inventoryClient), auth.IsAuthenticated, mustHaveHousehold(inventoryClient))
 	e.POST("/households/:householdId/edit", editHouseholdHandler(inventoryClient), auth.IsAuthenticated, mustHaveHousehold(inventoryClient))
 	e.GET("/households/:householdId/delete", deleteHouseholdViewHandler(), auth.IsAuthenticated, mustHaveHousehold(inventoryClient))
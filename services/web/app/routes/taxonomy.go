@@ -0,0 +1,185 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cybre/home-inventory/services/inventory/client"
+	"github.com/cybre/home-inventory/services/inventory/shared"
+	"github.com/cybre/home-inventory/services/web/app/helpers"
+	"github.com/cybre/home-inventory/services/web/app/htmx"
+	"github.com/cybre/home-inventory/services/web/app/toast"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const taxonomyPath = "/categories"
+
+type TaxonomyGetter interface {
+	GetUserCategories(ctx context.Context, userID string) ([]shared.UserCategory, error)
+	GetUserTags(ctx context.Context, userID string) ([]shared.UserTag, error)
+}
+
+func taxonomyViewHandler(taxonomyGetter TaxonomyGetter) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, ok := helpers.GetUser(c)
+		if !ok {
+			return fmt.Errorf("user not found")
+		}
+
+		categories, err := taxonomyGetter.GetUserCategories(c.Request().Context(), user.ID)
+		if err != nil {
+			return err
+		}
+
+		tags, err := taxonomyGetter.GetUserTags(c.Request().Context(), user.ID)
+		if err != nil {
+			return err
+		}
+
+		return c.Render(http.StatusOK, "taxonomy", map[string]interface{}{
+			"Title":      "Categories & Tags",
+			"Categories": categories,
+			"Tags":       tags,
+			"FieldTypes": shared.CategoryFieldTypes,
+		})
+	}
+}
+
+type TaxonomyManager interface {
+	CreateCategory(ctx context.Context, category client.CreateCategoryRequest) error
+	RenameCategory(ctx context.Context, category client.RenameCategoryRequest) error
+	MergeCategory(ctx context.Context, category client.MergeCategoryRequest) error
+	DeleteCategory(ctx context.Context, userID, categoryID string) error
+	AddCategoryField(ctx context.Context, field client.AddCategoryFieldRequest) error
+	RemoveCategoryField(ctx context.Context, userID, categoryID, fieldID string) error
+
+	CreateTag(ctx context.Context, tag client.CreateTagRequest) error
+	RenameTag(ctx context.Context, tag client.RenameTagRequest) error
+	MergeTag(ctx context.Context, tag client.MergeTagRequest) error
+	DeleteTag(ctx context.Context, userID, tagID string) error
+}
+
+// taxonomyAction wraps a category or tag mutation, returning to the management page once it succeeds.
+func taxonomyAction(successMessage string, action func(c echo.Context, userID string) error) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, ok := helpers.GetUser(c)
+		if !ok {
+			return fmt.Errorf("user not found")
+		}
+
+		if err := action(c, user.ID); err != nil {
+			return err
+		}
+
+		if htmx.IsHTMXRequest(c) {
+			toast.Success(c, successMessage)
+			htmx.Location(c, taxonomyPath)
+			return c.NoContent(http.StatusOK)
+		}
+
+		return c.Redirect(http.StatusFound, taxonomyPath)
+	}
+}
+
+func createCategoryHandler(taxonomyManager TaxonomyManager) echo.HandlerFunc {
+	return taxonomyAction("Category has been created successfully", func(c echo.Context, userID string) error {
+		return taxonomyManager.CreateCategory(c.Request().Context(), client.CreateCategoryRequest{
+			UserID:     userID,
+			CategoryID: uuid.NewString(),
+			ParentID:   c.FormValue("parentId"),
+			Name:       c.FormValue("name"),
+		})
+	})
+}
+
+func renameCategoryHandler(taxonomyManager TaxonomyManager) echo.HandlerFunc {
+	return taxonomyAction("Category has been renamed successfully", func(c echo.Context, userID string) error {
+		return taxonomyManager.RenameCategory(c.Request().Context(), client.RenameCategoryRequest{
+			UserID:     userID,
+			CategoryID: c.Param("categoryId"),
+			Name:       c.FormValue("name"),
+		})
+	})
+}
+
+func mergeCategoryHandler(taxonomyManager TaxonomyManager) echo.HandlerFunc {
+	return taxonomyAction("Categories have been merged successfully", func(c echo.Context, userID string) error {
+		return taxonomyManager.MergeCategory(c.Request().Context(), client.MergeCategoryRequest{
+			UserID:           userID,
+			CategoryID:       c.Param("categoryId"),
+			TargetCategoryID: c.FormValue("targetCategoryId"),
+		})
+	})
+}
+
+func deleteCategoryHandler(taxonomyManager TaxonomyManager) echo.HandlerFunc {
+	return taxonomyAction("Category has been deleted successfully", func(c echo.Context, userID string) error {
+		return taxonomyManager.DeleteCategory(c.Request().Context(), userID, c.Param("categoryId"))
+	})
+}
+
+func addCategoryFieldHandler(taxonomyManager TaxonomyManager) echo.HandlerFunc {
+	return taxonomyAction("Field has been added successfully", func(c echo.Context, userID string) error {
+		var options []string
+		for _, option := range strings.Split(c.FormValue("options"), "\n") {
+			if option = strings.TrimSpace(option); option != "" {
+				options = append(options, option)
+			}
+		}
+
+		return taxonomyManager.AddCategoryField(c.Request().Context(), client.AddCategoryFieldRequest{
+			UserID:     userID,
+			CategoryID: c.Param("categoryId"),
+			FieldID:    uuid.NewString(),
+			Name:       c.FormValue("name"),
+			Type:       c.FormValue("type"),
+			Options:    options,
+			Required:   c.FormValue("required") != "",
+		})
+	})
+}
+
+func removeCategoryFieldHandler(taxonomyManager TaxonomyManager) echo.HandlerFunc {
+	return taxonomyAction("Field has been removed successfully", func(c echo.Context, userID string) error {
+		return taxonomyManager.RemoveCategoryField(c.Request().Context(), userID, c.Param("categoryId"), c.Param("fieldId"))
+	})
+}
+
+func createTagHandler(taxonomyManager TaxonomyManager) echo.HandlerFunc {
+	return taxonomyAction("Tag has been created successfully", func(c echo.Context, userID string) error {
+		return taxonomyManager.CreateTag(c.Request().Context(), client.CreateTagRequest{
+			UserID: userID,
+			TagID:  uuid.NewString(),
+			Name:   c.FormValue("name"),
+		})
+	})
+}
+
+func renameTagHandler(taxonomyManager TaxonomyManager) echo.HandlerFunc {
+	return taxonomyAction("Tag has been renamed successfully", func(c echo.Context, userID string) error {
+		return taxonomyManager.RenameTag(c.Request().Context(), client.RenameTagRequest{
+			UserID: userID,
+			TagID:  c.Param("tagId"),
+			Name:   c.FormValue("name"),
+		})
+	})
+}
+
+func mergeTagHandler(taxonomyManager TaxonomyManager) echo.HandlerFunc {
+	return taxonomyAction("Tags have been merged successfully", func(c echo.Context, userID string) error {
+		return taxonomyManager.MergeTag(c.Request().Context(), client.MergeTagRequest{
+			UserID:      userID,
+			TagID:       c.Param("tagId"),
+			TargetTagID: c.FormValue("targetTagId"),
+		})
+	})
+}
+
+func deleteTagHandler(taxonomyManager TaxonomyManager) echo.HandlerFunc {
+	return taxonomyAction("Tag has been deleted successfully", func(c echo.Context, userID string) error {
+		return taxonomyManager.DeleteTag(c.Request().Context(), userID, c.Param("tagId"))
+	})
+}
@@ -55,6 +55,9 @@ func New() *Renderer {
 				"timestamp": func() int64 {
 					return time.Now().UnixMilli()
 				},
+				"datetime": func(millis int64) string {
+					return time.UnixMilli(millis).UTC().Format("Jan 2, 2006 15:04")
+				},
 			},
 		},
 	})